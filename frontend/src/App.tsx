@@ -7,6 +7,8 @@ import * as TranslationsAPI from '../wailsjs/go/app/TranslationsAPI'
 import * as JobsAPI from '../wailsjs/go/app/JobsAPI'
 import * as ProviderAPI from '../wailsjs/go/app/ProviderAPI'
 import * as ExportAPI from '../wailsjs/go/app/ExportAPI'
+import * as SecretsAPI from '../wailsjs/go/app/SecretsAPI'
+import { app, domain, jobs } from '../wailsjs/go/models'
 import TranslationRow from './components/TranslationRow'
 import NewProjectModal from './components/NewProjectModal'
 import EditProjectModal from './components/EditProjectModal'
@@ -19,6 +21,9 @@ import { Pencil, Trash2, RefreshCw, UploadCloud, Plus, FileDiff, Download, Circl
 import { Progress } from './components/ui/progress'
 import ProviderDropdown from './components/ProviderDropdown'
 import GeneralSettings from './components/GeneralSettings'
+import UnlockModal from './components/UnlockModal'
+import EstimateModal from './components/EstimateModal'
+import { isLockedError } from './lib/secrets'
 
 type ProjectRecord = {
   id: number
@@ -208,6 +213,53 @@ function App() {
     }
   }, [jobProgress.jobId])
 
+  const [secretsStatus, setSecretsStatus] = useState<app.SecretsStatus | null>(null)
+  const [unlockOpen, setUnlockOpen] = useState(false)
+  const unlockWaiters = useRef<Array<(ok: boolean) => void>>([])
+
+  // requestUnlock opens the passphrase prompt and resolves once it is unlocked or dismissed.
+  const requestUnlock = useCallback(() => new Promise<boolean>(resolve => {
+    unlockWaiters.current.push(resolve)
+    setUnlockOpen(true)
+  }), [])
+
+  const finishUnlock = useCallback((ok: boolean) => {
+    setUnlockOpen(false)
+    const waiters = unlockWaiters.current
+    unlockWaiters.current = []
+    waiters.forEach(resolve => resolve(ok))
+  }, [])
+
+  // withUnlock runs fn; when it fails because the API keys are locked it asks for the
+  // passphrase and retries once.
+  const withUnlock = useCallback(async <T,>(fn: () => Promise<T>): Promise<T> => {
+    try {
+      return await fn()
+    } catch (e) {
+      if (!isLockedError(e) || !(await requestUnlock())) throw e
+      return await fn()
+    }
+  }, [requestUnlock])
+
+  const [estimateState, setEstimateState] = useState<{ estimate: jobs.Estimate; allowBudget: boolean; resolve: (budget: number | null) => void } | null>(null)
+
+  // confirmEstimate shows a job's estimate and resolves to the chosen budget, or null when
+  // the user cancels.
+  const confirmEstimate = useCallback((estimate: jobs.Estimate, allowBudget: boolean) => new Promise<number | null>(resolve => {
+    setEstimateState({ estimate, allowBudget, resolve })
+  }), [])
+
+  const closeEstimate = useCallback((budget: number | null) => {
+    estimateState?.resolve(budget)
+    setEstimateState(null)
+  }, [estimateState])
+
+  // failure describes an error for the status bar.
+  const failure = useCallback((what: string, e: any) => {
+    console.error(e)
+    return isLockedError(e) ? `${what}: API keys are locked.` : `${what}: ${String(e?.message || e)}`
+  }, [])
+
   const importInputRef = useRef<HTMLInputElement | null>(null)
   const searchInputRef = useRef<HTMLInputElement | null>(null)
 
@@ -447,6 +499,30 @@ function App() {
     loadProviders()
   }, [wailsReady, loadProjects, loadProviders])
 
+  // Ask for the passphrase at startup when stored keys need it.
+  useEffect(() => {
+    if (!wailsReady) return
+    SecretsAPI.Status().then(st => {
+      setSecretsStatus(st)
+      if (st.mode === 'passphrase' && st.locked && (st.initialized || st.plaintext > 0)) setUnlockOpen(true)
+    }).catch(console.error)
+  }, [wailsReady])
+
+  const handleUnlocked = useCallback((st: app.SecretsStatus) => {
+    setSecretsStatus(st)
+    setStatus('API keys unlocked.')
+    finishUnlock(true)
+    loadProviders()
+  }, [finishUnlock, loadProviders])
+
+  const handleLock = useCallback(async () => {
+    try {
+      setSecretsStatus(await SecretsAPI.Lock())
+      setStatus('API keys locked.')
+      await loadProviders()
+    } catch (e) { setStatus(failure('Failed to lock API keys', e)) }
+  }, [failure, loadProviders])
+
   useEffect(() => {
     if (selectedProjectId != null) {
       loadFiles(selectedProjectId)
@@ -646,21 +722,28 @@ function App() {
       setStatus('Select at least one row to translate.')
       return
     }
+    const req = {
+      project_id: selectedProjectId,
+      provider_id: providerSettings.providerId,
+      unit_ids: unitIds,
+      locales,
+      model: providerSettings.model,
+      force: true,
+    }
     try {
+      const estimate = await withUnlock(() => JobsAPI.EstimateTranslateUnits(app.StartTranslateUnitsRequest.createFrom(req)))
+      const budget = await confirmEstimate(estimate, true)
+      if (budget == null) {
+        setStatus('Translation canceled.')
+        return
+      }
       // If user intentionally cleared drafts, clear stored translations first
       const toClear = entries.filter(e => selection.has(e.unitId) && (e.draft || '').trim() === '')
       for (const e of toClear) {
         try { await (TranslationsAPI as any).Upsert({ unit_id: e.unitId, locale: targetLang, text: '', status: 'draft' }) } catch {}
       }
-      const res = await (JobsAPI as any).StartTranslateUnits({
-        project_id: selectedProjectId,
-        provider_id: providerSettings.providerId,
-        unit_ids: unitIds,
-        locales,
-        model: providerSettings.model,
-        force: true,
-      })
-      const jobId = res?.job_id ?? res?.JobID
+      const res = await withUnlock(() => JobsAPI.StartTranslateUnits(app.StartTranslateUnitsRequest.createFrom({ ...req, budget })))
+      const jobId = res?.job_id
       if (jobId) {
         setJobProgress({ jobId, done: 0, total: unitIds.length * locales.length, status: 'running', model: providerSettings.model })
         setStatus(`Queued re-translate for ${unitIds.length} item${unitIds.length === 1 ? '' : 's'} (force).`)
@@ -668,10 +751,9 @@ function App() {
         setStatus('No jobs started.')
       }
     } catch (error) {
-      console.error(error)
-      setStatus('Failed to start translation job.')
+      setStatus(failure('Failed to start translation job', error))
     }
-  }, [entries, selection, selectedProjectId, selectedFileId, providerSettings, targetLang])
+  }, [entries, selection, selectedProjectId, selectedFileId, providerSettings, targetLang, withUnlock, confirmEstimate, failure])
 
   const handleAiTranslateRow = useCallback(async (entry: Entry) => {
     if (!(window as any)?.go?.app) {
@@ -692,28 +774,39 @@ function App() {
       return
     }
     try {
+      const estimate = await withUnlock(() => JobsAPI.EstimateTranslateUnits(app.StartTranslateUnitsRequest.createFrom({
+        project_id: selectedProjectId,
+        provider_id: providerSettings.providerId,
+        unit_ids: [entry.unitId],
+        locales,
+        model: providerSettings.model,
+        force: true,
+      })))
+      if ((await confirmEstimate(estimate, false)) == null) {
+        setStatus('Translation canceled.')
+        return
+      }
       if ((entry.draft || '').trim() === '') {
         try { await (TranslationsAPI as any).Upsert({ unit_id: entry.unitId, locale: targetLang, text: '', status: 'draft' }) } catch {}
       }
       setStatus(`Starting translation for ${entry.key}…`)
-      const res = await (JobsAPI as any).StartTranslateUnit({
+      const res = await withUnlock(() => JobsAPI.StartTranslateUnit(app.StartTranslateUnitRequest.createFrom({
         project_id: selectedProjectId,
         provider_id: providerSettings.providerId,
         unit_id: entry.unitId,
         locales,
         model: providerSettings.model,
         force: true,
-      })
-      const jobId = res?.job_id ?? res?.JobID
+      })))
+      const jobId = res?.job_id
       if (jobId) {
         setJobProgress({ jobId, done: 0, total: locales.length, status: 'running', model: providerSettings.model })
       }
       setStatus(`Queued translation for ${entry.key}.`)
     } catch (error) {
-      console.error(error)
-      setStatus('Failed to start translation job.')
+      setStatus(failure('Failed to start translation job', error))
     }
-  }, [selectedProjectId, selectedFileId, providerSettings, targetLang])
+  }, [selectedProjectId, selectedFileId, providerSettings, targetLang, withUnlock, confirmEstimate, failure])
 
   const handleSaveRow = useCallback(async (entry: Entry) => {
     if (!(window as any)?.go?.app) {
@@ -1015,6 +1108,14 @@ function App() {
         onClose={() => setConfirmState(prev => ({ ...prev, open: false }))}
         onConfirm={async () => { if (confirmState.onConfirm) await confirmState.onConfirm() }}
       />
+      <EstimateModal
+        estimate={estimateState?.estimate ?? null}
+        allowBudget={estimateState?.allowBudget}
+        providerName={id => providers.find(p => p.id === id)?.name || `#${id}`}
+        onConfirm={budget => closeEstimate(budget)}
+        onClose={() => closeEstimate(null)}
+      />
+      <UnlockModal open={unlockOpen} status={secretsStatus} onUnlocked={handleUnlocked} onClose={() => finishUnlock(false)} />
       <div id="app" className="h-full w-full flex">
         {sidebarCollapsed && (
           <button
@@ -1389,31 +1490,38 @@ function App() {
             <section className="grow overflow-auto">
               <div className="max-w-4xl mx-auto w-full px-4 py-4 grid gap-6">
                 {settingsProviderId === 'general' ? (
-                  <GeneralSettings theme={themePref} onChangeTheme={handleChangeTheme} />
+                  <GeneralSettings
+                    theme={themePref}
+                    onChangeTheme={handleChangeTheme}
+                    secrets={secretsStatus}
+                    onUnlock={() => { requestUnlock() }}
+                    onLock={handleLock}
+                  />
                 ) : (
                 <ProviderEditor
                   provider={settingsProviderId === 'new' ? null : providers.find(p => p.id === settingsProviderId) || null}
+                  withUnlock={withUnlock}
                   onCreate={async (data) => {
                     if (!(window as any)?.go?.app) { setStatus('Backend not available in web mode.'); return }
                     try {
-                      const created = await (ProviderAPI as any).Create({ ...data })
+                      const created = await withUnlock(() => ProviderAPI.Create(domain.Provider.createFrom({ ...data })))
                       setStatus('Provider created.')
                       await loadProviders()
                       if (created?.id) { setSettingsProviderId(created.id); selectProvider(String(created.id)) }
-                    } catch (e) { console.error(e); setStatus('Failed to create provider.') }
+                    } catch (e) { setStatus(failure('Failed to create provider', e)) }
                   }}
                   onUpdate={async (data) => {
                     if (!(window as any)?.go?.app) { setStatus('Backend not available in web mode.'); return }
                     try {
-                      await (ProviderAPI as any).Update({ ...data })
+                      await withUnlock(() => ProviderAPI.Update(domain.Provider.createFrom({ ...data })))
                       setStatus('Provider updated.')
                       await loadProviders()
-                    } catch (e) { console.error(e); setStatus('Failed to update provider.') }
+                    } catch (e) { setStatus(failure('Failed to update provider', e)) }
                   }}
                   onDelete={async (id) => {
                     if (!(window as any)?.go?.app) { setStatus('Backend not available in web mode.'); return }
                     try {
-                      await (ProviderAPI as any).Delete(id)
+                      await ProviderAPI.Delete(id)
                       setStatus('Provider deleted.')
                       await loadProviders()
                     } catch (e) { console.error(e); setStatus('Failed to delete provider.') }
@@ -1421,9 +1529,9 @@ function App() {
                   onTest={async (id) => {
                     if (!(window as any)?.go?.app) { setStatus('Backend not available in web mode.'); return }
                     try {
-                      const res = await (ProviderAPI as any).Test(id)
+                      const res = await withUnlock(() => ProviderAPI.Test(id))
                       setStatus(res?.ok ? 'Provider test OK' : `Provider test failed: ${res?.error || 'unknown error'}`)
-                    } catch (e: any) { console.error(e); setStatus(`Provider test failed: ${String(e?.message || e)}`) }
+                    } catch (e: any) { setStatus(failure('Provider test failed', e)) }
                   }}
                 />)}
               </div>
@@ -0,0 +1,83 @@
+import React, { useEffect, useState } from 'react'
+import { X } from 'lucide-react'
+import { Button } from './ui/button'
+import { jobs } from '../../wailsjs/go/models'
+
+type Props = {
+  estimate: jobs.Estimate | null
+  // allowBudget shows the cost cap input; single-unit jobs take none.
+  allowBudget?: boolean
+  providerName: (id: number) => string
+  onConfirm: (budget: number) => void
+  onClose: () => void
+}
+
+const money = (v?: number | null) => (v == null ? 'unknown' : `$${v < 0.01 && v > 0 ? v.toFixed(4) : v.toFixed(2)}`)
+const plural = (n: number, word: string) => `${n.toLocaleString()} ${word}${n === 1 ? '' : 's'}`
+
+// EstimateModal shows the expected size, cost and duration of a translate job before it starts.
+export default function EstimateModal({ estimate, allowBudget = true, providerName, onConfirm, onClose }: Props) {
+  const [budget, setBudget] = useState('')
+  useEffect(() => { if (estimate) setBudget('') }, [estimate])
+  if (!estimate) return null
+  const est = estimate
+  const budgetValue = Number(budget) || 0
+  const minutes = est.seconds == null ? null : Math.max(1, Math.ceil(est.seconds / 60))
+  return (
+    <div className="fixed inset-0 z-50 grid place-items-center">
+      <div className="absolute inset-0 bg-black/40" onClick={onClose} />
+      <div className="relative z-10 w-[92vw] max-w-md rounded-xl bg-white dark:bg-slate-800 shadow-xl border border-slate-200 dark:border-slate-700">
+        <div className="flex items-center justify-between p-3 border-b border-slate-200 dark:border-slate-700">
+          <div className="text-sm font-semibold">Start translation?</div>
+          <button className="p-2 rounded-lg hover:bg-slate-100 dark:hover:bg-slate-700" onClick={onClose} aria-label="Close">
+            <X className="h-4 w-4" />
+          </button>
+        </div>
+        <div className="p-4 grid gap-2 text-sm text-slate-700 dark:text-slate-200">
+          <div>
+            {plural(est.segments, 'segment')} · {plural(est.words, 'word')} · {plural(est.chars, 'character')}
+          </div>
+          {est.memory > 0 && <div className="text-xs text-muted-foreground">{plural(est.memory, 'segment')} filled from translation memory at no cost.</div>}
+          {est.outdated > 0 && <div className="text-xs text-amber-600">{plural(est.outdated, 'existing translation')} will be replaced.</div>}
+          <div>
+            {plural(est.requests, 'request')} · ~{(est.input_tokens + est.output_tokens).toLocaleString()} tokens
+            <span className="text-xs text-muted-foreground"> ({est.input_tokens.toLocaleString()} in, {est.output_tokens.toLocaleString()} out)</span>
+          </div>
+          <div>
+            Cost: <span className="font-medium">{est.cost == null ? 'unknown (no price for this model)' : `~${money(est.cost)}`}</span>
+            {minutes != null && <> · ~{plural(minutes, 'minute')}</>}
+          </div>
+          {est.fallbacks && est.fallbacks.length > 0 && (
+            <div className="text-xs text-muted-foreground">
+              Fallbacks, if they had to translate everything:
+              <ul className="list-disc ml-5">
+                {est.fallbacks.map((fb, i) => (
+                  <li key={i}>{providerName(fb.provider_id)} · {fb.model || 'default model'}: {money(fb.cost)}</li>
+                ))}
+              </ul>
+            </div>
+          )}
+          {allowBudget && (
+            <label className="grid gap-1 mt-1">
+              <span className="text-xs text-muted-foreground">Stop when the job costs more than (USD, optional)</span>
+              <input
+                type="number"
+                min={0}
+                step="0.01"
+                className="h-9 border rounded-md px-2 dark:border-slate-600 dark:bg-slate-900 dark:text-slate-100"
+                value={budget}
+                disabled={est.cost == null}
+                placeholder={est.cost == null ? 'Needs the model price' : 'No limit'}
+                onChange={e => setBudget(e.target.value)}
+              />
+            </label>
+          )}
+        </div>
+        <div className="p-3 border-t border-slate-200 dark:border-slate-700 flex items-center justify-end gap-2">
+          <Button variant="outline" onClick={onClose}>Cancel</Button>
+          <Button onClick={() => onConfirm(budgetValue)} disabled={est.segments === 0}>Translate</Button>
+        </div>
+      </div>
+    </div>
+  )
+}
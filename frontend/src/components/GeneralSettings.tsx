@@ -1,13 +1,19 @@
 import React from 'react'
+import { Button } from './ui/button'
+import NetworkSettings from './NetworkSettings'
+import { app } from '../../wailsjs/go/models'
 
 type ThemePref = 'system' | 'light' | 'dark'
 
 type Props = {
   theme: ThemePref
   onChangeTheme: (value: ThemePref) => void
+  secrets: app.SecretsStatus | null
+  onUnlock: () => void
+  onLock: () => void
 }
 
-export default function GeneralSettings({ theme, onChangeTheme }: Props) {
+export default function GeneralSettings({ theme, onChangeTheme, secrets, onUnlock, onLock }: Props) {
   return (
     <div className="grid gap-4">
       <div>
@@ -37,6 +43,35 @@ export default function GeneralSettings({ theme, onChangeTheme }: Props) {
           </button>
         </div>
       </div>
+      {secrets && (
+        <div>
+          <div className="text-sm font-medium mb-1">API keys</div>
+          {secrets.mode === 'passphrase' ? (
+            <>
+              <div className="text-xs text-muted-foreground mb-2">
+                {!secrets.initialized
+                  ? 'Keys are encrypted with a passphrase. Set it to store keys.'
+                  : secrets.locked
+                    ? 'Keys are encrypted and locked. Unlock them to translate and test providers.'
+                    : 'Keys are encrypted and unlocked for this session.'}
+              </div>
+              {secrets.locked ? (
+                <Button onClick={onUnlock}>{secrets.initialized ? 'Unlock' : 'Set passphrase'}</Button>
+              ) : (
+                <Button variant="outline" onClick={onLock}>Lock</Button>
+              )}
+            </>
+          ) : (
+            <div className="text-xs text-muted-foreground">Keys are stored in the operating system keyring.</div>
+          )}
+          {secrets.plaintext > 0 && (
+            <div className="mt-2 text-xs text-amber-600">
+              {secrets.plaintext} key{secrets.plaintext === 1 ? ' is' : 's are'} still stored unencrypted{secrets.mode === 'passphrase' ? ' until the keys are unlocked' : ''}.
+            </div>
+          )}
+        </div>
+      )}
+      <NetworkSettings />
     </div>
   )
 }
@@ -0,0 +1,68 @@
+import React, { useEffect, useState } from 'react'
+import { Button } from './ui/button'
+import { Input } from './ui/input'
+import * as NetworkAPI from '../../wailsjs/go/app/NetworkAPI'
+import { httpclient } from '../../wailsjs/go/models'
+
+type Message = { error?: string; warning?: string; ok?: boolean }
+
+// NetworkSettings edits the global proxy and TLS settings; a provider's own options override them.
+export default function NetworkSettings() {
+  const [network, setNetwork] = useState<httpclient.Network>(() => httpclient.Network.createFrom({}))
+  const [noProxy, setNoProxy] = useState('')
+  const [message, setMessage] = useState<Message | null>(null)
+  const [busy, setBusy] = useState(false)
+
+  useEffect(() => {
+    NetworkAPI.Get().then(n => {
+      setNetwork(n)
+      setNoProxy((n.no_proxy || []).join(', '))
+    }).catch(console.error)
+  }, [])
+
+  const set = (name: keyof httpclient.Network, value: any) => {
+    setMessage(null)
+    setNetwork(prev => httpclient.Network.createFrom({ ...prev, [name]: value === '' ? undefined : value }))
+  }
+
+  const save = async () => {
+    try {
+      setBusy(true)
+      const list = noProxy.split(',').map(s => s.trim()).filter(Boolean)
+      const res = await NetworkAPI.Set(httpclient.Network.createFrom({ ...network, no_proxy: list.length ? list : undefined }))
+      setMessage({ ok: res.ok, warning: res.warning })
+    } catch (e: any) {
+      setMessage({ error: String(e?.message || e) })
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  return (
+    <div>
+      <div className="text-sm font-medium mb-1">Network</div>
+      <div className="text-xs text-muted-foreground mb-2">
+        Proxy and TLS settings for all providers; a provider's own options override them. Without a proxy URL, HTTP_PROXY and HTTPS_PROXY are used.
+      </div>
+      <div className="grid gap-2 max-w-xl">
+        <Input value={network.proxy_url || ''} onChange={e => set('proxy_url', e.target.value)} placeholder="Proxy URL (http://proxy:3128 or socks5://host:1080)" />
+        <Input value={noProxy} onChange={e => { setMessage(null); setNoProxy(e.target.value) }} placeholder="No proxy (localhost, .corp.example, 10.0.0.0/8)" />
+        <Input value={network.ca_file || ''} onChange={e => set('ca_file', e.target.value)} placeholder="CA bundle (PEM file)" />
+        <div className="grid gap-2 md:grid-cols-2">
+          <Input value={network.client_cert_file || ''} onChange={e => set('client_cert_file', e.target.value)} placeholder="Client certificate (PEM)" />
+          <Input value={network.client_key_file || ''} onChange={e => set('client_key_file', e.target.value)} placeholder="Client key (PEM)" />
+        </div>
+        <label className="flex items-center gap-2 text-sm">
+          <input type="checkbox" checked={!!network.insecure_skip_verify} onChange={e => set('insecure_skip_verify', e.target.checked || undefined)} />
+          Skip TLS certificate verification (insecure)
+        </label>
+        {message?.error && <div className="text-xs text-red-600">{message.error}</div>}
+        {message?.warning && <div className="text-xs text-amber-600">{message.warning}</div>}
+        {message?.ok && !message.warning && <div className="text-xs text-green-600">Saved.</div>}
+        <div>
+          <Button onClick={save} disabled={busy}>Save network settings</Button>
+        </div>
+      </div>
+    </div>
+  )
+}
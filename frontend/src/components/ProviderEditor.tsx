@@ -5,6 +5,7 @@ import { Label } from './ui/label'
 import { Card, CardContent, CardHeader, CardTitle, CardDescription } from './ui/card'
 import { Trash2, Save as SaveIcon, Play, RefreshCw } from 'lucide-react'
 import * as ProviderAPI from '../../wailsjs/go/app/ProviderAPI'
+import { app, domain, registry } from '../../wailsjs/go/models'
 import ModelDropdown from './ModelDropdown'
 import ConfirmModal from './ConfirmModal'
 
@@ -24,15 +25,108 @@ type Props = {
   onUpdate: (data: ProviderInfo) => Promise<void>
   onDelete: (id: number) => Promise<void>
   onTest?: (id: number) => Promise<void>
+  // withUnlock retries a call after asking for the passphrase when the API keys are locked.
+  withUnlock?: <T>(fn: () => Promise<T>) => Promise<T>
 }
 
-const defaultTypes = ['openrouter', 'ollama']
+export type ProviderField = registry.Field
+export type ProviderType = registry.Descriptor
 
-export default function ProviderEditor({ provider, onCreate, onUpdate, onDelete, onTest }: Props) {
+const preferredType = 'openrouter'
+
+const inputClass = 'h-9 border rounded-md px-2 dark:border-slate-600 dark:bg-slate-900 dark:text-slate-100'
+
+// parseOptions returns the options object, or null when the JSON is not an object.
+function parseOptions(raw?: string): Record<string, any> | null {
+  if (!raw || !raw.trim()) return {}
+  try {
+    const v = JSON.parse(raw)
+    return v && typeof v === 'object' && !Array.isArray(v) ? v : null
+  } catch {
+    return null
+  }
+}
+
+const structured = (kind: string) => kind === 'list' || kind === 'object' || kind === 'json'
+
+// parseStructured reads the text of a list (one entry per line) or object (JSON) option;
+// undefined clears the option.
+function parseStructured(kind: string, text: string): { value?: any; error?: string } {
+  if (kind === 'list') {
+    const list = text.split('\n').map(s => s.trim()).filter(Boolean)
+    return { value: list.length ? list : undefined }
+  }
+  if (!text.trim()) return {}
+  try {
+    const v = JSON.parse(text)
+    if (!v || typeof v !== 'object' || Array.isArray(v)) return { error: 'Enter a JSON object.' }
+    if (kind === 'object' && Object.values(v).some(x => typeof x !== 'string')) return { error: 'Values must be strings.' }
+    return { value: Object.keys(v).length ? v : undefined }
+  } catch {
+    return { error: 'Not valid JSON.' }
+  }
+}
+
+function formatStructured(kind: string, value: any): string {
+  if (value == null) return ''
+  if (kind === 'list') return Array.isArray(value) ? value.join('\n') : ''
+  return JSON.stringify(value, null, 2)
+}
+
+type StructuredOptionProps = {
+  option: ProviderField
+  value: any
+  disabled: boolean
+  onChange: (value: any) => void
+}
+
+// StructuredOption edits list, object and json options, keeping the text being typed until
+// it parses.
+function StructuredOption({ option, value, disabled, onChange }: StructuredOptionProps) {
+  const [text, setText] = useState(() => formatStructured(option.kind, value))
+  const [error, setError] = useState<string | undefined>()
+  const current = JSON.stringify(value ?? null)
+  // follow edits made in the raw options JSON
+  useEffect(() => {
+    const parsed = parseStructured(option.kind, text)
+    if (parsed.error || JSON.stringify(parsed.value ?? null) !== current) {
+      setText(formatStructured(option.kind, value))
+      setError(undefined)
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [current])
+  const edit = (next: string) => {
+    setText(next)
+    const parsed = parseStructured(option.kind, next)
+    setError(parsed.error)
+    if (!parsed.error) onChange(parsed.value)
+  }
+  return (
+    <>
+      <textarea
+        id={`popt-${option.name}`}
+        className="w-full rounded-md border px-2 py-1 min-h-[64px] font-mono text-xs dark:bg-slate-900 dark:border-slate-600 dark:text-slate-100 focus:outline-none focus:ring-2 focus:ring-indigo-500"
+        disabled={disabled}
+        value={text}
+        placeholder={option.placeholder || (option.kind === 'list' ? 'One per line' : '{ }')}
+        onChange={e => edit(e.target.value)}
+      />
+      {error && <div className="text-xs text-red-600">{error}</div>}
+    </>
+  )
+}
+
+export default function ProviderEditor({ provider, onCreate, onUpdate, onDelete, onTest, withUnlock = fn => fn() }: Props) {
   const creating = provider == null
+  const [types, setTypes] = useState<ProviderType[]>([])
+  useEffect(() => {
+    ProviderAPI.Types().then(list => setTypes(list || [])).catch(console.error)
+  }, [])
+  const defaultType = useMemo(() => (types.find(t => t.type === preferredType) || types[0])?.type || preferredType, [types])
+
   const [form, setForm] = useState<Omit<ProviderInfo, 'id'>>({
     name: '',
-    type: defaultTypes[0],
+    type: preferredType,
     base_url: '',
     model: '',
     api_key: '',
@@ -43,19 +137,39 @@ export default function ProviderEditor({ provider, onCreate, onUpdate, onDelete,
     if (!creating && provider) {
       setForm({
         name: provider.name || '',
-        type: provider.type || defaultTypes[0],
+        type: provider.type || defaultType,
         base_url: provider.base_url || '',
         model: provider.model || '',
         api_key: provider.api_key || '',
         options_json: provider.options_json || '',
       })
     } else if (creating) {
-      setForm({ name: '', type: defaultTypes[0], base_url: '', model: '', api_key: '', options_json: '' })
+      setForm({ name: '', type: defaultType, base_url: '', model: '', api_key: '', options_json: '' })
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [creating, provider?.id])
 
-  const canSave = useMemo(() => form.name.trim().length > 0 && form.type.trim().length > 0, [form.name, form.type])
+  // New providers start with a registered type once the types are loaded.
+  useEffect(() => {
+    if (creating && types.length > 0) setForm(prev => (types.some(t => t.type === prev.type) ? prev : { ...prev, type: defaultType }))
+  }, [creating, types, defaultType])
+
+  const desc = useMemo(() => types.find(t => t.type === form.type), [types, form.type])
+  const field = (name: string) => desc?.fields.find(f => f.name === name)
+  const options = useMemo(() => parseOptions(form.options_json), [form.options_json])
+
+  const setOption = (name: string, value: any) => {
+    const next = { ...(options || {}) }
+    if (value === '' || value === undefined || value === null) delete next[name]
+    else next[name] = value
+    setForm(prev => ({ ...prev, options_json: Object.keys(next).length ? JSON.stringify(next, null, 2) : '' }))
+  }
+
+  const missing = useMemo(() => {
+    const values: Record<string, string | undefined> = { base_url: form.base_url, api_key: form.api_key, model: form.model }
+    return (desc?.fields || []).filter(f => f.required && !(values[f.name] || '').trim()).map(f => f.label)
+  }, [desc, form.base_url, form.api_key, form.model])
+  const canSave = useMemo(() => form.name.trim().length > 0 && form.type.trim().length > 0 && missing.length === 0, [form.name, form.type, missing])
   const dirty = useMemo(() => {
     if (!provider) return true
     return (
@@ -68,26 +182,27 @@ export default function ProviderEditor({ provider, onCreate, onUpdate, onDelete,
     )
   }, [provider, form])
 
-  const [models, setModels] = useState<Array<{ Name: string; Description: string; ContextTokens: number }>>([])
+  const [models, setModels] = useState<app.ModelInfo[]>([])
   const [loadingModels, setLoadingModels] = useState(false)
 
   const loadModels = async () => {
     try {
       setLoadingModels(true)
-      let resp: any[] = []
+      let resp: app.ModelInfo[] = []
       if (provider && !dirty) {
-        resp = await (ProviderAPI as any).ListModels(provider.id)
+        resp = await withUnlock(() => ProviderAPI.ListModels(provider.id))
       } else {
-        const preview = {
-          id: 0,
+        // a saved provider's id lets the backend use its stored key while the form shows it masked
+        const preview = domain.Provider.createFrom({
+          id: provider?.id || 0,
           type: form.type,
           name: form.name || 'temp',
           base_url: form.base_url,
           model: form.model,
           api_key: form.api_key,
           options_json: form.options_json,
-        }
-        resp = await (ProviderAPI as any).ListModelsPreview(preview)
+        })
+        resp = await withUnlock(() => ProviderAPI.ListModelsPreview(preview))
       }
       setModels(resp || [])
     } catch (e) {
@@ -119,46 +234,93 @@ export default function ProviderEditor({ provider, onCreate, onUpdate, onDelete,
           </div>
           <div className="grid gap-1.5">
             <Label htmlFor="ptype">Type</Label>
-            <select id="ptype" className="h-9 border rounded-md px-2 dark:border-slate-600 dark:bg-slate-900 dark:text-slate-100" value={form.type} onChange={e => setForm(prev => ({ ...prev, type: e.target.value }))}>
-              {defaultTypes.map(t => (<option key={t} value={t}>{t}</option>))}
+            <select id="ptype" className={inputClass} value={form.type} onChange={e => setForm(prev => ({ ...prev, type: e.target.value }))}>
+              {types.map(t => (<option key={t.type} value={t.type}>{t.label}</option>))}
+              {form.type && !desc && <option value={form.type}>{form.type}</option>}
             </select>
           </div>
         </div>
         <div className="grid gap-1.5 md:grid-cols-2 md:gap-3">
+          {field('base_url') && (
+            <div className="grid gap-1.5">
+              <Label htmlFor="purl">{field('base_url')!.label}{field('base_url')!.required && ' *'}</Label>
+              <Input id="purl" value={form.base_url} onChange={e => setForm(prev => ({ ...prev, base_url: e.target.value }))} placeholder={field('base_url')!.placeholder || desc?.default_base_url} />
+              {field('base_url')!.help && <div className="text-xs text-muted-foreground">{field('base_url')!.help}</div>}
+            </div>
+          )}
+          {field('model') && (
+            <div className="grid gap-1.5">
+              <Label htmlFor="pmodel">{field('model')!.label}{field('model')!.required && ' *'}</Label>
+              {models.length > 0 ? (
+                <ModelDropdown
+                  value={form.model || ''}
+                  options={models.map(m => ({ value: m.Name, label: m.Description || m.Name, tokens: m.ContextTokens }))}
+                  onChange={(val) => setForm(prev => ({ ...prev, model: val }))}
+                  onRefresh={loadModels}
+                  loading={loadingModels}
+                />
+              ) : (
+                <div className="flex items-center gap-2">
+                  <Input id="pmodel" value={form.model} onChange={e => setForm(prev => ({ ...prev, model: e.target.value }))} placeholder={field('model')!.placeholder} />
+                  {desc?.capabilities.list_models && (
+                    <button type="button" className="p-2 rounded-md border hover:bg-slate-50 dark:border-slate-600 dark:hover:bg-slate-800" title="Load models" onClick={loadModels} disabled={loadingModels}>
+                      <RefreshCw className={`h-4 w-4 ${loadingModels ? 'animate-spin' : ''}`} />
+                    </button>
+                  )}
+                </div>
+              )}
+              <div className="text-xs text-muted-foreground">{field('model')!.help || 'Use “Load models” to discover available models for this provider.'}</div>
+            </div>
+          )}
+        </div>
+        {field('api_key') && (
           <div className="grid gap-1.5">
-            <Label htmlFor="purl">Base URL</Label>
-            <Input id="purl" value={form.base_url} onChange={e => setForm(prev => ({ ...prev, base_url: e.target.value }))} placeholder="https://api.openai.com/v1 or http://localhost:11434" />
+            <Label htmlFor="pkey">{field('api_key')!.label}{field('api_key')!.required && ' *'}</Label>
+            <Input id="pkey" type="password" value={form.api_key} onChange={e => setForm(prev => ({ ...prev, api_key: e.target.value }))} placeholder={field('api_key')!.placeholder} />
+            {field('api_key')!.help && <div className="text-xs text-muted-foreground">{field('api_key')!.help}</div>}
           </div>
-          <div className="grid gap-1.5">
-            <Label htmlFor="pmodel">Model</Label>
-            {models.length > 0 ? (
-              <ModelDropdown
-                value={form.model || ''}
-                options={models.map(m => ({ value: m.Name, label: m.Description || m.Name, tokens: m.ContextTokens }))}
-                onChange={(val) => setForm(prev => ({ ...prev, model: val }))}
-                onRefresh={loadModels}
-                loading={loadingModels}
-              />
-            ) : (
-              <div className="flex items-center gap-2">
-                <Input id="pmodel" value={form.model} onChange={e => setForm(prev => ({ ...prev, model: e.target.value }))} placeholder="gpt-4o-mini or llama3.1" />
-                <button type="button" className="p-2 rounded-md border hover:bg-slate-50 dark:border-slate-600 dark:hover:bg-slate-800" title="Load models" onClick={loadModels} disabled={loadingModels}>
-                  <RefreshCw className={`h-4 w-4 ${loadingModels ? 'animate-spin' : ''}`} />
-                </button>
+        )}
+        {desc && desc.options.length > 0 && (
+          <div className="grid gap-1.5 md:grid-cols-2 md:gap-3">
+            {desc.options.map(o => (
+              <div key={o.name} className={`grid gap-1.5 ${structured(o.kind) ? 'md:col-span-2' : ''}`}>
+                <Label htmlFor={`popt-${o.name}`}>{o.label}</Label>
+                {structured(o.kind) ? (
+                  <StructuredOption option={o} value={options?.[o.name]} disabled={options == null} onChange={v => setOption(o.name, v)} />
+                ) : o.kind === 'bool' ? (
+                  <select id={`popt-${o.name}`} className={inputClass} disabled={options == null} value={options?.[o.name] === undefined ? '' : String(options[o.name])} onChange={e => setOption(o.name, e.target.value === '' ? '' : e.target.value === 'true')}>
+                    <option value="">(default)</option>
+                    <option value="true">yes</option>
+                    <option value="false">no</option>
+                  </select>
+                ) : o.kind === 'select' ? (
+                  <select id={`popt-${o.name}`} className={inputClass} disabled={options == null} value={options?.[o.name] ?? ''} onChange={e => setOption(o.name, e.target.value)}>
+                    <option value="">(default)</option>
+                    {(o.choices || []).map(c => (<option key={c} value={c}>{c}</option>))}
+                  </select>
+                ) : (
+                  <Input
+                    id={`popt-${o.name}`}
+                    type={o.kind === 'number' ? 'number' : 'text'}
+                    min={o.kind === 'number' ? 0 : undefined}
+                    disabled={options == null}
+                    value={options?.[o.name] ?? ''}
+                    placeholder={o.placeholder}
+                    onChange={e => setOption(o.name, o.kind === 'number' ? (e.target.value === '' ? '' : Number(e.target.value)) : e.target.value)}
+                  />
+                )}
+                {o.help && <div className="text-xs text-muted-foreground">{o.help}</div>}
               </div>
-            )}
-            <div className="text-xs text-muted-foreground">Use “Load models” to discover available models for this provider.</div>
+            ))}
           </div>
-        </div>
-        <div className="grid gap-1.5">
-          <Label htmlFor="pkey">API Key</Label>
-          <Input id="pkey" type="password" value={form.api_key} onChange={e => setForm(prev => ({ ...prev, api_key: e.target.value }))} placeholder="sk-..." />
-        </div>
+        )}
         <div className="grid gap-1.5">
           <Label htmlFor="popt">Options (JSON)</Label>
+          {options == null && <div className="text-xs text-red-600">Options are not a valid JSON object.</div>}
           <textarea id="popt" className="w-full rounded-md border px-2 py-1 min-h-[80px] font-mono text-xs dark:bg-slate-900 dark:border-slate-600 dark:text-slate-100 focus:outline-none focus:ring-2 focus:ring-indigo-500" value={form.options_json} onChange={e => setForm(prev => ({ ...prev, options_json: e.target.value }))} placeholder="{ }" />
         </div>
 
+        {missing.length > 0 && <div className="text-xs text-muted-foreground">Required: {missing.join(', ')}</div>}
         <div className="flex items-center gap-2 mt-2">
           <Button onClick={submit} disabled={!canSave}><SaveIcon className="h-4 w-4 mr-2"/>Save</Button>
           {!creating && (
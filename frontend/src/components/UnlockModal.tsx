@@ -0,0 +1,80 @@
+import React, { useEffect, useState } from 'react'
+import { X } from 'lucide-react'
+import { Button } from './ui/button'
+import * as SecretsAPI from '../../wailsjs/go/app/SecretsAPI'
+import { app } from '../../wailsjs/go/models'
+import { MinPassphrase } from '../lib/secrets'
+
+type Props = {
+  open: boolean
+  status: app.SecretsStatus | null
+  onUnlocked: (status: app.SecretsStatus) => void
+  onClose: () => void
+}
+
+// UnlockModal asks for the passphrase that encrypts API keys; the first time it sets it.
+export default function UnlockModal({ open, status, onUnlocked, onClose }: Props) {
+  const [passphrase, setPassphrase] = useState('')
+  const [confirm, setConfirm] = useState('')
+  const [error, setError] = useState('')
+  const [busy, setBusy] = useState(false)
+  const setting = status != null && !status.initialized
+
+  useEffect(() => {
+    if (!open) return
+    setPassphrase('')
+    setConfirm('')
+    setError('')
+  }, [open])
+
+  if (!open) return null
+
+  const submit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    if (setting && passphrase.length < MinPassphrase) { setError(`Use at least ${MinPassphrase} characters.`); return }
+    if (setting && passphrase !== confirm) { setError('The passphrases do not match.'); return }
+    try {
+      setBusy(true)
+      setError('')
+      onUnlocked(await SecretsAPI.Unlock(passphrase))
+    } catch (err: any) {
+      setError(String(err?.message || err))
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  const inputClass = 'h-9 w-full border rounded-md px-2 dark:border-slate-600 dark:bg-slate-900 dark:text-slate-100'
+  return (
+    <div className="fixed inset-0 z-50 grid place-items-center">
+      <div className="absolute inset-0 bg-black/40" onClick={() => !busy && onClose()} />
+      <form onSubmit={submit} className="relative z-10 w-[92vw] max-w-sm rounded-xl bg-white dark:bg-slate-800 shadow-xl border border-slate-200 dark:border-slate-700">
+        <div className="flex items-center justify-between p-3 border-b border-slate-200 dark:border-slate-700">
+          <div className="text-sm font-semibold">{setting ? 'Set passphrase' : 'Unlock API keys'}</div>
+          <button type="button" className="p-2 rounded-lg hover:bg-slate-100 dark:hover:bg-slate-700" onClick={() => !busy && onClose()} aria-label="Close">
+            <X className="h-4 w-4" />
+          </button>
+        </div>
+        <div className="p-4 grid gap-3 text-sm text-slate-700 dark:text-slate-200">
+          <div className="text-xs text-muted-foreground">
+            {setting
+              ? 'API keys are encrypted with this passphrase. It cannot be recovered; without it the keys have to be entered again.'
+              : 'Enter the passphrase to use the stored API keys in this session.'}
+          </div>
+          <input type="password" className={inputClass} placeholder="Passphrase" autoFocus value={passphrase} onChange={e => setPassphrase(e.target.value)} />
+          {setting && (
+            <input type="password" className={inputClass} placeholder="Repeat passphrase" value={confirm} onChange={e => setConfirm(e.target.value)} />
+          )}
+          {status && status.plaintext > 0 && (
+            <div className="text-xs text-amber-600">{status.plaintext} key{status.plaintext === 1 ? ' is' : 's are'} still stored unencrypted and will be encrypted.</div>
+          )}
+          {error && <div className="text-xs text-red-600">{error}</div>}
+        </div>
+        <div className="p-3 border-t border-slate-200 dark:border-slate-700 flex items-center justify-end gap-2">
+          <Button type="button" variant="outline" onClick={() => onClose()} disabled={busy}>Cancel</Button>
+          <Button type="submit" disabled={busy || !passphrase}>{setting ? 'Set passphrase' : 'Unlock'}</Button>
+        </div>
+      </form>
+    </div>
+  )
+}
@@ -0,0 +1,10 @@
+// LockedCode matches secrets.LockedCode in the backend: errors containing it mean the API
+// keys are locked until the passphrase is entered.
+export const LockedCode = 'secrets_locked'
+
+// MinPassphrase matches secrets.MinPassphrase.
+export const MinPassphrase = 8
+
+export function isLockedError(e: unknown): boolean {
+  return String((e as any)?.message ?? e ?? '').includes(LockedCode)
+}
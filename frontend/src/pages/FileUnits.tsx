@@ -11,6 +11,7 @@ import { Table, Thead, Tbody, Tr, Th, Td } from '../components/ui/table'
 import * as TranslationsAPI from '../../wailsjs/go/app/TranslationsAPI'
 import * as JobsAPI from '../../wailsjs/go/app/JobsAPI'
 import * as ProviderAPI from '../../wailsjs/go/app/ProviderAPI'
+import { app } from '../../wailsjs/go/models'
 
 type UnitText = { unit_id: number; key: string; source: string; translation: string; status: string }
 type Provider = { id: number; type: string; name: string; base_url?: string; model?: string }
@@ -105,7 +106,16 @@ export default function FileUnitsPage() {
     if (!providerID || !fID) return
     const locales = targetLocales.split(',').map(s => s.trim()).filter(Boolean)
     if (locales.length === 0 && locale) locales.push(locale)
-    await (JobsAPI as any).StartTranslateFile({ project_id: 0, provider_id: providerID, file_id: fID, locales, model })
+    const req = { project_id: 0, provider_id: providerID, file_id: fID, locales, model }
+    try {
+      const est = await JobsAPI.EstimateTranslateFile(app.StartTranslateFileRequest.createFrom(req))
+      const cost = est.cost == null ? 'unknown cost' : `~$${est.cost.toFixed(2)}`
+      const time = est.seconds == null ? '' : `, ~${Math.ceil(est.seconds / 60)} min`
+      if (!confirm(`Translate ${est.segments} segments (${est.words} words): ${est.requests} requests, ~${est.input_tokens + est.output_tokens} tokens, ${cost}${time}. Start?`)) return
+    } catch (e) {
+      console.error(e)
+    }
+    await (JobsAPI as any).StartTranslateFile(req)
     await loadJobs()
     setCurrentJobId(null)
     setJobProgress({done:0,total:0,status:'running', model})
@@ -1,10 +1,11 @@
-import React, { useEffect, useState } from 'react'
+import React, { useEffect, useMemo, useState } from 'react'
 import { Button } from '../components/ui/button'
 import { Input } from '../components/ui/input'
 import { Card, CardHeader, CardTitle, CardDescription, CardContent, CardFooter } from '../components/ui/card'
 import { Select } from '../components/ui/select'
 import { Plus, RefreshCw, TestTube2, Bot, Link2, KeyRound } from 'lucide-react'
 import * as ProviderAPI from '../../wailsjs/go/app/ProviderAPI'
+import { domain, registry } from '../../wailsjs/go/models'
 
 type Provider = {
   id: number
@@ -18,7 +19,8 @@ type Provider = {
 export default function ProvidersPage() {
   const [list, setList] = useState<Provider[]>([])
   const [loading, setLoading] = useState(false)
-  const [form, setForm] = useState<Provider>({ id: 0, type: 'ollama', name: '', base_url: '', model: '', api_key: '' } as any)
+  const [types, setTypes] = useState<registry.Descriptor[]>([])
+  const [form, setForm] = useState<Provider>({ id: 0, type: 'ollama', name: '', base_url: '', model: '', api_key: '' })
   const [models, setModels] = useState<ModelOpt[]>([])
   const [modelsFor, setModelsFor] = useState<number | null>(null)
   type ModelOpt = { name: string, label: string }
@@ -27,40 +29,46 @@ export default function ProvidersPage() {
   const [modelsLoading, setModelsLoading] = useState(false)
   const [formError, setFormError] = useState<string | null>(null)
   const [editing, setEditing] = useState<Provider | null>(null)
-  type TestResult = { ok?: boolean; translation?: string; raw?: string; error?: string; loading?: boolean }
+  type TestResult = { ok?: boolean; translation?: string; raw?: string; error?: string; warning?: string; loading?: boolean }
   const [testResults, setTestResults] = useState<Record<number, TestResult>>({})
 
   const load = async () => {
     setLoading(true)
     try {
-      const items = await (ProviderAPI as any).List()
+      const items = await ProviderAPI.List()
       setList(items || [])
+      setTypes((await ProviderAPI.Types()) || [])
     } finally { setLoading(false) }
   }
 
+
   useEffect(() => { load() }, [])
 
+  // keyField returns the api_key field of a provider type; types without one take no key.
+  const keyField = (type: string) => types.find(t => t.type === type)?.fields.find(f => f.name === 'api_key')
+  const formKey = useMemo(() => keyField(form.type), [types, form.type])
+
   const onChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement>) => {
     const { name, value } = e.target
     setForm(prev => ({ ...prev, [name]: value }))
   }
 
   const create = async () => {
-    await (ProviderAPI as any).Create({ ...form })
-    setForm({ id: 0, type: 'ollama', name: '', base_url: '', model: '' } as any)
+    await ProviderAPI.Create(domain.Provider.createFrom({ ...form }))
+    setForm({ id: 0, type: form.type, name: '', base_url: '', model: '' })
     await load()
   }
 
   const test = async (id: number) => {
     setTestResults(prev => ({ ...prev, [id]: { loading: true } }))
     try {
-      const res = await (ProviderAPI as any).Test(id)
-      // Wails may return PascalCase keys from Go; normalize to camelCase for UI
+      const res = await ProviderAPI.Test(id)
       const result: TestResult = {
-        ok: res?.ok ?? res?.Ok ?? false,
-        translation: res?.translation ?? res?.Translation ?? '',
-        raw: res?.raw ?? res?.Raw ?? '',
-        error: res?.error ?? res?.Error ?? undefined,
+        ok: res.ok,
+        translation: res.translation || '',
+        raw: res.raw || '',
+        error: res.error || undefined,
+        warning: res.warning || undefined,
         loading: false,
       }
       setTestResults(prev => ({ ...prev, [id]: result }))
@@ -70,8 +78,8 @@ export default function ProvidersPage() {
   }
 
   const fetchModels = async (id: number) => {
-    const res = await (ProviderAPI as any).ListModels(id)
-    const list = (res || []).map((m: any) => ({ name: (m?.Name ?? m?.name), label: (m?.Description ?? m?.description ?? (m?.Name ?? m?.name)) }))
+    const res = await ProviderAPI.ListModels(id)
+    const list = (res || []).map(m => ({ name: m.Name, label: m.Description || m.Name }))
     setModelsFor(id)
     setModels(list)
   }
@@ -82,14 +90,14 @@ export default function ProvidersPage() {
 
   const saveEdit = async () => {
     if (!editing) return
-    await (ProviderAPI as any).Update({ ...editing })
+    await ProviderAPI.Update(domain.Provider.createFrom({ ...editing }))
     setEditing(null)
     await load()
   }
 
   const remove = async (id: number) => {
     if (!confirm('Delete provider?')) return
-    await (ProviderAPI as any).Delete(id)
+    await ProviderAPI.Delete(id)
     await load()
   }
 
@@ -101,18 +109,13 @@ export default function ProvidersPage() {
         setFormError('Base URL is required')
         return
       }
-      if (form.type === 'openrouter' && !(form as any).api_key) {
-        setFormError('API Key is required for OpenRouter')
+      if (formKey?.required && !form.api_key) {
+        setFormError(`${formKey.label} is required`)
         return
       }
       setModelsLoading(true)
-      const fn = (ProviderAPI as any).ListModelsPreview
-      if (typeof fn !== 'function') {
-        setFormError('Model preview not available. Save the provider first, then click Models.')
-        return
-      }
-      const res = await fn({ ...form })
-      const list = (res || []).map((m: any) => ({ name: (m?.Name ?? m?.name), label: (m?.Description ?? m?.description ?? (m?.Name ?? m?.name)) })).filter((x:any)=>x.name)
+      const res = await ProviderAPI.ListModelsPreview(domain.Provider.createFrom({ ...form }))
+      const list = (res || []).map(m => ({ name: m.Name, label: m.Description || m.Name })).filter(x => x.name)
       setPreviewModels(list)
     } catch (e: any) {
       setFormError(String(e?.message || e))
@@ -135,8 +138,7 @@ export default function ProvidersPage() {
           {formError && <div className="text-sm text-red-600">{formError}</div>}
           <label className="text-sm">Type</label>
           <Select name="type" value={form.type} onChange={onChange}>
-            <option value="ollama">Ollama</option>
-            <option value="openrouter">OpenRouter</option>
+            {types.map(t => (<option key={t.type} value={t.type}>{t.label}</option>))}
           </Select>
           <label className="text-sm">Name</label>
           <Input name="name" value={form.name} onChange={onChange} placeholder="My Provider" />
@@ -153,10 +155,10 @@ export default function ProvidersPage() {
           ) : (
             <Input name="model" value={(form as any).model || ''} onChange={onChange} placeholder="e.g., llama3.1 or openrouter model" />
           )}
-          {form.type === 'openrouter' && (
+          {formKey && (
             <>
-              <label className="text-sm">API Key</label>
-              <Input name="api_key" value={(form as any).api_key || ''} onChange={onChange} placeholder="sk-..." />
+              <label className="text-sm">{formKey.label}{formKey.required && ' *'}</label>
+              <Input name="api_key" type="password" value={form.api_key || ''} onChange={onChange} placeholder={formKey.placeholder || 'sk-...'} />
               <div className="flex items-center gap-2 mt-2">
                 <Button variant="outline" size="sm" onClick={fetchPreviewModels} disabled={modelsLoading}>{modelsLoading ? 'Loading…' : 'Fetch Models'}</Button>
                 <Input value={modelFilter} onChange={e => setModelFilter(e.target.value)} placeholder="Filter by name" className="h-9 max-w-60" />
@@ -192,8 +194,8 @@ export default function ProvidersPage() {
                   ) : (
                     <Input value={(editing as any).model || ''} onChange={e => setEditing(prev => ({ ...(prev as any), model: e.target.value }) as any)} placeholder="Model (ID)" />
                   )}
-                  {editing.type === 'openrouter' && (
-                    <Input value={(editing as any).api_key || ''} onChange={e => setEditing(prev => ({ ...(prev as any), api_key: e.target.value }) as any)} placeholder="API Key" />
+                  {keyField(editing.type) && (
+                    <Input type="password" value={editing.api_key || ''} onChange={e => setEditing(prev => ({ ...(prev as any), api_key: e.target.value }) as any)} placeholder={keyField(editing.type)!.label} />
                   )}
                 </div>
               ) : (
@@ -229,6 +231,7 @@ export default function ProvidersPage() {
               <CardDescription>Translates "hello" to Russian and shows raw output or error.</CardDescription>
             </CardHeader>
             <CardContent>
+              {testResults[p.id].warning && <div className="mb-2 text-sm text-amber-600">{testResults[p.id].warning}</div>}
               {testResults[p.id].loading ? (
                 <div className="text-sm text-muted-foreground">Testing…</div>
               ) : testResults[p.id].ok ? (
@@ -0,0 +1,22 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {domain} from '../models';
+import {app} from '../models';
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function ExportBase64(arg1:domain.CacheFilter):Promise<app.CacheExportResponse>;
+
+export function GetTTLHours():Promise<number>;
+
+export function ImportBase64(arg1:string):Promise<number>;
+
+export function List(arg1:domain.CacheFilter):Promise<Array<domain.CacheEntry>>;
+
+export function Purge(arg1:domain.CacheFilter):Promise<number>;
+
+export function PurgeExpired():Promise<number>;
+
+export function SetTTLHours(arg1:number):Promise<boolean>;
+
+export function Stats():Promise<domain.CacheStats>;
@@ -0,0 +1,39 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Delete(arg1) {
+  return window['go']['app']['CacheAPI']['Delete'](arg1);
+}
+
+export function ExportBase64(arg1) {
+  return window['go']['app']['CacheAPI']['ExportBase64'](arg1);
+}
+
+export function GetTTLHours() {
+  return window['go']['app']['CacheAPI']['GetTTLHours']();
+}
+
+export function ImportBase64(arg1) {
+  return window['go']['app']['CacheAPI']['ImportBase64'](arg1);
+}
+
+export function List(arg1) {
+  return window['go']['app']['CacheAPI']['List'](arg1);
+}
+
+export function Purge(arg1) {
+  return window['go']['app']['CacheAPI']['Purge'](arg1);
+}
+
+export function PurgeExpired() {
+  return window['go']['app']['CacheAPI']['PurgeExpired']();
+}
+
+export function SetTTLHours(arg1) {
+  return window['go']['app']['CacheAPI']['SetTTLHours'](arg1);
+}
+
+export function Stats() {
+  return window['go']['app']['CacheAPI']['Stats']();
+}
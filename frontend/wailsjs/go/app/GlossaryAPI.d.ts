@@ -0,0 +1,14 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {app} from '../models';
+import {domain} from '../models';
+
+export function Check(arg1:app.GlossaryCheckRequest):Promise<Array<domain.TermIssue>>;
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function ImportBase64(arg1:app.GlossaryImportRequest):Promise<number>;
+
+export function List(arg1:number):Promise<Array<domain.GlossaryTerm>>;
+
+export function Save(arg1:domain.GlossaryTerm):Promise<domain.GlossaryTerm>;
@@ -0,0 +1,23 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Check(arg1) {
+  return window['go']['app']['GlossaryAPI']['Check'](arg1);
+}
+
+export function Delete(arg1) {
+  return window['go']['app']['GlossaryAPI']['Delete'](arg1);
+}
+
+export function ImportBase64(arg1) {
+  return window['go']['app']['GlossaryAPI']['ImportBase64'](arg1);
+}
+
+export function List(arg1) {
+  return window['go']['app']['GlossaryAPI']['List'](arg1);
+}
+
+export function Save(arg1) {
+  return window['go']['app']['GlossaryAPI']['Save'](arg1);
+}
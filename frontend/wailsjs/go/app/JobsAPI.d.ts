@@ -1,11 +1,16 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {app} from '../models';
+import {jobs} from '../models';
 
 export function Cancel(arg1:number):Promise<boolean>;
 
 export function Delete(arg1:number):Promise<boolean>;
 
+export function EstimateTranslateFile(arg1:app.StartTranslateFileRequest):Promise<jobs.Estimate>;
+
+export function EstimateTranslateUnits(arg1:app.StartTranslateUnitsRequest):Promise<jobs.Estimate>;
+
 export function Get(arg1:number):Promise<app.JobDTO>;
 
 export function Items(arg1:number):Promise<Array<app.JobItemDTO>>;
@@ -14,6 +19,8 @@ export function List(arg1:number):Promise<Array<app.JobDTO>>;
 
 export function Logs(arg1:number,arg2:number):Promise<Array<app.JobLogDTO>>;
 
+export function StartDetectLanguage(arg1:app.StartDetectLanguageRequest):Promise<app.StartJobResponse>;
+
 export function StartTranslateFile(arg1:app.StartTranslateFileRequest):Promise<app.StartJobResponse>;
 
 export function StartTranslateUnit(arg1:app.StartTranslateUnitRequest):Promise<app.StartJobResponse>;
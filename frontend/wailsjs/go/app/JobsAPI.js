@@ -10,6 +10,14 @@ export function Delete(arg1) {
   return window['go']['app']['JobsAPI']['Delete'](arg1);
 }
 
+export function EstimateTranslateFile(arg1) {
+  return window['go']['app']['JobsAPI']['EstimateTranslateFile'](arg1);
+}
+
+export function EstimateTranslateUnits(arg1) {
+  return window['go']['app']['JobsAPI']['EstimateTranslateUnits'](arg1);
+}
+
 export function Get(arg1) {
   return window['go']['app']['JobsAPI']['Get'](arg1);
 }
@@ -26,6 +34,10 @@ export function Logs(arg1, arg2) {
   return window['go']['app']['JobsAPI']['Logs'](arg1, arg2);
 }
 
+export function StartDetectLanguage(arg1) {
+  return window['go']['app']['JobsAPI']['StartDetectLanguage'](arg1);
+}
+
 export function StartTranslateFile(arg1) {
   return window['go']['app']['JobsAPI']['StartTranslateFile'](arg1);
 }
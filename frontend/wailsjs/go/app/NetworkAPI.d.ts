@@ -0,0 +1,8 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {httpclient} from '../models';
+import {app} from '../models';
+
+export function Get():Promise<httpclient.Network>;
+
+export function Set(arg1:httpclient.Network):Promise<app.NetworkResult>;
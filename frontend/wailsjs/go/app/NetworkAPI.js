@@ -0,0 +1,11 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Get() {
+  return window['go']['app']['NetworkAPI']['Get']();
+}
+
+export function Set(arg1) {
+  return window['go']['app']['NetworkAPI']['Set'](arg1);
+}
@@ -1,6 +1,7 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {domain} from '../models';
+import {translator} from '../models';
 
 export function AddLocale(arg1:number,arg2:string):Promise<domain.ProjectLocale>;
 
@@ -8,8 +9,12 @@ export function Create(arg1:string,arg2:string):Promise<domain.Project>;
 
 export function Delete(arg1:number):Promise<boolean>;
 
+export function GetFewShot(arg1:number):Promise<translator.FewShotConfig>;
+
 export function List():Promise<Array<domain.Project>>;
 
 export function ListLocales(arg1:number):Promise<Array<domain.ProjectLocale>>;
 
+export function SetFewShot(arg1:number,arg2:translator.FewShotConfig):Promise<boolean>;
+
 export function Update(arg1:number,arg2:string,arg3:string):Promise<domain.Project>;
@@ -14,6 +14,10 @@ export function Delete(arg1) {
   return window['go']['app']['ProjectAPI']['Delete'](arg1);
 }
 
+export function GetFewShot(arg1) {
+  return window['go']['app']['ProjectAPI']['GetFewShot'](arg1);
+}
+
 export function List() {
   return window['go']['app']['ProjectAPI']['List']();
 }
@@ -22,6 +26,10 @@ export function ListLocales(arg1) {
   return window['go']['app']['ProjectAPI']['ListLocales'](arg1);
 }
 
+export function SetFewShot(arg1, arg2) {
+  return window['go']['app']['ProjectAPI']['SetFewShot'](arg1, arg2);
+}
+
 export function Update(arg1, arg2, arg3) {
   return window['go']['app']['ProjectAPI']['Update'](arg1, arg2, arg3);
 }
@@ -0,0 +1,12 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {app} from '../models';
+import {domain} from '../models';
+
+export function Add(arg1:app.AddProtectedTermRequest):Promise<domain.ProtectedTerm>;
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function List(arg1:number):Promise<Array<domain.ProtectedTerm>>;
+
+export function Test(arg1:number,arg2:string):Promise<Array<string>>;
@@ -0,0 +1,19 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Add(arg1) {
+  return window['go']['app']['ProtectedTermsAPI']['Add'](arg1);
+}
+
+export function Delete(arg1) {
+  return window['go']['app']['ProtectedTermsAPI']['Delete'](arg1);
+}
+
+export function List(arg1) {
+  return window['go']['app']['ProtectedTermsAPI']['List'](arg1);
+}
+
+export function Test(arg1, arg2) {
+  return window['go']['app']['ProtectedTermsAPI']['Test'](arg1, arg2);
+}
@@ -2,6 +2,7 @@
 // This file is automatically generated. DO NOT EDIT
 import {domain} from '../models';
 import {app} from '../models';
+import {registry} from '../models';
 
 export function Create(arg1:domain.Provider):Promise<domain.Provider>;
 
@@ -15,4 +16,6 @@ export function ListModelsPreview(arg1:domain.Provider):Promise<Array<app.ModelI
 
 export function Test(arg1:number):Promise<app.ProviderTestResult>;
 
+export function Types():Promise<Array<registry.Descriptor>>;
+
 export function Update(arg1:domain.Provider):Promise<domain.Provider>;
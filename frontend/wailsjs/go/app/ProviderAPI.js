@@ -26,6 +26,10 @@ export function Test(arg1) {
   return window['go']['app']['ProviderAPI']['Test'](arg1);
 }
 
+export function Types() {
+  return window['go']['app']['ProviderAPI']['Types']();
+}
+
 export function Update(arg1) {
   return window['go']['app']['ProviderAPI']['Update'](arg1);
 }
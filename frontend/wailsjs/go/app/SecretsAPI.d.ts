@@ -0,0 +1,9 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {app} from '../models';
+
+export function Lock():Promise<app.SecretsStatus>;
+
+export function Status():Promise<app.SecretsStatus>;
+
+export function Unlock(arg1:string):Promise<app.SecretsStatus>;
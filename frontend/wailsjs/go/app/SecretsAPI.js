@@ -0,0 +1,15 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Lock() {
+  return window['go']['app']['SecretsAPI']['Lock']();
+}
+
+export function Status() {
+  return window['go']['app']['SecretsAPI']['Status']();
+}
+
+export function Unlock(arg1) {
+  return window['go']['app']['SecretsAPI']['Unlock'](arg1);
+}
@@ -0,0 +1,11 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {domain} from '../models';
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function Effective(arg1:number,arg2:string):Promise<domain.StyleGuide>;
+
+export function List(arg1:number):Promise<Array<domain.StyleGuide>>;
+
+export function Save(arg1:domain.StyleGuide):Promise<domain.StyleGuide>;
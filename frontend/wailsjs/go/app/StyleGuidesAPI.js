@@ -0,0 +1,19 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Delete(arg1) {
+  return window['go']['app']['StyleGuidesAPI']['Delete'](arg1);
+}
+
+export function Effective(arg1, arg2) {
+  return window['go']['app']['StyleGuidesAPI']['Effective'](arg1, arg2);
+}
+
+export function List(arg1) {
+  return window['go']['app']['StyleGuidesAPI']['List'](arg1);
+}
+
+export function Save(arg1) {
+  return window['go']['app']['StyleGuidesAPI']['Save'](arg1);
+}
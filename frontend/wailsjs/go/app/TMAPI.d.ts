@@ -0,0 +1,20 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {domain} from '../models';
+import {app} from '../models';
+
+export function Count():Promise<number>;
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function ExportTMXBase64(arg1:domain.TMFilter):Promise<app.TMXExportResponse>;
+
+export function ImportTMXBase64(arg1:string):Promise<number>;
+
+export function List(arg1:domain.TMFilter):Promise<Array<domain.TMEntry>>;
+
+export function Lookup(arg1:app.TMLookupRequest):Promise<Array<domain.TMMatch>>;
+
+export function Rebuild():Promise<number>;
+
+export function Suggest(arg1:number,arg2:string):Promise<Array<domain.TMMatch>>;
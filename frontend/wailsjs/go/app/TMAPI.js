@@ -0,0 +1,35 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Count() {
+  return window['go']['app']['TMAPI']['Count']();
+}
+
+export function Delete(arg1) {
+  return window['go']['app']['TMAPI']['Delete'](arg1);
+}
+
+export function ExportTMXBase64(arg1) {
+  return window['go']['app']['TMAPI']['ExportTMXBase64'](arg1);
+}
+
+export function ImportTMXBase64(arg1) {
+  return window['go']['app']['TMAPI']['ImportTMXBase64'](arg1);
+}
+
+export function List(arg1) {
+  return window['go']['app']['TMAPI']['List'](arg1);
+}
+
+export function Lookup(arg1) {
+  return window['go']['app']['TMAPI']['Lookup'](arg1);
+}
+
+export function Rebuild() {
+  return window['go']['app']['TMAPI']['Rebuild']();
+}
+
+export function Suggest(arg1, arg2) {
+  return window['go']['app']['TMAPI']['Suggest'](arg1, arg2);
+}
@@ -0,0 +1,18 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {domain} from '../models';
+import {app} from '../models';
+
+export function Delete(arg1:number):Promise<boolean>;
+
+export function Get(arg1:number):Promise<domain.Template>;
+
+export function List(arg1:string,arg2:any):Promise<Array<domain.Template>>;
+
+export function Preview(arg1:app.TemplatePreviewRequest):Promise<string>;
+
+export function Reset(arg1:string,arg2:any,arg3:string,arg4:string):Promise<domain.Template>;
+
+export function Save(arg1:domain.Template):Promise<domain.Template>;
+
+export function Versions(arg1:number):Promise<Array<domain.TemplateVersion>>;
@@ -0,0 +1,31 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Delete(arg1) {
+  return window['go']['app']['TemplatesAPI']['Delete'](arg1);
+}
+
+export function Get(arg1) {
+  return window['go']['app']['TemplatesAPI']['Get'](arg1);
+}
+
+export function List(arg1, arg2) {
+  return window['go']['app']['TemplatesAPI']['List'](arg1, arg2);
+}
+
+export function Preview(arg1) {
+  return window['go']['app']['TemplatesAPI']['Preview'](arg1);
+}
+
+export function Reset(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['TemplatesAPI']['Reset'](arg1, arg2, arg3, arg4);
+}
+
+export function Save(arg1) {
+  return window['go']['app']['TemplatesAPI']['Save'](arg1);
+}
+
+export function Versions(arg1) {
+  return window['go']['app']['TemplatesAPI']['Versions'](arg1);
+}
@@ -0,0 +1,6 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {domain} from '../models';
+import {app} from '../models';
+
+export function Report(arg1:domain.UsageQuery):Promise<app.UsageReport>;
@@ -1,5 +1,39 @@
 export namespace app {
 	
+	export class AddProtectedTermRequest {
+	    project_id: number;
+	    pattern: string;
+	    is_regex: boolean;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AddProtectedTermRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project_id = source["project_id"];
+	        this.pattern = source["pattern"];
+	        this.is_regex = source["is_regex"];
+	        this.note = source["note"];
+	    }
+	}
+	export class CacheExportResponse {
+	    filename: string;
+	    entries: number;
+	    content_b64: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheExportResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filename = source["filename"];
+	        this.entries = source["entries"];
+	        this.content_b64 = source["content_b64"];
+	    }
+	}
 	export class ExportFileRequest {
 	    file_id: number;
 	    locale: string;
@@ -32,6 +66,42 @@ export namespace app {
 	        this.content_b64 = source["content_b64"];
 	    }
 	}
+	export class GlossaryCheckRequest {
+	    unit_id: number;
+	    locale: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlossaryCheckRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.unit_id = source["unit_id"];
+	        this.locale = source["locale"];
+	        this.text = source["text"];
+	    }
+	}
+	export class GlossaryImportRequest {
+	    project_id: number;
+	    filename: string;
+	    format: string;
+	    src_lang: string;
+	    content_b64: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlossaryImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project_id = source["project_id"];
+	        this.filename = source["filename"];
+	        this.format = source["format"];
+	        this.src_lang = source["src_lang"];
+	        this.content_b64 = source["content_b64"];
+	    }
+	}
 	export class ImportRequest {
 	    project_id: number;
 	    filename: string;
@@ -72,6 +142,8 @@ export namespace app {
 	    status: string;
 	    progress: number;
 	    total: number;
+	    result_json?: string;
+	    usage?: domain.Usage;
 	
 	    static createFrom(source: any = {}) {
 	        return new JobDTO(source);
@@ -84,7 +156,27 @@ export namespace app {
 	        this.status = source["status"];
 	        this.progress = source["progress"];
 	        this.total = source["total"];
+	        this.result_json = source["result_json"];
+	        this.usage = this.convertValues(source["usage"], domain.Usage);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class JobItemDTO {
 	    id: number;
@@ -92,6 +184,7 @@ export namespace app {
 	    locale?: string;
 	    status: string;
 	    error: string;
+	    meta_json?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new JobItemDTO(source);
@@ -104,6 +197,7 @@ export namespace app {
 	        this.locale = source["locale"];
 	        this.status = source["status"];
 	        this.error = source["error"];
+	        this.meta_json = source["meta_json"];
 	    }
 	}
 	export class JobLogDTO {
@@ -128,6 +222,8 @@ export namespace app {
 	    Name: string;
 	    Description: string;
 	    ContextTokens: number;
+	    InputPrice: number;
+	    OutputPrice: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ModelInfo(source);
@@ -138,6 +234,22 @@ export namespace app {
 	        this.Name = source["Name"];
 	        this.Description = source["Description"];
 	        this.ContextTokens = source["ContextTokens"];
+	        this.InputPrice = source["InputPrice"];
+	        this.OutputPrice = source["OutputPrice"];
+	    }
+	}
+	export class NetworkResult {
+	    ok: boolean;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NetworkResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.warning = source["warning"];
 	    }
 	}
 	export class UnitKV {
@@ -193,6 +305,7 @@ export namespace app {
 	    translation?: string;
 	    raw?: string;
 	    error?: string;
+	    warning?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ProviderTestResult(source);
@@ -204,6 +317,51 @@ export namespace app {
 	        this.translation = source["translation"];
 	        this.raw = source["raw"];
 	        this.error = source["error"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class SecretsStatus {
+	    mode: string;
+	    initialized: boolean;
+	    locked: boolean;
+	    plaintext: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecretsStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.initialized = source["initialized"];
+	        this.locked = source["locked"];
+	        this.plaintext = source["plaintext"];
+	    }
+	}
+	export class StartDetectLanguageRequest {
+	    project_id: number;
+	    provider_id: number;
+	    file_id: number;
+	    sample_size: number;
+	    model: string;
+	    mode: string;
+	    min_confidence: number;
+	    apply_to: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartDetectLanguageRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project_id = source["project_id"];
+	        this.provider_id = source["provider_id"];
+	        this.file_id = source["file_id"];
+	        this.sample_size = source["sample_size"];
+	        this.model = source["model"];
+	        this.mode = source["mode"];
+	        this.min_confidence = source["min_confidence"];
+	        this.apply_to = source["apply_to"];
 	    }
 	}
 	export class StartJobResponse {
@@ -224,6 +382,13 @@ export namespace app {
 	    file_id: number;
 	    locales: string[];
 	    model: string;
+	    use_tm: boolean;
+	    mode: string;
+	    system_prompt: string;
+	    user_prompt: string;
+	    fallbacks: translator.Fallback[];
+	    options: number[];
+	    budget: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new StartTranslateFileRequest(source);
@@ -236,7 +401,32 @@ export namespace app {
 	        this.file_id = source["file_id"];
 	        this.locales = source["locales"];
 	        this.model = source["model"];
+	        this.use_tm = source["use_tm"];
+	        this.mode = source["mode"];
+	        this.system_prompt = source["system_prompt"];
+	        this.user_prompt = source["user_prompt"];
+	        this.fallbacks = this.convertValues(source["fallbacks"], translator.Fallback);
+	        this.options = source["options"];
+	        this.budget = source["budget"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class StartTranslateUnitRequest {
 	    project_id: number;
@@ -245,6 +435,11 @@ export namespace app {
 	    locales: string[];
 	    model: string;
 	    force: boolean;
+	    use_tm: boolean;
+	    system_prompt: string;
+	    user_prompt: string;
+	    fallbacks: translator.Fallback[];
+	    options: number[];
 	
 	    static createFrom(source: any = {}) {
 	        return new StartTranslateUnitRequest(source);
@@ -258,7 +453,30 @@ export namespace app {
 	        this.locales = source["locales"];
 	        this.model = source["model"];
 	        this.force = source["force"];
+	        this.use_tm = source["use_tm"];
+	        this.system_prompt = source["system_prompt"];
+	        this.user_prompt = source["user_prompt"];
+	        this.fallbacks = this.convertValues(source["fallbacks"], translator.Fallback);
+	        this.options = source["options"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class StartTranslateUnitsRequest {
 	    project_id: number;
@@ -267,6 +485,13 @@ export namespace app {
 	    locales: string[];
 	    model: string;
 	    force: boolean;
+	    use_tm: boolean;
+	    mode: string;
+	    system_prompt: string;
+	    user_prompt: string;
+	    fallbacks: translator.Fallback[];
+	    options: number[];
+	    budget: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new StartTranslateUnitsRequest(source);
@@ -280,6 +505,87 @@ export namespace app {
 	        this.locales = source["locales"];
 	        this.model = source["model"];
 	        this.force = source["force"];
+	        this.use_tm = source["use_tm"];
+	        this.mode = source["mode"];
+	        this.system_prompt = source["system_prompt"];
+	        this.user_prompt = source["user_prompt"];
+	        this.fallbacks = this.convertValues(source["fallbacks"], translator.Fallback);
+	        this.options = source["options"];
+	        this.budget = source["budget"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TMLookupRequest {
+	    src_lang: string;
+	    tgt_lang: string;
+	    text: string;
+	    min_score: number;
+	    max_results: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TMLookupRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.src_lang = source["src_lang"];
+	        this.tgt_lang = source["tgt_lang"];
+	        this.text = source["text"];
+	        this.min_score = source["min_score"];
+	        this.max_results = source["max_results"];
+	    }
+	}
+	export class TMXExportResponse {
+	    filename: string;
+	    entries: number;
+	    content_b64: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TMXExportResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filename = source["filename"];
+	        this.entries = source["entries"];
+	        this.content_b64 = source["content_b64"];
+	    }
+	}
+	export class TemplatePreviewRequest {
+	    type: string;
+	    role: string;
+	    body: string;
+	    unit_id: number;
+	    locale: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplatePreviewRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.role = source["role"];
+	        this.body = source["body"];
+	        this.unit_id = source["unit_id"];
+	        this.locale = source["locale"];
 	    }
 	}
 	
@@ -341,34 +647,18 @@ export namespace app {
 	        this.provider_id = source["provider_id"];
 	    }
 	}
-
-}
-
-export namespace domain {
-	
-	export class File {
-	    id: number;
-	    project_id: number;
-	    path: string;
-	    format: string;
-	    locale: string;
-	    hash: string;
-	    // Go type: time
-	    created_at: any;
+	export class UsageReport {
+	    rows: domain.UsageRow[];
+	    total: domain.Usage;
 	
 	    static createFrom(source: any = {}) {
-	        return new File(source);
+	        return new UsageReport(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.project_id = source["project_id"];
-	        this.path = source["path"];
-	        this.format = source["format"];
-	        this.locale = source["locale"];
-	        this.hash = source["hash"];
-	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.rows = this.convertValues(source["rows"], domain.UsageRow);
+	        this.total = this.convertValues(source["total"], domain.Usage);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -389,26 +679,63 @@ export namespace domain {
 		    return a;
 		}
 	}
-	export class Project {
-	    id: number;
+
+}
+
+export namespace domain {
+	
+	export class CacheBucket {
 	    name: string;
-	    source_lang: string;
+	    entries: number;
+	    hits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.entries = source["entries"];
+	        this.hits = source["hits"];
+	    }
+	}
+	export class CacheEntry {
+	    id: number;
+	    source_text: string;
+	    src_lang: string;
+	    tgt_lang: string;
+	    provider: string;
+	    model: string;
+	    template_version: string;
+	    context_hash: string;
+	    translation: string;
+	    project_id?: number;
+	    hits: number;
 	    // Go type: time
 	    created_at: any;
 	    // Go type: time
-	    updated_at: any;
+	    last_used_at?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Project(source);
+	        return new CacheEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.source_lang = source["source_lang"];
+	        this.source_text = source["source_text"];
+	        this.src_lang = source["src_lang"];
+	        this.tgt_lang = source["tgt_lang"];
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	        this.template_version = source["template_version"];
+	        this.context_hash = source["context_hash"];
+	        this.translation = source["translation"];
+	        this.project_id = source["project_id"];
+	        this.hits = source["hits"];
 	        this.created_at = this.convertValues(source["created_at"], null);
-	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.last_used_at = this.convertValues(source["last_used_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -429,23 +756,429 @@ export namespace domain {
 		    return a;
 		}
 	}
-	export class ProjectLocale {
-	    id: number;
-	    project_id: number;
-	    locale: string;
-	    // Go type: time
-	    created_at: any;
+	export class CacheFilter {
+	    project_id?: number;
+	    provider: string;
+	    model: string;
+	    tgt_lang: string;
+	    query: string;
+	    limit: number;
+	    offset: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProjectLocale(source);
+	        return new CacheFilter(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.project_id = source["project_id"];
-	        this.locale = source["locale"];
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	        this.tgt_lang = source["tgt_lang"];
+	        this.query = source["query"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class CacheStats {
+	    entries: number;
+	    hits: number;
+	    // Go type: time
+	    oldest?: any;
+	    // Go type: time
+	    newest?: any;
+	    by_model: CacheBucket[];
+	    by_locale: CacheBucket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = source["entries"];
+	        this.hits = source["hits"];
+	        this.oldest = this.convertValues(source["oldest"], null);
+	        this.newest = this.convertValues(source["newest"], null);
+	        this.by_model = this.convertValues(source["by_model"], CacheBucket);
+	        this.by_locale = this.convertValues(source["by_locale"], CacheBucket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class File {
+	    id: number;
+	    project_id: number;
+	    path: string;
+	    format: string;
+	    locale: string;
+	    hash: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new File(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.locale = source["locale"];
+	        this.hash = source["hash"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GlossaryTranslation {
+	    locale: string;
+	    text: string;
+	    forbidden: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlossaryTranslation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.locale = source["locale"];
+	        this.text = source["text"];
+	        this.forbidden = source["forbidden"];
+	    }
+	}
+	export class GlossaryTerm {
+	    id: number;
+	    project_id: number;
+	    term: string;
+	    description: string;
+	    part_of_speech: string;
+	    case_sensitive: boolean;
+	    do_not_translate: boolean;
+	    translations: GlossaryTranslation[];
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlossaryTerm(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.term = source["term"];
+	        this.description = source["description"];
+	        this.part_of_speech = source["part_of_speech"];
+	        this.case_sensitive = source["case_sensitive"];
+	        this.do_not_translate = source["do_not_translate"];
+	        this.translations = this.convertValues(source["translations"], GlossaryTranslation);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Project {
+	    id: number;
+	    name: string;
+	    source_lang: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.source_lang = source["source_lang"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProjectLocale {
+	    id: number;
+	    project_id: number;
+	    locale: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectLocale(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.locale = source["locale"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProtectedTerm {
+	    id: number;
+	    project_id: number;
+	    pattern: string;
+	    is_regex: boolean;
+	    note: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProtectedTerm(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.pattern = source["pattern"];
+	        this.is_regex = source["is_regex"];
+	        this.note = source["note"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Provider {
+	    id: number;
+	    type: string;
+	    name: string;
+	    base_url: string;
+	    model: string;
+	    api_key: string;
+	    options_json: string;
+	    concurrency: number;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Provider(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.base_url = source["base_url"];
+	        this.model = source["model"];
+	        this.api_key = source["api_key"];
+	        this.options_json = source["options_json"];
+	        this.concurrency = source["concurrency"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StyleGuide {
+	    id: number;
+	    project_id: number;
+	    locale: string;
+	    formality: string;
+	    tone: string;
+	    audience: string;
+	    punctuation: string;
+	    notes: string;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new StyleGuide(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.locale = source["locale"];
+	        this.formality = source["formality"];
+	        this.tone = source["tone"];
+	        this.audience = source["audience"];
+	        this.punctuation = source["punctuation"];
+	        this.notes = source["notes"];
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TMEntry {
+	    id: number;
+	    source_text: string;
+	    target_text: string;
+	    src_lang: string;
+	    tgt_lang: string;
+	    project_id?: number;
+	    unit_id?: number;
+	    origin: string;
+	    status: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TMEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source_text = source["source_text"];
+	        this.target_text = source["target_text"];
+	        this.src_lang = source["src_lang"];
+	        this.tgt_lang = source["tgt_lang"];
+	        this.project_id = source["project_id"];
+	        this.unit_id = source["unit_id"];
+	        this.origin = source["origin"];
+	        this.status = source["status"];
 	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -466,33 +1199,86 @@ export namespace domain {
 		    return a;
 		}
 	}
-	export class Provider {
+	export class TMFilter {
+	    project_id?: number;
+	    src_lang: string;
+	    tgt_lang: string;
+	    statuses: string[];
+	    query: string;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TMFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project_id = source["project_id"];
+	        this.src_lang = source["src_lang"];
+	        this.tgt_lang = source["tgt_lang"];
+	        this.statuses = source["statuses"];
+	        this.query = source["query"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class TMMatch {
+	    entry?: TMEntry;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TMMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], TMEntry);
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Template {
 	    id: number;
+	    scope: string;
+	    ref_id?: number;
 	    type: string;
-	    name: string;
-	    base_url: string;
-	    model: string;
-	    api_key: string;
-	    options_json: string;
-	    // Go type: time
-	    created_at: any;
+	    role: string;
+	    body: string;
+	    is_default: boolean;
 	    // Go type: time
 	    updated_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Provider(source);
+	        return new Template(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.scope = source["scope"];
+	        this.ref_id = source["ref_id"];
 	        this.type = source["type"];
-	        this.name = source["name"];
-	        this.base_url = source["base_url"];
-	        this.model = source["model"];
-	        this.api_key = source["api_key"];
-	        this.options_json = source["options_json"];
-	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.role = source["role"];
+	        this.body = source["body"];
+	        this.is_default = source["is_default"];
 	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
@@ -514,6 +1300,61 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class TemplateVersion {
+	    id: number;
+	    template_id: number;
+	    body: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.template_id = source["template_id"];
+	        this.body = source["body"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TermIssue {
+	    term: string;
+	    kind: string;
+	    expected?: string[];
+	    found?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TermIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.term = source["term"];
+	        this.kind = source["kind"];
+	        this.expected = source["expected"];
+	        this.found = source["found"];
+	    }
+	}
 	export class Unit {
 	    id: number;
 	    file_id: number;
@@ -557,11 +1398,183 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class Usage {
+	    items: number;
+	    input_tokens: number;
+	    output_tokens: number;
+	    cost: number;
+	    unpriced: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Usage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = source["items"];
+	        this.input_tokens = source["input_tokens"];
+	        this.output_tokens = source["output_tokens"];
+	        this.cost = source["cost"];
+	        this.unpriced = source["unpriced"];
+	    }
+	}
+	export class UsageQuery {
+	    from_month: string;
+	    to_month: string;
+	    project_id: number;
+	    provider_id: number;
+	    group_by: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from_month = source["from_month"];
+	        this.to_month = source["to_month"];
+	        this.project_id = source["project_id"];
+	        this.provider_id = source["provider_id"];
+	        this.group_by = source["group_by"];
+	    }
+	}
+	export class UsageRow {
+	    month?: string;
+	    project_id?: number;
+	    project_name?: string;
+	    provider_id?: number;
+	    provider_name?: string;
+	    model?: string;
+	    items: number;
+	    input_tokens: number;
+	    output_tokens: number;
+	    cost: number;
+	    unpriced: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.month = source["month"];
+	        this.project_id = source["project_id"];
+	        this.project_name = source["project_name"];
+	        this.provider_id = source["provider_id"];
+	        this.provider_name = source["provider_name"];
+	        this.model = source["model"];
+	        this.items = source["items"];
+	        this.input_tokens = source["input_tokens"];
+	        this.output_tokens = source["output_tokens"];
+	        this.cost = source["cost"];
+	        this.unpriced = source["unpriced"];
+	    }
+	}
+
+}
+
+export namespace httpclient {
+	
+	export class Network {
+	    proxy_url?: string;
+	    no_proxy?: string[];
+	    ca_file?: string;
+	    client_cert_file?: string;
+	    client_key_file?: string;
+	    insecure_skip_verify?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Network(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.proxy_url = source["proxy_url"];
+	        this.no_proxy = source["no_proxy"];
+	        this.ca_file = source["ca_file"];
+	        this.client_cert_file = source["client_cert_file"];
+	        this.client_key_file = source["client_key_file"];
+	        this.insecure_skip_verify = source["insecure_skip_verify"];
+	    }
+	}
 
 }
 
 export namespace jobs {
 	
+	export class FallbackEstimate {
+	    provider_id: number;
+	    model: string;
+	    cost?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FallbackEstimate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider_id = source["provider_id"];
+	        this.model = source["model"];
+	        this.cost = source["cost"];
+	    }
+	}
+	export class Estimate {
+	    provider_id: number;
+	    model: string;
+	    segments: number;
+	    chars: number;
+	    words: number;
+	    memory: number;
+	    outdated: number;
+	    requests: number;
+	    input_tokens: number;
+	    output_tokens: number;
+	    sampled: number;
+	    cost?: number;
+	    seconds?: number;
+	    fallbacks?: FallbackEstimate[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Estimate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider_id = source["provider_id"];
+	        this.model = source["model"];
+	        this.segments = source["segments"];
+	        this.chars = source["chars"];
+	        this.words = source["words"];
+	        this.memory = source["memory"];
+	        this.outdated = source["outdated"];
+	        this.requests = source["requests"];
+	        this.input_tokens = source["input_tokens"];
+	        this.output_tokens = source["output_tokens"];
+	        this.sampled = source["sampled"];
+	        this.cost = source["cost"];
+	        this.seconds = source["seconds"];
+	        this.fallbacks = this.convertValues(source["fallbacks"], FallbackEstimate);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class Runner {
 	
 	
@@ -577,3 +1590,127 @@ export namespace jobs {
 
 }
 
+export namespace registry {
+	
+	export class Capabilities {
+	    prompts: boolean;
+	    json_schema: boolean;
+	    batch: boolean;
+	    list_models: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Capabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.prompts = source["prompts"];
+	        this.json_schema = source["json_schema"];
+	        this.batch = source["batch"];
+	        this.list_models = source["list_models"];
+	    }
+	}
+	export class Field {
+	    name: string;
+	    label: string;
+	    kind: string;
+	    required?: boolean;
+	    placeholder?: string;
+	    choices?: string[];
+	    help?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Field(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.required = source["required"];
+	        this.placeholder = source["placeholder"];
+	        this.choices = source["choices"];
+	        this.help = source["help"];
+	    }
+	}
+	export class Descriptor {
+	    type: string;
+	    label: string;
+	    default_base_url: string;
+	    fields: Field[];
+	    options: Field[];
+	    capabilities: Capabilities;
+	
+	    static createFrom(source: any = {}) {
+	        return new Descriptor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.label = source["label"];
+	        this.default_base_url = source["default_base_url"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	        this.options = this.convertValues(source["options"], Field);
+	        this.capabilities = this.convertValues(source["capabilities"], Capabilities);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace translator {
+	
+	export class Fallback {
+	    provider_id: number;
+	    model?: string;
+	    options?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Fallback(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider_id = source["provider_id"];
+	        this.model = source["model"];
+	        this.options = source["options"];
+	    }
+	}
+	export class FewShotConfig {
+	    disabled: boolean;
+	    k: number;
+	    min_score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FewShotConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.disabled = source["disabled"];
+	        this.k = source["k"];
+	        this.min_score = source["min_score"];
+	    }
+	}
+
+}
+
@@ -13,30 +13,31 @@ type CacheRepo struct{ *Repo }
 
 func NewCacheRepo(db *sql.DB) *CacheRepo { return &CacheRepo{NewRepo(db)} }
 
-func (r *CacheRepo) Get(ctx context.Context, src, srcLang, tgtLang, provider, model string) (*domain.CacheEntry, error) {
-	q := r.SQ.Select(
-		"id",
-		"source_text",
-		"src_lang",
-		"tgt_lang",
-		"provider",
-		"model",
-		"translation",
-		"created_at",
-	).
-		From("cache").
-		Where(sq.Eq{
-			"source_text": src,
-			"src_lang":    srcLang,
-			"tgt_lang":    tgtLang,
-			"provider":    provider,
-			"model":       model,
-		}).
-		Limit(1)
-	sqlStr, args, _ := q.ToSql()
-	row := r.DB.QueryRowContext(ctx, sqlStr, args...)
+var cacheColumns = []string{
+	"id",
+	"source_text",
+	"src_lang",
+	"tgt_lang",
+	"provider",
+	"model",
+	"template_version",
+	"context_hash",
+	"translation",
+	"project_id",
+	"hits",
+	"created_at",
+	"last_used_at",
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCacheEntry(row rowScanner) (*domain.CacheEntry, error) {
 	var e domain.CacheEntry
+	var proj sql.NullInt64
 	var created string
+	var lastUsed sql.NullString
 	if err := row.Scan(
 		&e.ID,
 		&e.SourceText,
@@ -44,19 +45,67 @@ func (r *CacheRepo) Get(ctx context.Context, src, srcLang, tgtLang, provider, mo
 		&e.TgtLang,
 		&e.Provider,
 		&e.Model,
+		&e.TemplateVersion,
+		&e.ContextHash,
 		&e.Translation,
+		&proj,
+		&e.Hits,
 		&created,
+		&lastUsed,
 	); err != nil {
+		return nil, err
+	}
+	if proj.Valid {
+		v := proj.Int64
+		e.ProjectID = &v
+	}
+	e.CreatedAt = parseTime(created)
+	if lastUsed.Valid {
+		v := parseTime(lastUsed.String)
+		e.LastUsedAt = &v
+	}
+	return &e, nil
+}
+
+// parseTime accepts both RFC3339 and SQLite CURRENT_TIMESTAMP layouts.
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02 15:04:05", s)
+	return t
+}
+
+func (r *CacheRepo) Get(ctx context.Context, key domain.CacheKey) (*domain.CacheEntry, error) {
+	q := r.SQ.Select(cacheColumns...).
+		From("cache").
+		Where(sq.Eq{
+			"source_text":      key.SourceText,
+			"src_lang":         key.SrcLang,
+			"tgt_lang":         key.TgtLang,
+			"provider":         key.Provider,
+			"model":            key.Model,
+			"template_version": key.TemplateVersion,
+			"context_hash":     key.ContextHash,
+		}).
+		Limit(1)
+	sqlStr, args, _ := q.ToSql()
+	e, err := scanCacheEntry(r.DB.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	e.CreatedAt, _ = time.Parse(time.RFC3339, created)
-	return &e, nil
+	return e, nil
 }
 
 func (r *CacheRepo) Put(ctx context.Context, entry *domain.CacheEntry) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	created := now
+	if !entry.CreatedAt.IsZero() {
+		created = entry.CreatedAt.UTC().Format(time.RFC3339)
+	}
 	q := r.SQ.
 		Insert("cache").
 		Columns(
@@ -65,7 +114,11 @@ func (r *CacheRepo) Put(ctx context.Context, entry *domain.CacheEntry) error {
 			"tgt_lang",
 			"provider",
 			"model",
+			"template_version",
+			"context_hash",
 			"translation",
+			"project_id",
+			"created_at",
 		).
 		Values(
 			entry.SourceText,
@@ -73,10 +126,146 @@ func (r *CacheRepo) Put(ctx context.Context, entry *domain.CacheEntry) error {
 			entry.TgtLang,
 			entry.Provider,
 			entry.Model,
+			entry.TemplateVersion,
+			entry.ContextHash,
 			entry.Translation,
+			entry.ProjectID,
+			created,
 		).
-		Suffix("ON CONFLICT(source_text, src_lang, tgt_lang, provider, model) DO UPDATE SET translation=excluded.translation")
+		Suffix("ON CONFLICT(source_text, src_lang, tgt_lang, provider, model, template_version, context_hash) DO UPDATE SET translation=excluded.translation, project_id=COALESCE(excluded.project_id, cache.project_id), created_at=excluded.created_at")
+	sqlStr, args, _ := q.ToSql()
+	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+// Touch records a cache hit.
+func (r *CacheRepo) Touch(ctx context.Context, id int64) error {
+	q := r.SQ.Update("cache").
+		Set("hits", sq.Expr("hits + 1")).
+		Set("last_used_at", time.Now().UTC().Format(time.RFC3339)).
+		Where(sq.Eq{"id": id})
+	sqlStr, args, _ := q.ToSql()
+	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func applyCacheFilter(b sq.SelectBuilder, f domain.CacheFilter) sq.SelectBuilder {
+	if f.ProjectID != nil {
+		b = b.Where(sq.Eq{"project_id": *f.ProjectID})
+	}
+	if f.Provider != "" {
+		b = b.Where(sq.Eq{"provider": f.Provider})
+	}
+	if f.Model != "" {
+		b = b.Where(sq.Eq{"model": f.Model})
+	}
+	if f.TgtLang != "" {
+		b = b.Where(sq.Eq{"tgt_lang": f.TgtLang})
+	}
+	if f.Query != "" {
+		like := "%" + f.Query + "%"
+		b = b.Where(sq.Or{sq.Like{"source_text": like}, sq.Like{"translation": like}})
+	}
+	return b
+}
+
+func (r *CacheRepo) List(ctx context.Context, f domain.CacheFilter) ([]*domain.CacheEntry, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	q := applyCacheFilter(r.SQ.Select(cacheColumns...).From("cache"), f).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(max(f.Offset, 0)))
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.CacheEntry
+	for rows.Next() {
+		e, err := scanCacheEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *CacheRepo) Stats(ctx context.Context) (*domain.CacheStats, error) {
+	var st domain.CacheStats
+	var oldest, newest sql.NullString
+	row := r.DB.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(hits), 0), MIN(created_at), MAX(created_at) FROM cache`)
+	if err := row.Scan(&st.Entries, &st.Hits, &oldest, &newest); err != nil {
+		return nil, err
+	}
+	if oldest.Valid {
+		v := parseTime(oldest.String)
+		st.Oldest = &v
+	}
+	if newest.Valid {
+		v := parseTime(newest.String)
+		st.Newest = &v
+	}
+	var err error
+	if st.ByModel, err = r.buckets(ctx, "provider || '/' || model"); err != nil {
+		return nil, err
+	}
+	if st.ByLocale, err = r.buckets(ctx, "tgt_lang"); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *CacheRepo) buckets(ctx context.Context, expr string) ([]domain.CacheBucket, error) {
+	q := r.SQ.Select(expr+" AS name", "COUNT(*)", "COALESCE(SUM(hits), 0)").
+		From("cache").
+		GroupBy("name").
+		OrderBy("COUNT(*) DESC")
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.CacheBucket
+	for rows.Next() {
+		var b domain.CacheBucket
+		if err := rows.Scan(&b.Name, &b.Entries, &b.Hits); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (r *CacheRepo) Delete(ctx context.Context, id int64) error {
+	q := r.SQ.Delete("cache").Where(sq.Eq{"id": id})
 	sqlStr, args, _ := q.ToSql()
 	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
 	return err
 }
+
+// Purge deletes all entries matching the filter (Limit/Offset are ignored).
+func (r *CacheRepo) Purge(ctx context.Context, f domain.CacheFilter) (int64, error) {
+	sub := applyCacheFilter(r.SQ.Select("id").From("cache"), f)
+	subSQL, args, _ := sub.ToSql()
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM cache WHERE id IN ("+subSQL+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *CacheRepo) PurgeOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	q := r.SQ.Delete("cache").Where(sq.Lt{"created_at": t.UTC().Format(time.RFC3339)})
+	sqlStr, args, _ := q.ToSql()
+	res, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
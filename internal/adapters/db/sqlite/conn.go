@@ -24,7 +24,11 @@ func Init(dbPath string) (*sql.DB, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return nil, fmt.Errorf("make db dir: %w", err)
 	}
-	db, err := sql.Open("sqlite3", dbPath)
+	// Connection pragmas go into the DSN so that every pooled connection gets them: concurrent
+	// job workers wait for the write lock (busy timeout) and take it when a transaction begins
+	// instead of failing on lock upgrades.
+	dsn := dbPath + "?_foreign_keys=on&_busy_timeout=5000&_synchronous=NORMAL&_txlock=immediate"
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"locail/internal/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type GlossaryRepo struct{ *Repo }
+
+func NewGlossaryRepo(db *sql.DB) *GlossaryRepo { return &GlossaryRepo{NewRepo(db)} }
+
+var glossaryColumns = []string{
+	"id",
+	"project_id",
+	"term",
+	"description",
+	"part_of_speech",
+	"case_sensitive",
+	"do_not_translate",
+	"created_at",
+	"updated_at",
+}
+
+func scanGlossaryTerm(row rowScanner) (*domain.GlossaryTerm, error) {
+	var t domain.GlossaryTerm
+	var created, updated string
+	if err := row.Scan(
+		&t.ID,
+		&t.ProjectID,
+		&t.Term,
+		&t.Description,
+		&t.PartOfSpeech,
+		&t.CaseSensitive,
+		&t.DoNotTranslate,
+		&created,
+		&updated,
+	); err != nil {
+		return nil, err
+	}
+	t.CreatedAt = parseTime(created)
+	t.UpdatedAt = parseTime(updated)
+	return &t, nil
+}
+
+func (r *GlossaryRepo) Save(ctx context.Context, t *domain.GlossaryTerm) error {
+	now := time.Now().UTC()
+	return WithTx(ctx, r.DB, func(tx *sql.Tx) error {
+		var sqlStr string
+		var args []any
+		if t.ID != 0 {
+			sqlStr, args, _ = r.SQ.Update("glossary_terms").
+				Set("term", t.Term).
+				Set("description", t.Description).
+				Set("part_of_speech", t.PartOfSpeech).
+				Set("case_sensitive", t.CaseSensitive).
+				Set("do_not_translate", t.DoNotTranslate).
+				Set("updated_at", now.Format(time.RFC3339)).
+				Where(sq.Eq{"id": t.ID}).
+				ToSql()
+			if _, err := tx.ExecContext(ctx, sqlStr, args...); err != nil {
+				return err
+			}
+		} else {
+			sqlStr, args, _ = r.SQ.Insert("glossary_terms").
+				Columns(
+					"project_id",
+					"term",
+					"description",
+					"part_of_speech",
+					"case_sensitive",
+					"do_not_translate",
+					"created_at",
+					"updated_at",
+				).
+				Values(
+					t.ProjectID,
+					t.Term,
+					t.Description,
+					t.PartOfSpeech,
+					t.CaseSensitive,
+					t.DoNotTranslate,
+					now.Format(time.RFC3339),
+					now.Format(time.RFC3339),
+				).
+				Suffix("ON CONFLICT(project_id, term) DO UPDATE SET description=excluded.description, part_of_speech=excluded.part_of_speech, case_sensitive=excluded.case_sensitive, do_not_translate=excluded.do_not_translate, updated_at=excluded.updated_at RETURNING id").
+				ToSql()
+			if err := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&t.ID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM glossary_translations WHERE term_id = ?`, t.ID); err != nil {
+			return err
+		}
+		for _, tr := range t.Translations {
+			if _, err := tx.ExecContext(
+				ctx,
+				`INSERT OR IGNORE INTO glossary_translations(term_id, locale, text, forbidden) VALUES (?, ?, ?, ?)`,
+				t.ID,
+				tr.Locale,
+				tr.Text,
+				tr.Forbidden,
+			); err != nil {
+				return err
+			}
+		}
+		t.UpdatedAt = now
+		return nil
+	})
+}
+
+func (r *GlossaryRepo) Get(ctx context.Context, id int64) (*domain.GlossaryTerm, error) {
+	sqlStr, args, _ := r.SQ.Select(glossaryColumns...).From("glossary_terms").Where(sq.Eq{"id": id}).ToSql()
+	t, err := scanGlossaryTerm(r.DB.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, []*domain.GlossaryTerm{t}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *GlossaryRepo) ListByProject(ctx context.Context, projectID int64) ([]*domain.GlossaryTerm, error) {
+	sqlStr, args, _ := r.SQ.Select(glossaryColumns...).From("glossary_terms").
+		Where(sq.Eq{"project_id": projectID}).
+		OrderBy("term COLLATE NOCASE").
+		ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.GlossaryTerm
+	for rows.Next() {
+		t, err := scanGlossaryTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *GlossaryRepo) loadTranslations(ctx context.Context, terms []*domain.GlossaryTerm) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*domain.GlossaryTerm, len(terms))
+	ids := make([]int64, 0, len(terms))
+	for _, t := range terms {
+		byID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+	sqlStr, args, _ := r.SQ.Select("term_id", "locale", "text", "forbidden").From("glossary_translations").
+		Where(sq.Eq{"term_id": ids}).
+		OrderBy("term_id", "locale", "forbidden", "id").
+		ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var termID int64
+		var tr domain.GlossaryTranslation
+		if err := rows.Scan(&termID, &tr.Locale, &tr.Text, &tr.Forbidden); err != nil {
+			return err
+		}
+		if t := byID[termID]; t != nil {
+			t.Translations = append(t.Translations, tr)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *GlossaryRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM glossary_terms WHERE id = ?`, id)
+	return err
+}
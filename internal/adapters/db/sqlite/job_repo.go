@@ -61,6 +61,17 @@ func (r *JobRepo) UpdateProgress(ctx context.Context, jobID int64, done, total i
 	return err
 }
 
+func (r *JobRepo) SetResult(ctx context.Context, jobID int64, resultJSON string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	q := r.SQ.Update("jobs").
+		Set("result_json", resultJSON).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": jobID})
+	sqlStr, args, _ := q.ToSql()
+	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
 func (r *JobRepo) AddItem(ctx context.Context, ji *domain.JobItem) (int64, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
 	q := r.SQ.Insert("job_items").
@@ -88,6 +99,39 @@ func (r *JobRepo) UpdateItem(ctx context.Context, itemID int64, status, errMsg s
 	return err
 }
 
+func (r *JobRepo) SetItemMeta(ctx context.Context, itemID int64, metaJSON string) error {
+	q := r.SQ.Update("job_items").
+		Set("meta_json", metaJSON).
+		Where(sq.Eq{"id": itemID})
+	sqlStr, args, _ := q.ToSql()
+	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func (r *JobRepo) SetItemUsage(ctx context.Context, itemID, providerID int64, model string, inputTokens, outputTokens int, cost *float64) error {
+	q := r.SQ.Update("job_items").
+		Set("provider_id", providerID).
+		Set("model", model).
+		Set("input_tokens", inputTokens).
+		Set("output_tokens", outputTokens).
+		Set("cost", cost).
+		Where(sq.Eq{"id": itemID})
+	sqlStr, args, _ := q.ToSql()
+	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func (r *JobRepo) Usage(ctx context.Context, jobID int64) (domain.Usage, error) {
+	q := r.SQ.Select(usageColumns("")...).
+		From("job_items").
+		Where(sq.Eq{"job_id": jobID}).
+		Where(sq.NotEq{"provider_id": nil})
+	sqlStr, args, _ := q.ToSql()
+	var u domain.Usage
+	err := r.DB.QueryRowContext(ctx, sqlStr, args...).Scan(&u.Items, &u.InputTokens, &u.OutputTokens, &u.Cost, &u.Unpriced)
+	return u, err
+}
+
 func (r *JobRepo) AddLog(ctx context.Context, jl *domain.JobLog) error {
 	q := r.SQ.Insert("job_logs").
 		Columns("job_id", "ts", "level", "message").
@@ -105,6 +149,7 @@ func (r *JobRepo) Get(ctx context.Context, jobID int64) (*domain.Job, error) {
 		"project_id",
 		"provider_id",
 		"params_json",
+		"COALESCE(result_json, '')",
 		"progress",
 		"total",
 		"created_at",
@@ -125,6 +170,7 @@ func (r *JobRepo) Get(ctx context.Context, jobID int64) (*domain.Job, error) {
 		&proj,
 		&prov,
 		&j.ParamsRaw,
+		&j.ResultRaw,
 		&j.Progress,
 		&j.Total,
 		&created,
@@ -159,6 +205,7 @@ func (r *JobRepo) List(ctx context.Context, limit int) ([]*domain.Job, error) {
 		"project_id",
 		"provider_id",
 		"params_json",
+		"COALESCE(result_json, '')",
 		"progress",
 		"total",
 		"created_at",
@@ -185,6 +232,7 @@ func (r *JobRepo) List(ctx context.Context, limit int) ([]*domain.Job, error) {
 			&proj,
 			&prov,
 			&j.ParamsRaw,
+			&j.ResultRaw,
 			&j.Progress,
 			&j.Total,
 			&created,
@@ -208,7 +256,7 @@ func (r *JobRepo) List(ctx context.Context, limit int) ([]*domain.Job, error) {
 }
 
 func (r *JobRepo) ListItems(ctx context.Context, jobID int64) ([]*domain.JobItem, error) {
-	q := r.SQ.Select("id", "job_id", "unit_id", "locale", "status", "error", "created_at", "updated_at").
+	q := r.SQ.Select("id", "job_id", "unit_id", "locale", "status", "COALESCE(error, '')", "COALESCE(meta_json, '')", "created_at", "updated_at").
 		From("job_items").
 		Where(sq.Eq{"job_id": jobID}).
 		OrderBy("id")
@@ -224,7 +272,7 @@ func (r *JobRepo) ListItems(ctx context.Context, jobID int64) ([]*domain.JobItem
 		var unit sql.NullInt64
 		var loc sql.NullString
 		var created, updated string
-		if err := rows.Scan(&ji.ID, &ji.JobID, &unit, &loc, &ji.Status, &ji.Error, &created, &updated); err != nil {
+		if err := rows.Scan(&ji.ID, &ji.JobID, &unit, &loc, &ji.Status, &ji.Error, &ji.MetaRaw, &created, &updated); err != nil {
 			return nil, err
 		}
 		if unit.Valid {
@@ -0,0 +1,22 @@
+-- cache v2: entries keyed by masked source/translation plus prompt template version.
+-- Existing rows stored unmasked translations under masked keys and cannot be trusted, so they are dropped.
+DROP TABLE IF EXISTS cache;
+
+CREATE TABLE IF NOT EXISTS cache (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  source_text TEXT NOT NULL,
+  src_lang TEXT NOT NULL,
+  tgt_lang TEXT NOT NULL,
+  provider TEXT NOT NULL DEFAULT '',
+  model TEXT NOT NULL DEFAULT '',
+  template_version TEXT NOT NULL DEFAULT '',
+  translation TEXT NOT NULL,
+  project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL,
+  hits INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  last_used_at TEXT,
+  UNIQUE(source_text, src_lang, tgt_lang, provider, model, template_version)
+);
+CREATE INDEX IF NOT EXISTS idx_cache_project ON cache(project_id);
+CREATE INDEX IF NOT EXISTS idx_cache_model ON cache(provider, model);
+CREATE INDEX IF NOT EXISTS idx_cache_tgt ON cache(tgt_lang);
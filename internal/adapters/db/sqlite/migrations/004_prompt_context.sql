@@ -0,0 +1,24 @@
+-- cache entries are additionally keyed by a digest of the prompt context (project, file, key, neighbours)
+CREATE TABLE cache_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  source_text TEXT NOT NULL,
+  src_lang TEXT NOT NULL,
+  tgt_lang TEXT NOT NULL,
+  provider TEXT NOT NULL DEFAULT '',
+  model TEXT NOT NULL DEFAULT '',
+  template_version TEXT NOT NULL DEFAULT '',
+  context_hash TEXT NOT NULL DEFAULT '',
+  translation TEXT NOT NULL,
+  project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL,
+  hits INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  last_used_at TEXT,
+  UNIQUE(source_text, src_lang, tgt_lang, provider, model, template_version, context_hash)
+);
+INSERT INTO cache_new(id, source_text, src_lang, tgt_lang, provider, model, template_version, translation, project_id, hits, created_at, last_used_at)
+  SELECT id, source_text, src_lang, tgt_lang, provider, model, template_version, translation, project_id, hits, created_at, last_used_at FROM cache;
+DROP TABLE cache;
+ALTER TABLE cache_new RENAME TO cache;
+CREATE INDEX IF NOT EXISTS idx_cache_project ON cache(project_id);
+CREATE INDEX IF NOT EXISTS idx_cache_model ON cache(provider, model);
+CREATE INDEX IF NOT EXISTS idx_cache_tgt ON cache(tgt_lang);
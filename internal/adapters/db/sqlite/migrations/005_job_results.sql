@@ -0,0 +1,2 @@
+-- jobs can store a structured result (e.g. detected language)
+ALTER TABLE jobs ADD COLUMN result_json TEXT;
@@ -0,0 +1,20 @@
+-- translation memory: approved source/target pairs shared across projects
+CREATE TABLE IF NOT EXISTS tm_entries (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  source_text TEXT NOT NULL,
+  target_text TEXT NOT NULL,
+  source_norm TEXT NOT NULL,
+  src_lang TEXT NOT NULL DEFAULT '',
+  tgt_lang TEXT NOT NULL,
+  project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL,
+  unit_id INTEGER REFERENCES units(id) ON DELETE SET NULL,
+  origin TEXT NOT NULL DEFAULT 'translation',
+  status TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  updated_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  UNIQUE(source_text, target_text, src_lang, tgt_lang)
+);
+CREATE INDEX IF NOT EXISTS idx_tm_langs ON tm_entries(tgt_lang, src_lang);
+CREATE INDEX IF NOT EXISTS idx_tm_unit ON tm_entries(unit_id, tgt_lang);
+CREATE INDEX IF NOT EXISTS idx_tm_source ON tm_entries(source_text);
+CREATE INDEX IF NOT EXISTS idx_tm_norm ON tm_entries(source_norm, tgt_lang);
@@ -0,0 +1,24 @@
+-- project glossary (termbase)
+CREATE TABLE IF NOT EXISTS glossary_terms (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  term TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  part_of_speech TEXT NOT NULL DEFAULT '',
+  case_sensitive INTEGER NOT NULL DEFAULT 0,
+  do_not_translate INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  updated_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  UNIQUE(project_id, term)
+);
+
+-- approved (forbidden = 0) and forbidden (forbidden = 1) translations of a term per locale
+CREATE TABLE IF NOT EXISTS glossary_translations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  term_id INTEGER NOT NULL REFERENCES glossary_terms(id) ON DELETE CASCADE,
+  locale TEXT NOT NULL,
+  text TEXT NOT NULL,
+  forbidden INTEGER NOT NULL DEFAULT 0,
+  UNIQUE(term_id, locale, text)
+);
+CREATE INDEX IF NOT EXISTS idx_glossary_translations_term ON glossary_translations(term_id);
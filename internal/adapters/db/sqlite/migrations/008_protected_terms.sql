@@ -0,0 +1,10 @@
+-- per-project do-not-translate terms masked like placeholders (literal text or regular expressions)
+CREATE TABLE IF NOT EXISTS protected_terms (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pattern TEXT NOT NULL,
+  is_regex INTEGER NOT NULL DEFAULT 0,
+  note TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  UNIQUE(project_id, pattern, is_regex)
+);
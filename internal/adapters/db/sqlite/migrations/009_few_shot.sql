@@ -0,0 +1,4 @@
+-- job items can store structured details of how an item was produced (e.g. few-shot examples used)
+ALTER TABLE job_items ADD COLUMN meta_json TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tm_project ON tm_entries(project_id, tgt_lang);
@@ -0,0 +1,13 @@
+-- style guides per project; locale '' applies to all target locales
+CREATE TABLE IF NOT EXISTS style_guides (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  locale TEXT NOT NULL DEFAULT '',
+  formality TEXT NOT NULL DEFAULT '',
+  tone TEXT NOT NULL DEFAULT '',
+  audience TEXT NOT NULL DEFAULT '',
+  punctuation TEXT NOT NULL DEFAULT '',
+  notes TEXT NOT NULL DEFAULT '',
+  updated_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  UNIQUE(project_id, locale)
+);
@@ -0,0 +1,18 @@
+-- previous bodies of templates, recorded on every change
+CREATE TABLE IF NOT EXISTS template_versions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  template_id INTEGER NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP)
+);
+CREATE INDEX IF NOT EXISTS idx_template_versions_template ON template_versions(template_id);
+
+-- keep the newest row per (scope, ref_id, type, role) and move older duplicates into history
+INSERT INTO template_versions(template_id, body, created_at)
+  SELECT keep.id, old.body, old.updated_at
+  FROM templates old
+  JOIN (SELECT MAX(id) AS id, scope, COALESCE(ref_id, 0) AS ref, type, role FROM templates GROUP BY scope, COALESCE(ref_id, 0), type, role) keep
+    ON keep.scope = old.scope AND keep.ref = COALESCE(old.ref_id, 0) AND keep.type = old.type AND keep.role = old.role
+  WHERE old.id <> keep.id;
+DELETE FROM templates WHERE id NOT IN (SELECT MAX(id) FROM templates GROUP BY scope, COALESCE(ref_id, 0), type, role);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_templates_key ON templates(scope, COALESCE(ref_id, 0), type, role);
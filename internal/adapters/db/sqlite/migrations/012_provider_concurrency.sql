@@ -0,0 +1,2 @@
+-- number of parallel requests a provider accepts from translate jobs
+ALTER TABLE providers ADD COLUMN concurrency INTEGER NOT NULL DEFAULT 2;
@@ -0,0 +1,12 @@
+-- token usage and cost of translated items; cost is USD, NULL when the price is unknown
+ALTER TABLE job_items ADD COLUMN provider_id INTEGER REFERENCES providers(id) ON DELETE SET NULL;
+ALTER TABLE job_items ADD COLUMN model TEXT;
+ALTER TABLE job_items ADD COLUMN input_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE job_items ADD COLUMN output_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE job_items ADD COLUMN cost REAL;
+CREATE INDEX IF NOT EXISTS idx_job_items_provider ON job_items(provider_id, created_at);
+
+-- model prices in USD per million tokens, as listed by the provider
+ALTER TABLE provider_models ADD COLUMN context_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE provider_models ADD COLUMN input_price REAL NOT NULL DEFAULT 0;
+ALTER TABLE provider_models ADD COLUMN output_price REAL NOT NULL DEFAULT 0;
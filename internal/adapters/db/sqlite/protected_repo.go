@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"locail/internal/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type ProtectedTermRepo struct{ *Repo }
+
+func NewProtectedTermRepo(db *sql.DB) *ProtectedTermRepo { return &ProtectedTermRepo{NewRepo(db)} }
+
+func (r *ProtectedTermRepo) Create(ctx context.Context, t *domain.ProtectedTerm) error {
+	now := time.Now().UTC()
+	q := r.SQ.Insert("protected_terms").Columns("project_id", "pattern", "is_regex", "note", "created_at").
+		Values(t.ProjectID, t.Pattern, t.IsRegex, t.Note, now.Format(time.RFC3339)).
+		Suffix("ON CONFLICT(project_id, pattern, is_regex) DO UPDATE SET note=excluded.note RETURNING id, created_at")
+	sqlStr, args, _ := q.ToSql()
+	var created string
+	if err := r.DB.QueryRowContext(ctx, sqlStr, args...).Scan(&t.ID, &created); err != nil {
+		return err
+	}
+	t.CreatedAt = parseTime(created)
+	return nil
+}
+
+func (r *ProtectedTermRepo) ListByProject(ctx context.Context, projectID int64) ([]*domain.ProtectedTerm, error) {
+	q := r.SQ.Select("id", "project_id", "pattern", "is_regex", "note", "created_at").From("protected_terms").
+		Where(sq.Eq{"project_id": projectID}).OrderBy("id")
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.ProtectedTerm
+	for rows.Next() {
+		var t domain.ProtectedTerm
+		var created string
+		if err := rows.Scan(&t.ID, &t.ProjectID, &t.Pattern, &t.IsRegex, &t.Note, &created); err != nil {
+			return nil, err
+		}
+		t.CreatedAt = parseTime(created)
+		out = append(out, &t)
+	}
+	return out, rows.Err()
+}
+
+func (r *ProtectedTermRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM protected_terms WHERE id = ?`, id)
+	return err
+}
@@ -14,8 +14,8 @@ func NewProviderRepo(db *sql.DB) *ProviderRepo { return &ProviderRepo{NewRepo(db
 
 func (r *ProviderRepo) Create(ctx context.Context, p *domain.Provider) error {
 	now := time.Now().UTC().Format(time.RFC3339)
-	q := r.SQ.Insert("providers").Columns("type", "name", "base_url", "model", "api_key", "options_json", "created_at", "updated_at").
-		Values(p.Type, p.Name, p.BaseURL, p.Model, p.APIKey, p.OptionsRaw, now, now)
+	q := r.SQ.Insert("providers").Columns("type", "name", "base_url", "model", "api_key", "options_json", "concurrency", "created_at", "updated_at").
+		Values(p.Type, p.Name, p.BaseURL, p.Model, p.APIKey, p.OptionsRaw, p.Concurrency, now, now)
 	sqlStr, args, _ := q.ToSql()
 	res, err := r.DB.ExecContext(ctx, sqlStr, args...)
 	if err != nil {
@@ -29,20 +29,23 @@ func (r *ProviderRepo) Create(ctx context.Context, p *domain.Provider) error {
 func (r *ProviderRepo) Update(ctx context.Context, p *domain.Provider) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 	q := r.SQ.Update("providers").
-		Set("type", p.Type).Set("name", p.Name).Set("base_url", p.BaseURL).Set("model", p.Model).Set("api_key", p.APIKey).Set("options_json", p.OptionsRaw).Set("updated_at", now).
+		Set("type", p.Type).Set("name", p.Name).Set("base_url", p.BaseURL).Set("model", p.Model).Set("options_json", p.OptionsRaw).Set("concurrency", p.Concurrency).Set("updated_at", now).
 		Where(sq.Eq{"id": p.ID})
+	if !domain.IsMaskedKey(p.APIKey) {
+		q = q.Set("api_key", p.APIKey)
+	}
 	sqlStr, args, _ := q.ToSql()
 	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
 	return err
 }
 
 func (r *ProviderRepo) Get(ctx context.Context, id int64) (*domain.Provider, error) {
-	q := r.SQ.Select("id", "type", "name", "base_url", "model", "api_key", "options_json", "created_at", "updated_at").From("providers").Where(sq.Eq{"id": id})
+	q := r.SQ.Select("id", "type", "name", "base_url", "model", "api_key", "options_json", "concurrency", "created_at", "updated_at").From("providers").Where(sq.Eq{"id": id})
 	sqlStr, args, _ := q.ToSql()
 	row := r.DB.QueryRowContext(ctx, sqlStr, args...)
 	var p domain.Provider
 	var created, updated string
-	if err := row.Scan(&p.ID, &p.Type, &p.Name, &p.BaseURL, &p.Model, &p.APIKey, &p.OptionsRaw, &created, &updated); err != nil {
+	if err := row.Scan(&p.ID, &p.Type, &p.Name, &p.BaseURL, &p.Model, &p.APIKey, &p.OptionsRaw, &p.Concurrency, &created, &updated); err != nil {
 		return nil, err
 	}
 	p.CreatedAt, _ = time.Parse(time.RFC3339, created)
@@ -51,7 +54,7 @@ func (r *ProviderRepo) Get(ctx context.Context, id int64) (*domain.Provider, err
 }
 
 func (r *ProviderRepo) List(ctx context.Context) ([]*domain.Provider, error) {
-	q := r.SQ.Select("id", "type", "name", "base_url", "model", "api_key", "options_json", "created_at", "updated_at").From("providers").OrderBy("id DESC")
+	q := r.SQ.Select("id", "type", "name", "base_url", "model", "api_key", "options_json", "concurrency", "created_at", "updated_at").From("providers").OrderBy("id DESC")
 	sqlStr, args, _ := q.ToSql()
 	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
 	if err != nil {
@@ -62,7 +65,7 @@ func (r *ProviderRepo) List(ctx context.Context) ([]*domain.Provider, error) {
 	for rows.Next() {
 		var p domain.Provider
 		var created, updated string
-		if err := rows.Scan(&p.ID, &p.Type, &p.Name, &p.BaseURL, &p.Model, &p.APIKey, &p.OptionsRaw, &created, &updated); err != nil {
+		if err := rows.Scan(&p.ID, &p.Type, &p.Name, &p.BaseURL, &p.Model, &p.APIKey, &p.OptionsRaw, &p.Concurrency, &created, &updated); err != nil {
 			return nil, err
 		}
 		p.CreatedAt, _ = time.Parse(time.RFC3339, created)
@@ -79,19 +82,25 @@ func (r *ProviderRepo) Delete(ctx context.Context, id int64) error {
 	return err
 }
 
-func (r *ProviderRepo) SaveModelCache(ctx context.Context, providerID int64, names []string) error {
+func (r *ProviderRepo) SaveModelCache(ctx context.Context, providerID int64, models []*domain.ProviderModel) error {
 	// simple approach: delete existing then insert
 	del := r.SQ.Delete("provider_models").Where(sq.Eq{"provider_id": providerID})
 	sqlStr, args, _ := del.ToSql()
 	if _, err := r.DB.ExecContext(ctx, sqlStr, args...); err != nil {
 		return err
 	}
-	if len(names) == 0 {
+	if len(models) == 0 {
 		return nil
 	}
-	ib := r.SQ.Insert("provider_models").Columns("provider_id", "name")
-	for _, n := range names {
-		ib = ib.Values(providerID, n)
+	now := time.Now().UTC().Format(time.RFC3339)
+	ib := r.SQ.Insert("provider_models").Columns("provider_id", "name", "context_tokens", "input_price", "output_price", "updated_at")
+	seen := map[string]bool{}
+	for _, m := range models {
+		if seen[m.Name] {
+			continue
+		}
+		seen[m.Name] = true
+		ib = ib.Values(providerID, m.Name, m.ContextTokens, m.InputPrice, m.OutputPrice, now)
 	}
 	sqlStr, args, _ = ib.ToSql()
 	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
@@ -99,7 +108,7 @@ func (r *ProviderRepo) SaveModelCache(ctx context.Context, providerID int64, nam
 }
 
 func (r *ProviderRepo) ListModelCache(ctx context.Context, providerID int64) ([]*domain.ProviderModel, error) {
-	q := r.SQ.Select("id", "provider_id", "name", "updated_at").From("provider_models").Where(sq.Eq{"provider_id": providerID}).OrderBy("name")
+	q := r.SQ.Select("id", "provider_id", "name", "context_tokens", "input_price", "output_price", "updated_at").From("provider_models").Where(sq.Eq{"provider_id": providerID}).OrderBy("name")
 	sqlStr, args, _ := q.ToSql()
 	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
 	if err != nil {
@@ -110,7 +119,7 @@ func (r *ProviderRepo) ListModelCache(ctx context.Context, providerID int64) ([]
 	for rows.Next() {
 		var pm domain.ProviderModel
 		var updated string
-		if err := rows.Scan(&pm.ID, &pm.ProviderID, &pm.Name, &updated); err != nil {
+		if err := rows.Scan(&pm.ID, &pm.ProviderID, &pm.Name, &pm.ContextTokens, &pm.InputPrice, &pm.OutputPrice, &updated); err != nil {
 			return nil, err
 		}
 		pm.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"locail/internal/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type StyleGuideRepo struct{ *Repo }
+
+func NewStyleGuideRepo(db *sql.DB) *StyleGuideRepo { return &StyleGuideRepo{NewRepo(db)} }
+
+func (r *StyleGuideRepo) Upsert(ctx context.Context, g *domain.StyleGuide) error {
+	now := time.Now().UTC()
+	q := r.SQ.Insert("style_guides").
+		Columns("project_id", "locale", "formality", "tone", "audience", "punctuation", "notes", "updated_at").
+		Values(g.ProjectID, g.Locale, g.Formality, g.Tone, g.Audience, g.Punctuation, g.Notes, now.Format(time.RFC3339)).
+		Suffix("ON CONFLICT(project_id, locale) DO UPDATE SET formality=excluded.formality, tone=excluded.tone, audience=excluded.audience, punctuation=excluded.punctuation, notes=excluded.notes, updated_at=excluded.updated_at RETURNING id")
+	sqlStr, args, _ := q.ToSql()
+	if err := r.DB.QueryRowContext(ctx, sqlStr, args...).Scan(&g.ID); err != nil {
+		return err
+	}
+	g.UpdatedAt = now
+	return nil
+}
+
+func (r *StyleGuideRepo) ListByProject(ctx context.Context, projectID int64) ([]*domain.StyleGuide, error) {
+	q := r.SQ.Select("id", "project_id", "locale", "formality", "tone", "audience", "punctuation", "notes", "updated_at").
+		From("style_guides").
+		Where(sq.Eq{"project_id": projectID}).
+		OrderBy("locale")
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.StyleGuide
+	for rows.Next() {
+		var g domain.StyleGuide
+		var updated string
+		if err := rows.Scan(&g.ID, &g.ProjectID, &g.Locale, &g.Formality, &g.Tone, &g.Audience, &g.Punctuation, &g.Notes, &updated); err != nil {
+			return nil, err
+		}
+		g.UpdatedAt = parseTime(updated)
+		out = append(out, &g)
+	}
+	return out, rows.Err()
+}
+
+func (r *StyleGuideRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM style_guides WHERE id = ?`, id)
+	return err
+}
@@ -5,31 +5,58 @@ import (
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
 	"locail/internal/domain"
+	"locail/internal/ports"
+	"time"
 )
 
 type TemplateRepo struct{ *Repo }
 
 func NewTemplateRepo(db *sql.DB) *TemplateRepo { return &TemplateRepo{NewRepo(db)} }
 
-// GetEffective returns provider -> project -> global -> builtin (nil if none in DB).
-func (r *TemplateRepo) GetEffective(ctx context.Context, scope string, refID *int64, typ, role string) (*domain.Template, error) {
-	// Try exact scope first if refID is provided
-	if (scope == "provider" || scope == "project") && refID != nil {
-		t, err := r.getOne(ctx, scope, refID, typ, role)
-		if err == nil && t != nil {
+var templateColumns = []string{"id", "scope", "ref_id", "type", "role", "body", "is_default", "updated_at"}
+
+func scanTemplate(row rowScanner) (*domain.Template, error) {
+	var t domain.Template
+	var ref sql.NullInt64
+	var updated string
+	if err := row.Scan(&t.ID, &t.Scope, &ref, &t.Type, &t.Role, &t.Body, &t.IsDefault, &updated); err != nil {
+		return nil, err
+	}
+	if ref.Valid {
+		v := ref.Int64
+		t.RefID = &v
+	}
+	t.UpdatedAt = parseTime(updated)
+	return &t, nil
+}
+
+// GetEffective returns provider -> project -> global (nil if none in DB).
+func (r *TemplateRepo) GetEffective(ctx context.Context, scope ports.TemplateScope, typ, role string) (*domain.Template, error) {
+	levels := []struct {
+		scope string
+		ref   *int64
+	}{
+		{"provider", scope.ProviderID},
+		{"project", scope.ProjectID},
+		{"global", nil},
+	}
+	for _, l := range levels {
+		if l.scope != "global" && l.ref == nil {
+			continue
+		}
+		t, err := r.getOne(ctx, l.scope, l.ref, typ, role)
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
 			return t, nil
 		}
 	}
-	// Fallback to global
-	t, err := r.getOne(ctx, "global", nil, typ, role)
-	if err == nil && t != nil {
-		return t, nil
-	}
-	return nil, err
+	return nil, nil
 }
 
 func (r *TemplateRepo) getOne(ctx context.Context, scope string, refID *int64, typ, role string) (*domain.Template, error) {
-	b := r.SQ.Select("id", "scope", "ref_id", "type", "role", "body", "is_default", "updated_at").From("templates").
+	b := r.SQ.Select(templateColumns...).From("templates").
 		Where(sq.Eq{"scope": scope, "type": typ, "role": role}).
 		OrderBy("id DESC").Limit(1)
 	if refID != nil {
@@ -38,28 +65,123 @@ func (r *TemplateRepo) getOne(ctx context.Context, scope string, refID *int64, t
 		b = b.Where("ref_id IS NULL")
 	}
 	sqlStr, args, _ := b.ToSql()
-	row := r.DB.QueryRowContext(ctx, sqlStr, args...)
-	var t domain.Template
-	var ref sql.NullInt64
-	var updated string
-	if err := row.Scan(&t.ID, &t.Scope, &ref, &t.Type, &t.Role, &t.Body, &t.IsDefault, &updated); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
+	t, err := scanTemplate(r.DB.QueryRowContext(ctx, sqlStr, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// Upsert saves a template by (scope, ref_id, type, role). When a template already exists its
+// previous body is kept in template_versions.
+func (r *TemplateRepo) Upsert(ctx context.Context, t *domain.Template) error {
+	now := time.Now().UTC()
+	return WithTx(ctx, r.DB, func(tx *sql.Tx) error {
+		b := r.SQ.Select("id", "body").From("templates").
+			Where(sq.Eq{"scope": t.Scope, "type": t.Type, "role": t.Role})
+		if t.RefID != nil {
+			b = b.Where(sq.Eq{"ref_id": *t.RefID})
+		} else {
+			b = b.Where("ref_id IS NULL")
+		}
+		sqlStr, args, _ := b.ToSql()
+		var id int64
+		var body string
+		err := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&id, &body)
+		switch {
+		case err == sql.ErrNoRows:
+			q := r.SQ.Insert("templates").Columns("scope", "ref_id", "type", "role", "body", "is_default", "updated_at").
+				Values(t.Scope, t.RefID, t.Type, t.Role, t.Body, t.IsDefault, now.Format(time.RFC3339))
+			sqlStr, args, _ = q.ToSql()
+			res, err := tx.ExecContext(ctx, sqlStr, args...)
+			if err != nil {
+				return err
+			}
+			t.ID, _ = res.LastInsertId()
+		case err != nil:
+			return err
+		default:
+			t.ID = id
+			if body != t.Body {
+				if _, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO template_versions(template_id, body, created_at) VALUES (?, ?, ?)`,
+					id,
+					body,
+					now.Format(time.RFC3339),
+				); err != nil {
+					return err
+				}
+			}
+			q := r.SQ.Update("templates").
+				Set("body", t.Body).
+				Set("is_default", t.IsDefault).
+				Set("updated_at", now.Format(time.RFC3339)).
+				Where(sq.Eq{"id": id})
+			sqlStr, args, _ = q.ToSql()
+			if _, err := tx.ExecContext(ctx, sqlStr, args...); err != nil {
+				return err
+			}
 		}
+		t.UpdatedAt = now
+		return nil
+	})
+}
+
+func (r *TemplateRepo) Get(ctx context.Context, id int64) (*domain.Template, error) {
+	sqlStr, args, _ := r.SQ.Select(templateColumns...).From("templates").Where(sq.Eq{"id": id}).ToSql()
+	return scanTemplate(r.DB.QueryRowContext(ctx, sqlStr, args...))
+}
+
+func (r *TemplateRepo) List(ctx context.Context, scope string, refID *int64) ([]*domain.Template, error) {
+	b := r.SQ.Select(templateColumns...).From("templates")
+	if scope != "" {
+		b = b.Where(sq.Eq{"scope": scope})
+	}
+	if refID != nil {
+		b = b.Where(sq.Eq{"ref_id": *refID})
+	}
+	sqlStr, args, _ := b.OrderBy("scope", "ref_id", "type", "role").ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
 		return nil, err
 	}
-	if ref.Valid {
-		v := ref.Int64
-		t.RefID = &v
+	defer rows.Close()
+	var out []*domain.Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
 	}
-	return &t, nil
+	return out, rows.Err()
 }
 
-func (r *TemplateRepo) Upsert(ctx context.Context, t *domain.Template) error {
-	// Simple upsert by unique key (scope, ref_id, type, role) if we had constraint; we don't, so insert new row
-	q := r.SQ.Insert("templates").Columns("scope", "ref_id", "type", "role", "body", "is_default").
-		Values(t.Scope, t.RefID, t.Type, t.Role, t.Body, t.IsDefault)
-	sqlStr, args, _ := q.ToSql()
-	_, err := r.DB.ExecContext(ctx, sqlStr, args...)
+func (r *TemplateRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM templates WHERE id = ?`, id)
 	return err
 }
+
+func (r *TemplateRepo) ListVersions(ctx context.Context, templateID int64) ([]*domain.TemplateVersion, error) {
+	q := r.SQ.Select("id", "template_id", "body", "created_at").From("template_versions").
+		Where(sq.Eq{"template_id": templateID}).
+		OrderBy("id DESC")
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.TemplateVersion
+	for rows.Next() {
+		var v domain.TemplateVersion
+		var created string
+		if err := rows.Scan(&v.ID, &v.TemplateID, &v.Body, &created); err != nil {
+			return nil, err
+		}
+		v.CreatedAt = parseTime(created)
+		out = append(out, &v)
+	}
+	return out, rows.Err()
+}
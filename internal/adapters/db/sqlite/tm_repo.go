@@ -0,0 +1,352 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"locail/internal/domain"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// TMRepo stores translation memory entries. When the SQLite build includes FTS5
+// (build tag sqlite_fts5) a trigram index is used for candidate retrieval;
+// otherwise candidates are narrowed by length and a LIKE on the longest word.
+type TMRepo struct {
+	*Repo
+	fts bool
+}
+
+func NewTMRepo(db *sql.DB) *TMRepo {
+	r := &TMRepo{Repo: NewRepo(db)}
+	r.fts = r.ensureFTS()
+	return r
+}
+
+// ensureFTS creates the FTS5 index outside of migrations, since FTS5 availability
+// depends on how the binary was built.
+func (r *TMRepo) ensureFTS() bool {
+	if _, err := r.DB.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS tm_fts USING fts5(source_norm, tokenize='trigram')`); err != nil {
+		return false
+	}
+	var n int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM tm_fts`).Scan(&n); err != nil {
+		return false
+	}
+	var m int
+	_ = r.DB.QueryRow(`SELECT COUNT(*) FROM tm_entries`).Scan(&m)
+	if n != m {
+		// Index missing or stale (e.g. previously running without FTS5): rebuild it.
+		_, _ = r.DB.Exec(`DELETE FROM tm_fts`)
+		_, _ = r.DB.Exec(`INSERT INTO tm_fts(rowid, source_norm) SELECT id, source_norm FROM tm_entries`)
+	}
+	return true
+}
+
+// tmNorm lower-cases and collapses whitespace for matching.
+func tmNorm(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+var tmColumns = []string{
+	"id",
+	"source_text",
+	"target_text",
+	"src_lang",
+	"tgt_lang",
+	"project_id",
+	"unit_id",
+	"origin",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+func scanTMEntry(row rowScanner) (*domain.TMEntry, error) {
+	var e domain.TMEntry
+	var proj, unit sql.NullInt64
+	var created, updated string
+	if err := row.Scan(
+		&e.ID,
+		&e.SourceText,
+		&e.TargetText,
+		&e.SrcLang,
+		&e.TgtLang,
+		&proj,
+		&unit,
+		&e.Origin,
+		&e.Status,
+		&created,
+		&updated,
+	); err != nil {
+		return nil, err
+	}
+	if proj.Valid {
+		v := proj.Int64
+		e.ProjectID = &v
+	}
+	if unit.Valid {
+		v := unit.Int64
+		e.UnitID = &v
+	}
+	e.CreatedAt = parseTime(created)
+	e.UpdatedAt = parseTime(updated)
+	return &e, nil
+}
+
+func (r *TMRepo) Put(ctx context.Context, e *domain.TMEntry) error {
+	now := time.Now().UTC()
+	created := now
+	if !e.CreatedAt.IsZero() {
+		created = e.CreatedAt.UTC()
+	}
+	if e.Origin == "" {
+		e.Origin = "translation"
+	}
+	return WithTx(ctx, r.DB, func(tx *sql.Tx) error {
+		q := r.SQ.Insert("tm_entries").
+			Columns(
+				"source_text",
+				"target_text",
+				"source_norm",
+				"src_lang",
+				"tgt_lang",
+				"project_id",
+				"unit_id",
+				"origin",
+				"status",
+				"created_at",
+				"updated_at",
+			).
+			Values(
+				e.SourceText,
+				e.TargetText,
+				tmNorm(e.SourceText),
+				e.SrcLang,
+				e.TgtLang,
+				e.ProjectID,
+				e.UnitID,
+				e.Origin,
+				e.Status,
+				created.Format(time.RFC3339),
+				now.Format(time.RFC3339),
+			).
+			Suffix("ON CONFLICT(source_text, target_text, src_lang, tgt_lang) DO UPDATE SET project_id=COALESCE(excluded.project_id, tm_entries.project_id), unit_id=COALESCE(excluded.unit_id, tm_entries.unit_id), status=excluded.status, updated_at=excluded.updated_at RETURNING id")
+		sqlStr, args, _ := q.ToSql()
+		if err := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&e.ID); err != nil {
+			return err
+		}
+		if !r.fts {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tm_fts WHERE rowid = ?`, e.ID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO tm_fts(rowid, source_norm) VALUES (?, ?)`, e.ID, tmNorm(e.SourceText))
+		return err
+	})
+}
+
+func (r *TMRepo) DeleteByUnit(ctx context.Context, unitID int64, tgtLang string) error {
+	return WithTx(ctx, r.DB, func(tx *sql.Tx) error {
+		if r.fts {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM tm_fts WHERE rowid IN (SELECT id FROM tm_entries WHERE unit_id = ? AND tgt_lang = ?)`, unitID, tgtLang); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM tm_entries WHERE unit_id = ? AND tgt_lang = ?`, unitID, tgtLang)
+		return err
+	})
+}
+
+func (r *TMRepo) Delete(ctx context.Context, id int64) error {
+	return WithTx(ctx, r.DB, func(tx *sql.Tx) error {
+		if r.fts {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM tm_fts WHERE rowid = ?`, id); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM tm_entries WHERE id = ?`, id)
+		return err
+	})
+}
+
+func (r *TMRepo) Candidates(ctx context.Context, projectID int64, srcLang, tgtLang, text string, limit int) ([]*domain.TMEntry, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	norm := tmNorm(text)
+	cols := make([]string, 0, len(tmColumns))
+	for _, c := range tmColumns {
+		cols = append(cols, "e."+c)
+	}
+	b := r.SQ.Select(cols...).From("tm_entries e").Where(sq.Eq{"e.tgt_lang": tgtLang})
+	if srcLang != "" {
+		b = b.Where(sq.Eq{"e.src_lang": srcLang})
+	}
+	if projectID != 0 {
+		b = b.Where(sq.Eq{"e.project_id": projectID})
+	}
+	if match := ftsQuery(norm); r.fts && match != "" {
+		b = b.Join("tm_fts f ON f.rowid = e.id").
+			Where("tm_fts MATCH ?", match).
+			OrderBy("bm25(tm_fts)")
+	} else {
+		n := len([]rune(norm))
+		b = b.Where("length(e.source_norm) BETWEEN ? AND ?", n/2, n*2+3)
+		if w := longestWord(norm); len([]rune(w)) >= 3 {
+			b = b.Where(sq.Like{"e.source_norm": "%" + w + "%"})
+		}
+		b = b.OrderBy("e.updated_at DESC")
+	}
+	sqlStr, args, _ := b.Limit(uint64(limit)).ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.TMEntry
+	for rows.Next() {
+		e, err := scanTMEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *TMRepo) BySource(ctx context.Context, srcLang, tgtLang, text string) ([]*domain.TMEntry, error) {
+	b := r.SQ.Select(tmColumns...).From("tm_entries").Where(sq.Eq{"source_norm": tmNorm(text), "tgt_lang": tgtLang})
+	if srcLang != "" {
+		b = b.Where(sq.Eq{"src_lang": srcLang})
+	}
+	sqlStr, args, _ := b.OrderBy("updated_at DESC").ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.TMEntry
+	for rows.Next() {
+		e, err := scanTMEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// ftsQuery turns text into an OR query over its distinct character trigrams.
+func ftsQuery(norm string) string {
+	rs := []rune(norm)
+	if len(rs) < 3 {
+		return ""
+	}
+	seen := map[string]struct{}{}
+	parts := make([]string, 0, 64)
+	for i := 0; i+3 <= len(rs) && len(parts) < 64; i++ {
+		g := string(rs[i : i+3])
+		if _, ok := seen[g]; ok {
+			continue
+		}
+		seen[g] = struct{}{}
+		parts = append(parts, `"`+strings.ReplaceAll(g, `"`, `""`)+`"`)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+func longestWord(s string) string {
+	best := ""
+	for _, w := range strings.Fields(s) {
+		if len([]rune(w)) > len([]rune(best)) {
+			best = w
+		}
+	}
+	return best
+}
+
+func (r *TMRepo) CollectFromTranslations(ctx context.Context, statuses []string) ([]*domain.TMEntry, error) {
+	q := r.SQ.Select(
+		"u.source_text",
+		"t.text",
+		"COALESCE(NULLIF(f.locale, ''), p.source_lang, '')",
+		"t.locale",
+		"f.project_id",
+		"u.id",
+		"t.status",
+	).
+		From("translations t").
+		Join("units u ON u.id = t.unit_id").
+		Join("files f ON f.id = u.file_id").
+		Join("projects p ON p.id = f.project_id").
+		Where(sq.Eq{"t.status": statuses}).
+		Where("TRIM(COALESCE(t.text, '')) <> ''").
+		Where("TRIM(COALESCE(u.source_text, '')) <> ''")
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.TMEntry
+	for rows.Next() {
+		var e domain.TMEntry
+		var proj, unit int64
+		if err := rows.Scan(&e.SourceText, &e.TargetText, &e.SrcLang, &e.TgtLang, &proj, &unit, &e.Status); err != nil {
+			return nil, err
+		}
+		e.ProjectID = &proj
+		e.UnitID = &unit
+		e.Origin = "translation"
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
+
+func (r *TMRepo) List(ctx context.Context, f domain.TMFilter) ([]*domain.TMEntry, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	b := r.SQ.Select(tmColumns...).From("tm_entries")
+	if f.ProjectID != nil {
+		b = b.Where(sq.Eq{"project_id": *f.ProjectID})
+	}
+	if f.SrcLang != "" {
+		b = b.Where(sq.Eq{"src_lang": f.SrcLang})
+	}
+	if f.TgtLang != "" {
+		b = b.Where(sq.Eq{"tgt_lang": f.TgtLang})
+	}
+	if len(f.Statuses) > 0 {
+		b = b.Where(sq.Eq{"status": f.Statuses})
+	}
+	if f.Query != "" {
+		like := "%" + f.Query + "%"
+		b = b.Where(sq.Or{sq.Like{"source_text": like}, sq.Like{"target_text": like}})
+	}
+	sqlStr, args, _ := b.OrderBy("id DESC").Limit(uint64(limit)).Offset(uint64(max(f.Offset, 0))).ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.TMEntry
+	for rows.Next() {
+		e, err := scanTMEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *TMRepo) Count(ctx context.Context) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM tm_entries`).Scan(&n)
+	return n, err
+}
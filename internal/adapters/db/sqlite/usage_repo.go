@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"locail/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// usageColumns sum the usage of job_items rows into the fields of domain.Usage; t prefixes
+// the columns, e.g. "ji.".
+func usageColumns(t string) []string {
+	return []string{
+		"COUNT(*)",
+		"COALESCE(SUM(" + t + "input_tokens), 0)",
+		"COALESCE(SUM(" + t + "output_tokens), 0)",
+		"COALESCE(SUM(" + t + "cost), 0)",
+		"COALESCE(SUM(CASE WHEN " + t + "cost IS NULL THEN 1 ELSE 0 END), 0)",
+	}
+}
+
+type UsageRepo struct{ *Repo }
+
+func NewUsageRepo(db *sql.DB) *UsageRepo { return &UsageRepo{NewRepo(db)} }
+
+// usageGroups maps the group names of a usage query to their columns.
+var usageGroups = map[string][]string{
+	"month":    {"substr(ji.created_at, 1, 7)"},
+	"project":  {"j.project_id", "COALESCE(p.name, '')"},
+	"provider": {"ji.provider_id", "COALESCE(pr.name, '')"},
+	"model":    {"COALESCE(ji.model, '')"},
+}
+
+var usageGroupOrder = []string{"month", "project", "provider", "model"}
+
+func (r *UsageRepo) Report(ctx context.Context, f domain.UsageQuery) ([]*domain.UsageRow, error) {
+	groups := f.GroupBy
+	if len(groups) == 0 {
+		groups = usageGroupOrder
+	}
+	by := map[string]bool{}
+	for _, g := range groups {
+		if _, ok := usageGroups[g]; !ok {
+			return nil, fmt.Errorf("unknown usage group %q", g)
+		}
+		by[g] = true
+	}
+	var cols, groupCols []string
+	for _, g := range usageGroupOrder {
+		if by[g] {
+			cols = append(cols, usageGroups[g]...)
+			groupCols = append(groupCols, usageGroups[g]...)
+		}
+	}
+	cols = append(cols, usageColumns("ji.")...)
+	q := r.SQ.Select(cols...).
+		From("job_items ji").
+		Join("jobs j ON j.id = ji.job_id").
+		LeftJoin("projects p ON p.id = j.project_id").
+		LeftJoin("providers pr ON pr.id = ji.provider_id").
+		Where(sq.NotEq{"ji.provider_id": nil})
+	if f.FromMonth != "" {
+		q = q.Where(sq.GtOrEq{"substr(ji.created_at, 1, 7)": f.FromMonth})
+	}
+	if f.ToMonth != "" {
+		q = q.Where(sq.LtOrEq{"substr(ji.created_at, 1, 7)": f.ToMonth})
+	}
+	if f.ProjectID > 0 {
+		q = q.Where(sq.Eq{"j.project_id": f.ProjectID})
+	}
+	if f.ProviderID > 0 {
+		q = q.Where(sq.Eq{"ji.provider_id": f.ProviderID})
+	}
+	if len(groupCols) > 0 {
+		q = q.GroupBy(groupCols...).OrderBy(groupCols...)
+	}
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*domain.UsageRow
+	for rows.Next() {
+		var u domain.UsageRow
+		var project, provider sql.NullInt64
+		var dest []any
+		if by["month"] {
+			dest = append(dest, &u.Month)
+		}
+		if by["project"] {
+			dest = append(dest, &project, &u.ProjectName)
+		}
+		if by["provider"] {
+			dest = append(dest, &provider, &u.ProviderName)
+		}
+		if by["model"] {
+			dest = append(dest, &u.Model)
+		}
+		dest = append(dest, &u.Items, &u.InputTokens, &u.OutputTokens, &u.Cost, &u.Unpriced)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if project.Valid {
+			v := project.Int64
+			u.ProjectID = &v
+		}
+		if provider.Valid {
+			v := provider.Int64
+			u.ProviderID = &v
+		}
+		out = append(out, &u)
+	}
+	return out, rows.Err()
+}
+
+func (r *UsageRepo) Throughput(ctx context.Context, providerID int64, model string, jobs int) (int, float64, error) {
+	q := r.SQ.Select("COUNT(*)", "(julianday(MAX(updated_at)) - julianday(MIN(created_at))) * 86400").
+		From("job_items").
+		Where(sq.Eq{"provider_id": providerID, "model": model, "status": "done"}).
+		Where("input_tokens + output_tokens > 0").
+		GroupBy("job_id").
+		OrderBy("job_id DESC").
+		Limit(uint64(jobs))
+	sqlStr, args, _ := q.ToSql()
+	rows, err := r.DB.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+	items, seconds := 0, 0.0
+	for rows.Next() {
+		var n int
+		var secs sql.NullFloat64
+		if err := rows.Scan(&n, &secs); err != nil {
+			return 0, 0, err
+		}
+		items += n
+		seconds += secs.Float64
+	}
+	return items, seconds, rows.Err()
+}
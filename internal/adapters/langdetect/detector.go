@@ -0,0 +1,158 @@
+package langdetect
+
+import (
+	"context"
+	"errors"
+	"locail/internal/ports"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Detector is a fast offline language guesser based on Unicode scripts,
+// character trigram profiles and common function words. It is intended for
+// short UI strings and returns a low confidence when samples are ambiguous.
+type Detector struct{}
+
+func New() *Detector { return &Detector{} }
+
+var markupRE = regexp.MustCompile(`\{[^}]*\}|<[^>]*>|%[-+ #0-9.]*[sdifvqxXeEgG]|https?://\S+`)
+
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "you", "your", "in", "for", "with", "this", "are", "not", "on", "be", "it", "or", "from"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "sie", "ein", "eine", "mit", "für", "zu", "den", "auf", "ich", "du", "wird", "oder"},
+	"fr": {"le", "la", "les", "et", "est", "de", "des", "vous", "une", "un", "pour", "pas", "avec", "du", "dans", "votre", "sur", "ou"},
+	"es": {"el", "la", "los", "las", "y", "es", "de", "que", "en", "un", "una", "para", "no", "con", "por", "su", "tu", "del"},
+	"it": {"il", "la", "di", "che", "e", "è", "non", "un", "una", "per", "con", "del", "della", "sono", "gli", "le", "tuo", "questo"},
+	"pt": {"o", "a", "os", "as", "e", "de", "que", "não", "um", "uma", "para", "com", "do", "da", "em", "você", "seu", "sua"},
+	"nl": {"de", "het", "een", "en", "van", "is", "niet", "je", "met", "voor", "op", "dat", "te", "zijn", "uw", "ook", "of", "naar"},
+	"pl": {"i", "w", "nie", "na", "się", "to", "jest", "z", "że", "do", "jak", "ale", "czy", "od", "za", "po", "tak", "twój"},
+	"sv": {"och", "att", "det", "som", "en", "är", "på", "inte", "med", "för", "av", "till", "du", "den", "har", "ett", "eller", "din"},
+	"tr": {"ve", "bir", "bu", "için", "ile", "de", "da", "değil", "çok", "ne", "mi", "olarak", "gibi", "daha", "sen", "ben", "veya", "var"},
+	"cs": {"a", "je", "se", "na", "v", "že", "to", "s", "z", "do", "jsou", "pro", "ale", "jak", "není", "by", "nebo", "tak"},
+}
+
+// Detect returns the most likely ISO 639-1 code for the samples.
+func (d *Detector) Detect(ctx context.Context, samples []string) (ports.Detection, error) {
+	scripts := map[string]int{}
+	words := make([]string, 0, 64)
+	for _, s := range samples {
+		s = markupRE.ReplaceAllString(s, " ")
+		for _, r := range s {
+			if sc := scriptOf(r); sc != "" {
+				scripts[sc]++
+			}
+		}
+		for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) }) {
+			words = append(words, w)
+		}
+	}
+	total := 0
+	for _, n := range scripts {
+		total += n
+	}
+	if total == 0 {
+		return ports.Detection{}, errors.New("no letters to detect language from")
+	}
+	dominant, count := "", 0
+	for sc, n := range scripts {
+		if n > count {
+			dominant, count = sc, n
+		}
+	}
+	share := float64(count) / float64(total)
+	switch dominant {
+	case "latin":
+		lang, conf := scoreLatin(words)
+		return ports.Detection{Lang: lang, Confidence: conf * share}, nil
+	case "cyrillic":
+		lang := "ru"
+		if scripts["cyrillic-uk"] > 0 {
+			lang = "uk"
+		}
+		return ports.Detection{Lang: lang, Confidence: 0.7 * share}, nil
+	case "han":
+		if scripts["kana"] > 0 {
+			return ports.Detection{Lang: "ja", Confidence: share}, nil
+		}
+		return ports.Detection{Lang: "zh", Confidence: 0.9 * share}, nil
+	case "kana":
+		return ports.Detection{Lang: "ja", Confidence: share}, nil
+	default:
+		return ports.Detection{Lang: dominant, Confidence: share}, nil
+	}
+}
+
+// scriptOf maps a rune to a script bucket; single-language scripts map directly to a language code.
+func scriptOf(r rune) string {
+	switch {
+	case unicode.Is(unicode.Latin, r):
+		return "latin"
+	case strings.ContainsRune("іїєґІЇЄҐ", r):
+		return "cyrillic-uk"
+	case unicode.Is(unicode.Cyrillic, r):
+		return "cyrillic"
+	case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		return "kana"
+	case unicode.Is(unicode.Han, r):
+		return "han"
+	case unicode.Is(unicode.Hangul, r):
+		return "ko"
+	case unicode.Is(unicode.Greek, r):
+		return "el"
+	case unicode.Is(unicode.Arabic, r):
+		return "ar"
+	case unicode.Is(unicode.Hebrew, r):
+		return "he"
+	case unicode.Is(unicode.Thai, r):
+		return "th"
+	case unicode.Is(unicode.Devanagari, r):
+		return "hi"
+	default:
+		return ""
+	}
+}
+
+// scoreLatin combines character trigram similarity with function-word hits.
+func scoreLatin(words []string) (string, float64) {
+	if len(words) == 0 {
+		return "en", 0
+	}
+	scores := scoreNgrams(strings.Join(words, " "))
+	for lang, list := range stopwords {
+		set := make(map[string]struct{}, len(list))
+		for _, w := range list {
+			set[w] = struct{}{}
+		}
+		hits := 0
+		for _, w := range words {
+			if _, ok := set[w]; ok {
+				hits++
+			}
+		}
+		scores[lang] += 0.5 * float64(hits) / float64(len(words))
+	}
+	langs := make([]string, 0, len(scores))
+	for l := range scores {
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if scores[langs[i]] != scores[langs[j]] {
+			return scores[langs[i]] > scores[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	best := scores[langs[0]]
+	if best <= 0 {
+		return "en", 0
+	}
+	second := 0.0
+	if len(langs) > 1 {
+		second = scores[langs[1]]
+	}
+	// Confidence grows with the lead over the runner-up and with the amount of text.
+	margin := (best - second) / best
+	volume := min(1, float64(len(words))/8)
+	return langs[0], min(1, margin*2) * (0.4 + 0.6*volume)
+}
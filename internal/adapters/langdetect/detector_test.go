@@ -0,0 +1,39 @@
+package langdetect
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The settings of your game are not saved", "en"},
+		{"Die Einstellungen für das Spiel werden nicht gespeichert", "de"},
+		{"Les paramètres de votre partie ne sont pas enregistrés", "fr"},
+		{"Los ajustes de la partida no se guardan para el jugador", "es"},
+		{"Настройки игры не сохранены", "ru"},
+		{"ゲームの設定が保存されていません", "ja"},
+		{"设置未保存", "zh"},
+		{"설정이 저장되지 않았습니다", "ko"},
+	}
+	d := New()
+	for _, tt := range tests {
+		det, err := d.Detect(context.Background(), []string{tt.text})
+		if err != nil || det.Lang != tt.want {
+			t.Errorf("Detect(%q) = %+v, %v, want %s", tt.text, det, err, tt.want)
+		}
+	}
+}
+
+func TestDetectIgnoresMarkup(t *testing.T) {
+	if _, err := New().Detect(context.Background(), []string{"{0} <b>%s</b> https://example.com 123"}); err == nil {
+		t.Fatal("detected a language in markup only")
+	}
+	det, err := New().Detect(context.Background(), []string{"<clr:255,0,0>{name}</clr> und die Einstellungen sind nicht gespeichert"})
+	if err != nil || det.Lang != "de" {
+		t.Fatalf("got %+v, %v", det, err)
+	}
+}
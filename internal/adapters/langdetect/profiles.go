@@ -0,0 +1,99 @@
+package langdetect
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// seedText holds short representative UI/game text per Latin-script language.
+// Character trigram profiles are built from it at startup.
+var seedText = map[string]string{
+	"en": `Press any key to continue. Are you sure you want to quit the game? Your progress will be saved automatically.
+Select a server from the list and join the match. The player has left the game. Not enough gold to buy this item.
+Settings have been changed and will take effect after restarting. Invite your friends to play together with you.`,
+	"de": `Drücke eine beliebige Taste, um fortzufahren. Bist du sicher, dass du das Spiel beenden möchtest? Dein Fortschritt wird automatisch gespeichert.
+Wähle einen Server aus der Liste und tritt dem Spiel bei. Der Spieler hat das Spiel verlassen. Nicht genug Gold, um diesen Gegenstand zu kaufen.
+Die Einstellungen wurden geändert und werden nach einem Neustart wirksam. Lade deine Freunde ein, um gemeinsam zu spielen.`,
+	"fr": `Appuyez sur une touche pour continuer. Êtes-vous sûr de vouloir quitter le jeu ? Votre progression sera sauvegardée automatiquement.
+Sélectionnez un serveur dans la liste et rejoignez la partie. Le joueur a quitté la partie. Pas assez d'or pour acheter cet objet.
+Les paramètres ont été modifiés et prendront effet après le redémarrage. Invitez vos amis à jouer avec vous.`,
+	"es": `Pulsa cualquier tecla para continuar. ¿Seguro que quieres salir del juego? Tu progreso se guardará automáticamente.
+Selecciona un servidor de la lista y únete a la partida. El jugador ha abandonado la partida. No tienes suficiente oro para comprar este objeto.
+La configuración ha cambiado y se aplicará después de reiniciar. Invita a tus amigos a jugar contigo.`,
+	"it": `Premi un tasto qualsiasi per continuare. Sei sicuro di voler uscire dal gioco? I tuoi progressi verranno salvati automaticamente.
+Seleziona un server dalla lista e unisciti alla partita. Il giocatore ha lasciato la partita. Non hai abbastanza oro per acquistare questo oggetto.
+Le impostazioni sono state modificate e avranno effetto dopo il riavvio. Invita i tuoi amici a giocare insieme a te.`,
+	"pt": `Pressione qualquer tecla para continuar. Tem certeza de que deseja sair do jogo? Seu progresso será salvo automaticamente.
+Selecione um servidor da lista e entre na partida. O jogador saiu da partida. Você não tem ouro suficiente para comprar este item.
+As configurações foram alteradas e terão efeito após reiniciar. Convide seus amigos para jogar com você.`,
+	"nl": `Druk op een toets om door te gaan. Weet je zeker dat je het spel wilt afsluiten? Je voortgang wordt automatisch opgeslagen.
+Kies een server uit de lijst en doe mee aan de wedstrijd. De speler heeft het spel verlaten. Niet genoeg goud om dit voorwerp te kopen.
+De instellingen zijn gewijzigd en worden van kracht na een herstart. Nodig je vrienden uit om samen te spelen.`,
+	"pl": `Naciśnij dowolny klawisz, aby kontynuować. Czy na pewno chcesz wyjść z gry? Twój postęp zostanie zapisany automatycznie.
+Wybierz serwer z listy i dołącz do meczu. Gracz opuścił grę. Za mało złota, aby kupić ten przedmiot.
+Ustawienia zostały zmienione i zaczną obowiązywać po ponownym uruchomieniu. Zaproś znajomych do wspólnej gry.`,
+	"sv": `Tryck på valfri tangent för att fortsätta. Är du säker på att du vill avsluta spelet? Dina framsteg sparas automatiskt.
+Välj en server från listan och gå med i matchen. Spelaren har lämnat spelet. Inte tillräckligt med guld för att köpa detta föremål.
+Inställningarna har ändrats och träder i kraft efter omstart. Bjud in dina vänner att spela tillsammans med dig.`,
+	"tr": `Devam etmek için herhangi bir tuşa basın. Oyundan çıkmak istediğinizden emin misiniz? İlerlemeniz otomatik olarak kaydedilecek.
+Listeden bir sunucu seçin ve maça katılın. Oyuncu oyundan ayrıldı. Bu eşyayı satın almak için yeterli altın yok.
+Ayarlar değiştirildi ve yeniden başlatıldıktan sonra geçerli olacak. Birlikte oynamak için arkadaşlarınızı davet edin.`,
+	"cs": `Stiskněte libovolnou klávesu pro pokračování. Opravdu chcete ukončit hru? Váš postup bude automaticky uložen.
+Vyberte server ze seznamu a připojte se k zápasu. Hráč opustil hru. Nemáte dost zlata na koupi tohoto předmětu.
+Nastavení bylo změněno a projeví se po restartu. Pozvěte své přátele, aby hráli s vámi.`,
+}
+
+type profile map[string]float64
+
+var profiles = buildProfiles()
+
+func buildProfiles() map[string]profile {
+	out := make(map[string]profile, len(seedText))
+	for lang, text := range seedText {
+		out[lang] = normalize(trigrams(text))
+	}
+	return out
+}
+
+// trigrams counts character trigrams of lower-cased words padded with spaces.
+func trigrams(s string) map[string]float64 {
+	counts := map[string]float64{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		rs := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(rs); i++ {
+			counts[string(rs[i:i+3])]++
+		}
+	}
+	return counts
+}
+
+func normalize(m map[string]float64) profile {
+	var norm float64
+	for _, v := range m {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	p := make(profile, len(m))
+	if norm == 0 {
+		return p
+	}
+	for k, v := range m {
+		p[k] = v / norm
+	}
+	return p
+}
+
+// scoreNgrams returns the cosine similarity of the text against each language profile.
+func scoreNgrams(text string) map[string]float64 {
+	sample := normalize(trigrams(text))
+	out := make(map[string]float64, len(profiles))
+	for lang, prof := range profiles {
+		var dot float64
+		for g, v := range sample {
+			dot += v * prof[g]
+		}
+		out[lang] = dot
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+// Package anthropic talks to the Anthropic Messages API.
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+	"strings"
+)
+
+const (
+	defaultBaseURL = "https://api.anthropic.com"
+	apiVersion     = "2023-06-01"
+	// defaultMaxTokens is sent when the provider options do not set max_tokens, which the
+	// Messages API requires.
+	defaultMaxTokens = 4096
+	// contextTokens is the context window of current Claude models; the API does not
+	// report it.
+	contextTokens = 200000
+)
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "anthropic",
+		Label:          "Anthropic",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL},
+			{Name: "api_key", Label: "API key", Kind: "secret", Required: true},
+			{Name: "model", Label: "Model", Kind: "text", Help: "Default model; jobs may override it"},
+		},
+		// the Messages API takes no seed
+		Options: append([]registry.Field{
+			{Name: "structured_output", Label: "Structured output", Kind: "select", Choices: []string{"tool", "prefill"}, Help: "tool forces a tool call; prefill starts the answer with {"},
+		}, registry.SamplingOptions[:3]...),
+		Capabilities: registry.Capabilities{Prompts: true, JSONSchema: true, Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Options configure the anthropic provider type.
+type Options struct {
+	// StructuredOutput is "tool" (default), forcing a tool call whose input is the answer, or
+	// "prefill", starting the assistant turn with "{" so the model continues a JSON object.
+	StructuredOutput string `json:"structured_output"`
+}
+
+type Client struct {
+	httpclient.Base
+	opts Options
+}
+
+// New returns a client; invalid options JSON is ignored.
+func New(cfg registry.Config) *Client {
+	c := &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+	if strings.TrimSpace(cfg.Options) != "" {
+		_ = json.Unmarshal([]byte(cfg.Options), &c.opts)
+	}
+	return c
+}
+
+func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	return httpclient.TranslateJSON(ctx, c.chat, p)
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	return httpclient.TranslateBatchJSON(ctx, c.chat, segs, p)
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// chat sends a Messages API request whose answer must be a JSON object with the given
+// string fields and returns that object as text.
+func (c *Client) chat(ctx context.Context, p ports.TranslateParams, toolName string, keys []string) (string, ports.Usage, error) {
+	model := c.ModelFor(p)
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type tool struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		InputSchema map[string]any `json:"input_schema"`
+	}
+	type toolChoice struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	type requestBody struct {
+		Model       string      `json:"model"`
+		System      string      `json:"system,omitempty"`
+		Messages    []message   `json:"messages"`
+		MaxTokens   int         `json:"max_tokens"`
+		Temperature float64     `json:"temperature"`
+		TopP        *float64    `json:"top_p,omitempty"`
+		Tools       []tool      `json:"tools,omitempty"`
+		ToolChoice  *toolChoice `json:"tool_choice,omitempty"`
+	}
+	body := requestBody{
+		Model:       model,
+		System:      p.SystemPrompt,
+		Messages:    []message{{Role: "user", Content: p.UserPrompt}},
+		MaxTokens:   c.Opts.MaxTokens,
+		Temperature: c.Temperature(p),
+		TopP:        c.Opts.TopP,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = defaultMaxTokens
+	}
+	prefill := c.opts.StructuredOutput == "prefill"
+	if prefill {
+		body.Messages = append(body.Messages, message{Role: "assistant", Content: "{"})
+	} else {
+		props := make(map[string]any, len(keys))
+		for _, k := range keys {
+			props[k] = map[string]string{"type": "string"}
+		}
+		body.Tools = []tool{{
+			Name:        toolName,
+			Description: "Submit the answer.",
+			InputSchema: map[string]any{"type": "object", "properties": props, "required": keys},
+		}}
+		body.ToolChoice = &toolChoice{Type: "tool", Name: toolName}
+	}
+	var resp struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	rr, err := c.HTTP.R().SetContext(ctx).
+		SetHeaders(c.headers()).
+		SetHeader("Content-Type", "application/json").
+		SetBody(c.Body(body, nil)).SetResult(&resp).
+		Post(c.url("/messages"))
+	if err != nil {
+		return "", ports.Usage{}, err
+	}
+	if rr.IsError() {
+		return "", ports.Usage{}, httpclient.HTTPError("anthropic", "translate", rr)
+	}
+	usage := ports.Usage{InputTokens: resp.Usage.InputTokens, OutputTokens: resp.Usage.OutputTokens}
+	var text strings.Builder
+	for _, b := range resp.Content {
+		switch {
+		case b.Type == "tool_use" && b.Name == toolName:
+			return string(b.Input), usage, nil
+		case b.Type == "text":
+			text.WriteString(b.Text)
+		}
+	}
+	out := strings.TrimSpace(text.String())
+	if out == "" {
+		return "", usage, fmt.Errorf("no content returned (stop_reason %s): %w", resp.StopReason, ports.ErrMalformedResponse)
+	}
+	if prefill {
+		out = "{" + out
+	}
+	return out, usage, nil
+}
+
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	var out []ports.ModelInfo
+	after := ""
+	for {
+		var resp struct {
+			Data []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"display_name"`
+			} `json:"data"`
+			HasMore bool   `json:"has_more"`
+			LastID  string `json:"last_id"`
+		}
+		r := c.HTTP.R().SetContext(ctx).SetHeaders(c.headers()).SetQueryParam("limit", "1000").SetResult(&resp)
+		if after != "" {
+			r.SetQueryParam("after_id", after)
+		}
+		rr, err := r.Get(c.url("/models"))
+		if err != nil {
+			return nil, err
+		}
+		if rr.IsError() {
+			return nil, httpclient.HTTPError("anthropic", "list models", rr)
+		}
+		for _, d := range resp.Data {
+			label := d.DisplayName
+			if label == "" {
+				label = d.ID
+			}
+			out = append(out, ports.ModelInfo{Name: d.ID, Description: label, ContextTokens: contextTokens})
+		}
+		if !resp.HasMore || resp.LastID == "" || resp.LastID == after {
+			return out, nil
+		}
+		after = resp.LastID
+	}
+}
+
+// url joins the base URL and tail below /v1.
+func (c *Client) url(tail string) string {
+	base := c.URL(defaultBaseURL, "")
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base + tail
+}
+
+func (c *Client) headers() map[string]string {
+	return map[string]string{
+		"x-api-key":         c.APIKey,
+		"anthropic-version": apiVersion,
+	}
+}
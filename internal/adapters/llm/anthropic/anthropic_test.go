@@ -0,0 +1,197 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+// request is the part of a Messages API request the tests look at.
+type request struct {
+	System   string `json:"system"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+	MaxTokens int `json:"max_tokens"`
+	Tools     []struct {
+		Name        string         `json:"name"`
+		InputSchema map[string]any `json:"input_schema"`
+	} `json:"tools"`
+	ToolChoice *struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"tool_choice"`
+}
+
+// serve answers /v1/messages with answer and records the request.
+func serve(t *testing.T, answer string, got *request) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") != apiVersion {
+			t.Errorf("request %s %s with headers %v", r.Method, r.URL.Path, r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(answer))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		answer  string
+		want    string
+		prefill bool
+	}{
+		{
+			name:   "tool use",
+			answer: `{"content":[{"type":"text","text":"Sure"},{"type":"tool_use","name":"translation","input":{"translation":"Hallo"}}],"usage":{"input_tokens":12,"output_tokens":5}}`,
+			want:   "Hallo",
+		},
+		{
+			name:    "prefill",
+			options: `{"structured_output":"prefill","max_tokens":300}`,
+			answer:  `{"content":[{"type":"text","text":"\"translation\":\"Servus\"}"}],"usage":{"input_tokens":12,"output_tokens":5}}`,
+			want:    "Servus",
+			prefill: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got request
+			srv := serve(t, tt.answer, &got)
+			c := New(registry.Config{APIKey: "key", BaseURL: srv.URL, Model: "claude-a", Options: tt.options})
+			res, err := c.Translate(context.Background(), ports.Segment{Key: "k", Text: "Hi"}, ports.TranslateParams{SystemPrompt: "sys", UserPrompt: "Hi"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Translation != tt.want || res.Usage != (ports.Usage{InputTokens: 12, OutputTokens: 5}) {
+				t.Fatalf("got %q with usage %+v", res.Translation, res.Usage)
+			}
+			if got.System != "sys" {
+				t.Errorf("system %q", got.System)
+			}
+			if tt.prefill {
+				if len(got.Messages) != 2 || got.Messages[1].Role != "assistant" || got.Messages[1].Content != "{" {
+					t.Errorf("messages %+v, want an assistant turn starting with {", got.Messages)
+				}
+				if got.Tools != nil || got.ToolChoice != nil || got.MaxTokens != 300 {
+					t.Errorf("prefill request sent tools %+v, max_tokens %d", got.Tools, got.MaxTokens)
+				}
+				return
+			}
+			if got.ToolChoice == nil || got.ToolChoice.Type != "tool" || got.ToolChoice.Name != "translation" || len(got.Tools) != 1 {
+				t.Errorf("tool choice %+v, tools %+v", got.ToolChoice, got.Tools)
+			}
+			if got.MaxTokens != defaultMaxTokens {
+				t.Errorf("max_tokens %d, want the default", got.MaxTokens)
+			}
+		})
+	}
+}
+
+func TestTranslateBatchToolUse(t *testing.T) {
+	var got request
+	srv := serve(t, `{"content":[{"type":"tool_use","name":"translations","input":{"a":"A!","b":"B!"}}],"stop_reason":"tool_use","usage":{"input_tokens":50,"output_tokens":9}}`, &got)
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL + "/v1/", Model: "claude-a"})
+	res, err := c.TranslateBatch(context.Background(), []ports.Segment{{Key: "a"}, {Key: "b"}}, ports.TranslateParams{UserPrompt: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Translations["a"] != "A!" || res.Translations["b"] != "B!" || res.Usage.InputTokens != 50 {
+		t.Fatalf("got %+v", res)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Name != "translations" {
+		t.Fatalf("tools %+v", got.Tools)
+	}
+	if req, _ := got.Tools[0].InputSchema["required"].([]any); len(req) != 2 {
+		t.Fatalf("schema %+v, want both keys required", got.Tools[0].InputSchema)
+	}
+}
+
+func TestTranslateEmptyAnswer(t *testing.T) {
+	var got request
+	srv := serve(t, `{"content":[],"stop_reason":"max_tokens"}`, &got)
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL})
+	if _, err := c.Translate(context.Background(), ports.Segment{Key: "k"}, ports.TranslateParams{}); !errors.Is(err, ports.ErrMalformedResponse) {
+		t.Fatalf("got %v, want ErrMalformedResponse", err)
+	}
+}
+
+func TestListModelsPages(t *testing.T) {
+	var afters []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after := r.URL.Query().Get("after_id")
+		afters = append(afters, after)
+		w.Header().Set("Content-Type", "application/json")
+		switch after {
+		case "":
+			w.Write([]byte(`{"data":[{"id":"claude-a","display_name":"Claude A"}],"has_more":true,"last_id":"claude-a"}`))
+		case "claude-a":
+			w.Write([]byte(`{"data":[{"id":"claude-b"}],"has_more":false,"last_id":"claude-b"}`))
+		default:
+			t.Errorf("unexpected after_id %q", after)
+		}
+	}))
+	defer srv.Close()
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL})
+	models, err := c.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ports.ModelInfo{
+		{Name: "claude-a", Description: "Claude A", ContextTokens: contextTokens},
+		{Name: "claude-b", Description: "claude-b", ContextTokens: contextTokens},
+	}
+	if len(models) != len(want) || models[0] != want[0] || models[1] != want[1] {
+		t.Fatalf("got %+v", models)
+	}
+	if len(afters) != 2 {
+		t.Fatalf("%d pages requested, want 2", len(afters))
+	}
+}
+
+func TestHTTPErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{529, true},
+		{http.StatusUnauthorized, false},
+		{http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error"}}`))
+			}))
+			defer srv.Close()
+			c := New(registry.Config{APIKey: "key", BaseURL: srv.URL})
+			_, err := c.Translate(context.Background(), ports.Segment{Key: "k"}, ports.TranslateParams{})
+			var pe *ports.ProviderError
+			if !errors.As(err, &pe) {
+				t.Fatalf("got %v, want a *ports.ProviderError", err)
+			}
+			if pe.StatusCode != tt.status || pe.Retryable() != tt.retryable || pe.RetryAfter != 7*time.Second {
+				t.Fatalf("status %d retryable %v after %v", pe.StatusCode, pe.Retryable(), pe.RetryAfter)
+			}
+		})
+	}
+}
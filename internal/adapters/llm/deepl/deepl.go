@@ -0,0 +1,194 @@
+// Package deepl talks to the DeepL API. It translates segment text directly and ignores
+// prompts.
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+	"regexp"
+	"strings"
+)
+
+const defaultBaseURL = "https://api.deepl.com"
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "deepl",
+		Label:          "DeepL",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL, Help: "Free keys (ending in :fx) default to api-free.deepl.com"},
+			{Name: "api_key", Label: "API key", Kind: "secret", Required: true},
+			{Name: "model", Label: "Model type", Kind: "text", Placeholder: "default"},
+		},
+		Options: []registry.Field{
+			{Name: "formality", Label: "Formality", Kind: "select", Choices: []string{"default", "more", "less", "prefer_more", "prefer_less"}},
+		},
+		Capabilities: registry.Capabilities{Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Options configure the deepl provider type.
+type Options struct {
+	// Formality is passed through: default, more, less, prefer_more or prefer_less.
+	Formality string `json:"formality"`
+}
+
+// maxTexts is the number of texts DeepL accepts per request.
+const maxTexts = 50
+
+// Text goes out as XML with tokens in ignored <x> elements.
+var wrapperRE = regexp.MustCompile(`<x[^>]*>\s*(__(?:PH|TAG|PT)_\d+__)\s*</x>`)
+
+func protect(text string) string {
+	return httpclient.ProtectTokens(text, `<x>`, `</x>`)
+}
+
+type Client struct {
+	httpclient.Base
+	opts Options
+}
+
+// New returns a client; invalid options JSON is ignored.
+func New(cfg registry.Config) *Client {
+	c := &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+	if strings.TrimSpace(cfg.Options) != "" {
+		_ = json.Unmarshal([]byte(cfg.Options), &c.opts)
+	}
+	return c
+}
+
+// Promptless reports that DeepL ignores prompts.
+func (c *Client) Promptless() bool { return true }
+
+func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	out, err := c.translate(ctx, []string{protect(seg.Text)}, p)
+	if err != nil {
+		return ports.TranslateResult{}, err
+	}
+	return ports.TranslateResult{Translation: httpclient.RestoreTokens(out[0], wrapperRE), Raw: out[0]}, nil
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	res := ports.BatchResult{Translations: make(map[string]string, len(segs))}
+	for start := 0; start < len(segs); start += maxTexts {
+		chunk := segs[start:min(start+maxTexts, len(segs))]
+		texts := make([]string, len(chunk))
+		for i, seg := range chunk {
+			texts[i] = protect(seg.Text)
+		}
+		out, err := c.translate(ctx, texts, p)
+		if err != nil {
+			return ports.BatchResult{}, err
+		}
+		for i, seg := range chunk {
+			res.Translations[seg.Key] = httpclient.RestoreTokens(out[i], wrapperRE)
+		}
+	}
+	return res, nil
+}
+
+func (c *Client) translate(ctx context.Context, texts []string, p ports.TranslateParams) ([]string, error) {
+	body := map[string]any{
+		"text":         texts,
+		"target_lang":  targetLang(p.TargetLang),
+		"tag_handling": "xml",
+		"ignore_tags":  []string{"x"},
+	}
+	if src := sourceLang(p.SourceLang); src != "" {
+		body["source_lang"] = src
+	}
+	if model := c.ModelFor(p); model != "" && model != "default" {
+		body["model_type"] = model
+	}
+	if c.opts.Formality != "" {
+		body["formality"] = c.opts.Formality
+	}
+	var resp struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	rr, err := c.HTTP.R().SetContext(ctx).
+		SetHeader("Authorization", "DeepL-Auth-Key "+c.APIKey).
+		SetHeader("Content-Type", "application/json").
+		SetBody(c.Body(body, nil)).SetResult(&resp).
+		Post(c.url("/translate"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("deepl", "translate", rr)
+	}
+	if len(resp.Translations) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d texts: %w", len(resp.Translations), len(texts), ports.ErrMalformedResponse)
+	}
+	out := make([]string, len(texts))
+	for i, t := range resp.Translations {
+		out[i] = t.Text
+	}
+	return out, nil
+}
+
+// ListModels lists DeepL's model types; listing them checks the key against /usage.
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	rr, err := c.HTTP.R().SetContext(ctx).SetHeader("Authorization", "DeepL-Auth-Key "+c.APIKey).Get(c.url("/usage"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("deepl", "list models", rr)
+	}
+	return []ports.ModelInfo{
+		{Name: "default", Description: "DeepL (account default)"},
+		{Name: "latency_optimized", Description: "DeepL classic"},
+		{Name: "quality_optimized", Description: "DeepL next-gen"},
+		{Name: "prefer_quality_optimized", Description: "DeepL next-gen where available"},
+	}, nil
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// url joins the base URL and tail below /v2. Free keys (ending in ":fx") default to the
+// free API host.
+func (c *Client) url(tail string) string {
+	def := defaultBaseURL
+	if strings.HasSuffix(c.APIKey, ":fx") {
+		def = "https://api-free.deepl.com"
+	}
+	base := c.URL(def, "")
+	if !strings.HasSuffix(base, "/v2") {
+		base += "/v2"
+	}
+	return base + tail
+}
+
+// sourceLang maps a locale to a DeepL source language, which has no regional variants.
+func sourceLang(locale string) string {
+	l := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	base, _, _ := strings.Cut(l, "-")
+	return base
+}
+
+// targetLang maps a locale to a DeepL target language, keeping the variants DeepL knows.
+func targetLang(locale string) string {
+	l := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	switch l {
+	case "EN-GB", "EN-US", "PT-BR", "PT-PT", "ZH-HANS", "ZH-HANT", "ES-419":
+		return l
+	case "EN":
+		return "EN-US"
+	case "PT":
+		return "PT-PT"
+	case "ZH-TW", "ZH-HK":
+		return "ZH-HANT"
+	case "ZH-CN":
+		return "ZH-HANS"
+	}
+	base, _, _ := strings.Cut(l, "-")
+	return base
+}
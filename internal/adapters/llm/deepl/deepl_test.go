@@ -0,0 +1,144 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+// fakeEngine translates "Hi" to "Hallo" and pads the ignored elements like real engines may.
+func fakeEngine(s string) string {
+	s = strings.ReplaceAll(s, "Hi", "Hallo")
+	return strings.ReplaceAll(s, "<x>", "<x> ")
+}
+
+type request struct {
+	Text        []string `json:"text"`
+	SourceLang  string   `json:"source_lang"`
+	TargetLang  string   `json:"target_lang"`
+	TagHandling string   `json:"tag_handling"`
+	IgnoreTags  []string `json:"ignore_tags"`
+	ModelType   string   `json:"model_type"`
+	Formality   string   `json:"formality"`
+}
+
+func serve(t *testing.T, got *[]request) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/translate" || r.Header.Get("Authorization") != "DeepL-Auth-Key key" {
+			t.Errorf("request to %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		*got = append(*got, req)
+		out := make([]map[string]string, len(req.Text))
+		for i, s := range req.Text {
+			out[i] = map[string]string{"text": fakeEngine(s)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"translations": out})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTranslateProtectsTokens(t *testing.T) {
+	var got []request
+	srv := serve(t, &got)
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL, Model: "quality_optimized", Options: `{"formality":"less"}`})
+	res, err := c.Translate(context.Background(), ports.Segment{Key: "k", Text: "Hi __PH_0__ & __TAG_0__you__TAG_1__ <3"}, ports.TranslateParams{SourceLang: "en-GB", TargetLang: "zh_TW"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hallo __PH_0__ & __TAG_0__you__TAG_1__ <3"; res.Translation != want {
+		t.Fatalf("got %q, want %q", res.Translation, want)
+	}
+	if len(got) != 1 {
+		t.Fatalf("%d requests", len(got))
+	}
+	req := got[0]
+	if want := "Hi <x>__PH_0__</x> &amp; <x>__TAG_0__</x>you<x>__TAG_1__</x> &lt;3"; req.Text[0] != want {
+		t.Errorf("sent %q, want %q", req.Text[0], want)
+	}
+	if req.SourceLang != "EN" || req.TargetLang != "ZH-HANT" || req.TagHandling != "xml" || len(req.IgnoreTags) != 1 || req.IgnoreTags[0] != "x" {
+		t.Errorf("sent %+v", req)
+	}
+	if req.ModelType != "quality_optimized" || req.Formality != "less" {
+		t.Errorf("model type %q, formality %q", req.ModelType, req.Formality)
+	}
+}
+
+func TestTranslateBatchSplits(t *testing.T) {
+	var got []request
+	srv := serve(t, &got)
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL + "/v2"})
+	segs := make([]ports.Segment, maxTexts*2+1)
+	for i := range segs {
+		segs[i] = ports.Segment{Key: fmt.Sprintf("k%d", i), Text: fmt.Sprintf("Hi %d", i)}
+	}
+	res, err := c.TranslateBatch(context.Background(), segs, ports.TranslateParams{TargetLang: "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || len(got[0].Text) != maxTexts || len(got[2].Text) != 1 {
+		t.Fatalf("%d requests", len(got))
+	}
+	if len(res.Translations) != len(segs) || res.Translations["k100"] != "Hallo 100" {
+		t.Fatalf("got %d translations, k100 = %q", len(res.Translations), res.Translations["k100"])
+	}
+	if got[0].SourceLang != "" || got[0].ModelType != "" {
+		t.Errorf("sent source %q, model type %q", got[0].SourceLang, got[0].ModelType)
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		key, base, want string
+	}{
+		{"key", "", "https://api.deepl.com/v2/usage"},
+		{"key:fx", "", "https://api-free.deepl.com/v2/usage"},
+		{"key:fx", "https://proxy.local/", "https://proxy.local/v2/usage"},
+		{"key", "https://proxy.local/v2", "https://proxy.local/v2/usage"},
+	}
+	for _, tt := range tests {
+		c := New(registry.Config{APIKey: tt.key, BaseURL: tt.base})
+		if got := c.url("/usage"); got != tt.want {
+			t.Errorf("url(%q, %q) = %q, want %q", tt.key, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestTargetLang(t *testing.T) {
+	tests := map[string]string{
+		"de":      "DE",
+		"de_AT":   "DE",
+		"en":      "EN-US",
+		"en-gb":   "EN-GB",
+		"pt":      "PT-PT",
+		"pt_BR":   "PT-BR",
+		"zh-CN":   "ZH-HANS",
+		"zh-Hant": "ZH-HANT",
+		"es-419":  "ES-419",
+	}
+	for in, want := range tests {
+		if got := targetLang(in); got != want {
+			t.Errorf("targetLang(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPromptless(t *testing.T) {
+	var p ports.Provider = New(registry.Config{})
+	if pl, ok := p.(ports.PromptlessProvider); !ok || !pl.Promptless() {
+		t.Fatal("deepl does not report itself promptless")
+	}
+}
@@ -1,12 +1,26 @@
 package factory
 
 import (
-	httpprov "locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
 	"locail/internal/domain"
 	"locail/internal/ports"
+
+	// Provider packages register their types with the registry.
+	_ "locail/internal/adapters/llm/anthropic"
+	_ "locail/internal/adapters/llm/deepl"
+	_ "locail/internal/adapters/llm/gemini"
+	_ "locail/internal/adapters/llm/libretranslate"
+	_ "locail/internal/adapters/llm/ollama"
+	_ "locail/internal/adapters/llm/openai"
+	_ "locail/internal/adapters/llm/openrouter"
 )
 
-// FromProvider returns an HTTP-backed provider for the given record.
+// FromProvider returns the adapter for the given record; false if its type is not registered.
 func FromProvider(p *domain.Provider) (ports.Provider, bool) {
-	return httpprov.New(p.Type, p.APIKey, p.BaseURL, p.Model), true
+	return registry.Build(p.Type, registry.Config{
+		APIKey:  p.APIKey,
+		BaseURL: p.BaseURL,
+		Model:   p.Model,
+		Options: p.OptionsRaw,
+	})
 }
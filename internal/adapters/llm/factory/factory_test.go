@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+func TestEveryTypeRegisters(t *testing.T) {
+	types := []string{"anthropic", "deepl", "gemini", "libretranslate", "ollama", "openai", "openrouter"}
+	if n := len(registry.Descriptors()); n != len(types) {
+		t.Fatalf("%d registered types, want %d", n, len(types))
+	}
+	for _, typ := range types {
+		t.Run(typ, func(t *testing.T) {
+			d, ok := registry.Lookup(typ)
+			if !ok {
+				t.Fatal("no descriptor")
+			}
+			if d.Label == "" || d.DefaultBaseURL == "" {
+				t.Errorf("descriptor %+v", d)
+			}
+			seen := map[string]bool{}
+			for _, f := range d.Options {
+				if seen[f.Name] {
+					t.Errorf("option %s listed twice", f.Name)
+				}
+				seen[f.Name] = true
+			}
+			for _, f := range d.Fields {
+				if f.Name != "base_url" && f.Name != "api_key" && f.Name != "model" {
+					t.Errorf("field %s is not a provider column", f.Name)
+				}
+			}
+			p, ok := FromProvider(&domain.Provider{Type: typ, APIKey: "k", Model: "m", OptionsRaw: `{"timeout_seconds":5}`})
+			if !ok || p == nil {
+				t.Fatal("FromProvider built nothing")
+			}
+			pl, isPromptless := p.(ports.PromptlessProvider)
+			if promptless := isPromptless && pl.Promptless(); promptless == d.Capabilities.Prompts {
+				t.Errorf("promptless %v with capability prompts %v", promptless, d.Capabilities.Prompts)
+			}
+		})
+	}
+	if _, ok := FromProvider(&domain.Provider{Type: "nope"}); ok {
+		t.Fatal("built an unknown type")
+	}
+}
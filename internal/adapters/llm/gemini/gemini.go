@@ -0,0 +1,197 @@
+// Package gemini talks to the Google Gemini API.
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const defaultBaseURL = "https://generativelanguage.googleapis.com"
+
+// blockReasons are finish reasons meaning the answer was withheld rather than cut off.
+var blockReasons = []string{"SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY"}
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "gemini",
+		Label:          "Google Gemini",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL},
+			{Name: "api_key", Label: "API key", Kind: "secret", Required: true},
+			{Name: "model", Label: "Model", Kind: "text", Placeholder: "gemini-2.0-flash", Help: "Default model; jobs may override it"},
+		},
+		Options:      registry.SamplingOptions,
+		Capabilities: registry.Capabilities{Prompts: true, JSONSchema: true, Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+type Client struct {
+	httpclient.Base
+}
+
+func New(cfg registry.Config) *Client {
+	return &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+}
+
+func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	return httpclient.TranslateJSON(ctx, c.generate, p)
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	return httpclient.TranslateBatchJSON(ctx, c.generate, segs, p)
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// generate calls generateContent in JSON mode with a response schema of the given string
+// fields and returns the answer text.
+func (c *Client) generate(ctx context.Context, p ports.TranslateParams, _ string, keys []string) (string, ports.Usage, error) {
+	model := c.ModelFor(p)
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role,omitempty"`
+		Parts []part `json:"parts"`
+	}
+	type schema struct {
+		Type       string            `json:"type"`
+		Properties map[string]schema `json:"properties,omitempty"`
+		Required   []string          `json:"required,omitempty"`
+	}
+	type generationConfig struct {
+		Temperature      float64  `json:"temperature"`
+		TopP             *float64 `json:"topP,omitempty"`
+		MaxOutputTokens  int      `json:"maxOutputTokens,omitempty"`
+		Seed             *int64   `json:"seed,omitempty"`
+		ResponseMimeType string   `json:"responseMimeType"`
+		ResponseSchema   schema   `json:"responseSchema"`
+	}
+	type requestBody struct {
+		SystemInstruction *content         `json:"systemInstruction,omitempty"`
+		Contents          []content        `json:"contents"`
+		GenerationConfig  generationConfig `json:"generationConfig"`
+	}
+	props := make(map[string]schema, len(keys))
+	for _, k := range keys {
+		props[k] = schema{Type: "STRING"}
+	}
+	body := requestBody{
+		Contents: []content{{Role: "user", Parts: []part{{Text: p.UserPrompt}}}},
+		GenerationConfig: generationConfig{
+			Temperature:      c.Temperature(p),
+			TopP:             c.Opts.TopP,
+			MaxOutputTokens:  c.Opts.MaxTokens,
+			Seed:             c.Opts.Seed,
+			ResponseMimeType: "application/json",
+			ResponseSchema:   schema{Type: "OBJECT", Properties: props, Required: keys},
+		},
+	}
+	if p.SystemPrompt != "" {
+		body.SystemInstruction = &content{Parts: []part{{Text: p.SystemPrompt}}}
+	}
+	var resp struct {
+		Candidates []struct {
+			Content      content `json:"content"`
+			FinishReason string  `json:"finishReason"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason"`
+		} `json:"promptFeedback"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	endpoint := c.url("/models/" + url.PathEscape(strings.TrimPrefix(model, "models/")) + ":generateContent")
+	rr, err := c.HTTP.R().SetContext(ctx).
+		SetHeader("x-goog-api-key", c.APIKey).
+		SetHeader("Content-Type", "application/json").
+		SetBody(c.Body(body, nil)).SetResult(&resp).
+		Post(endpoint)
+	if err != nil {
+		return "", ports.Usage{}, err
+	}
+	if rr.IsError() {
+		return "", ports.Usage{}, httpclient.HTTPError("gemini", "translate", rr)
+	}
+	usage := ports.Usage{InputTokens: resp.UsageMetadata.PromptTokenCount, OutputTokens: resp.UsageMetadata.CandidatesTokenCount}
+	if r := resp.PromptFeedback.BlockReason; r != "" {
+		return "", usage, fmt.Errorf("gemini: prompt blocked (%s): %w", r, ports.ErrContentBlocked)
+	}
+	if len(resp.Candidates) == 0 {
+		return "", usage, fmt.Errorf("no candidates returned: %w", ports.ErrMalformedResponse)
+	}
+	cand := resp.Candidates[0]
+	if slices.Contains(blockReasons, cand.FinishReason) {
+		return "", usage, fmt.Errorf("gemini: answer blocked (%s): %w", cand.FinishReason, ports.ErrContentBlocked)
+	}
+	var text strings.Builder
+	for _, pt := range cand.Content.Parts {
+		text.WriteString(pt.Text)
+	}
+	out := strings.TrimSpace(text.String())
+	if out == "" {
+		return "", usage, fmt.Errorf("empty answer (finish reason %s): %w", cand.FinishReason, ports.ErrMalformedResponse)
+	}
+	return out, usage, nil
+}
+
+// ListModels lists the models supporting generateContent.
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	var out []ports.ModelInfo
+	page := ""
+	for {
+		var resp struct {
+			Models []struct {
+				Name                       string   `json:"name"`
+				DisplayName                string   `json:"displayName"`
+				InputTokenLimit            int      `json:"inputTokenLimit"`
+				SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		r := c.HTTP.R().SetContext(ctx).SetHeader("x-goog-api-key", c.APIKey).SetQueryParam("pageSize", "1000").SetResult(&resp)
+		if page != "" {
+			r.SetQueryParam("pageToken", page)
+		}
+		rr, err := r.Get(c.url("/models"))
+		if err != nil {
+			return nil, err
+		}
+		if rr.IsError() {
+			return nil, httpclient.HTTPError("gemini", "list models", rr)
+		}
+		for _, m := range resp.Models {
+			if len(m.SupportedGenerationMethods) > 0 && !slices.Contains(m.SupportedGenerationMethods, "generateContent") {
+				continue
+			}
+			name := strings.TrimPrefix(m.Name, "models/")
+			label := m.DisplayName
+			if label == "" {
+				label = name
+			}
+			out = append(out, ports.ModelInfo{Name: name, Description: label, ContextTokens: m.InputTokenLimit})
+		}
+		if resp.NextPageToken == "" || resp.NextPageToken == page {
+			return out, nil
+		}
+		page = resp.NextPageToken
+	}
+}
+
+// url joins the base URL and tail below /v1beta unless the base URL names a version.
+func (c *Client) url(tail string) string {
+	base := c.URL(defaultBaseURL, "")
+	if !strings.HasSuffix(base, "/v1beta") && !strings.HasSuffix(base, "/v1") {
+		base += "/v1beta"
+	}
+	return base + tail
+}
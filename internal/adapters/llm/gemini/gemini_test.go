@@ -0,0 +1,164 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+// request is the part of a generateContent request the tests look at.
+type request struct {
+	SystemInstruction *struct {
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"systemInstruction"`
+	Contents []struct {
+		Role string `json:"role"`
+	} `json:"contents"`
+	GenerationConfig struct {
+		Temperature      float64 `json:"temperature"`
+		MaxOutputTokens  int     `json:"maxOutputTokens"`
+		ResponseMimeType string  `json:"responseMimeType"`
+		ResponseSchema   struct {
+			Type       string `json:"type"`
+			Properties map[string]struct {
+				Type string `json:"type"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"responseSchema"`
+	} `json:"generationConfig"`
+}
+
+// serve answers generateContent with status and answer and records the request and path.
+func serve(t *testing.T, status int, answer string, got *request, path *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-goog-api-key") != "key" {
+			t.Errorf("api key header %q", r.Header.Get("x-goog-api-key"))
+		}
+		*path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(answer))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTranslateBatchSchema(t *testing.T) {
+	var got request
+	var path string
+	srv := serve(t, http.StatusOK, `{"candidates":[{"content":{"parts":[{"text":"{\"a\":\"A!\","},{"text":"\"b\":\"B!\"}"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":40,"candidatesTokenCount":8}}`, &got, &path)
+	c := New(registry.Config{APIKey: "key", BaseURL: srv.URL, Model: "gemini-x", Options: `{"max_tokens":500}`})
+	res, err := c.TranslateBatch(context.Background(), []ports.Segment{{Key: "a"}, {Key: "b"}}, ports.TranslateParams{SystemPrompt: "sys", UserPrompt: "u", Model: "models/gemini-y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Translations["a"] != "A!" || res.Translations["b"] != "B!" || res.Usage != (ports.Usage{InputTokens: 40, OutputTokens: 8}) {
+		t.Fatalf("got %+v", res)
+	}
+	if path != "/v1beta/models/gemini-y:generateContent" {
+		t.Errorf("path %s", path)
+	}
+	cfg := got.GenerationConfig
+	if cfg.ResponseMimeType != "application/json" || cfg.ResponseSchema.Type != "OBJECT" || len(cfg.ResponseSchema.Required) != 2 || cfg.ResponseSchema.Properties["b"].Type != "STRING" {
+		t.Errorf("generation config %+v", cfg)
+	}
+	if cfg.MaxOutputTokens != 500 {
+		t.Errorf("maxOutputTokens %d", cfg.MaxOutputTokens)
+	}
+	if got.SystemInstruction == nil || got.SystemInstruction.Parts[0].Text != "sys" || got.Contents[0].Role != "user" {
+		t.Errorf("request %+v", got)
+	}
+}
+
+func TestTranslateAnswers(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		answer string
+		want   string
+		err    error
+	}{
+		{"single", http.StatusOK, `{"candidates":[{"content":{"parts":[{"text":"{\"translation\":\"Hallo\"}"}]},"finishReason":"STOP"}]}`, "Hallo", nil},
+		{"prompt blocked", http.StatusOK, `{"promptFeedback":{"blockReason":"SAFETY"}}`, "", ports.ErrContentBlocked},
+		{"answer blocked", http.StatusOK, `{"candidates":[{"content":{"parts":[]},"finishReason":"RECITATION"}]}`, "", ports.ErrContentBlocked},
+		{"no candidates", http.StatusOK, `{"candidates":[]}`, "", ports.ErrMalformedResponse},
+		{"cut off", http.StatusOK, `{"candidates":[{"content":{"parts":[]},"finishReason":"MAX_TOKENS"}]}`, "", ports.ErrMalformedResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got request
+			var path string
+			srv := serve(t, tt.status, tt.answer, &got, &path)
+			c := New(registry.Config{APIKey: "key", BaseURL: srv.URL + "/v1", Model: "gemini-x"})
+			res, err := c.Translate(context.Background(), ports.Segment{Key: "k"}, ports.TranslateParams{UserPrompt: "Hi"})
+			if !errors.Is(err, tt.err) || res.Translation != tt.want {
+				t.Fatalf("got %q, %v; want %q, %v", res.Translation, err, tt.want, tt.err)
+			}
+			if path != "/v1/models/gemini-x:generateContent" || got.SystemInstruction != nil {
+				t.Errorf("path %s, system %+v", path, got.SystemInstruction)
+			}
+		})
+	}
+}
+
+func TestHTTPErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		var got request
+		var path string
+		srv := serve(t, tt.status, `{"error":{"status":"RESOURCE_EXHAUSTED"}}`, &got, &path)
+		_, err := New(registry.Config{APIKey: "key", BaseURL: srv.URL, Model: "m"}).Translate(context.Background(), ports.Segment{Key: "k"}, ports.TranslateParams{})
+		var pe *ports.ProviderError
+		if !errors.As(err, &pe) || pe.StatusCode != tt.status || pe.Retryable() != tt.retryable || pe.Provider != "gemini" {
+			t.Errorf("status %d: got %v", tt.status, err)
+		}
+	}
+}
+
+func TestListModels(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("pageToken")
+		pages = append(pages, page)
+		w.Header().Set("Content-Type", "application/json")
+		if page == "" {
+			w.Write([]byte(`{"models":[{"name":"models/gemini-x","displayName":"Gemini X","inputTokenLimit":1000000,"supportedGenerationMethods":["generateContent"]},{"name":"models/embed","supportedGenerationMethods":["embedContent"]}],"nextPageToken":"p2"}`))
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"models/gemini-y"}]}`))
+	}))
+	defer srv.Close()
+	models, err := New(registry.Config{APIKey: "key", BaseURL: srv.URL}).ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ports.ModelInfo{
+		{Name: "gemini-x", Description: "Gemini X", ContextTokens: 1000000},
+		{Name: "gemini-y", Description: "gemini-y"},
+	}
+	if len(models) != len(want) || models[0] != want[0] || models[1] != want[1] {
+		t.Fatalf("got %+v", models)
+	}
+	if len(pages) != 2 || pages[1] != "p2" {
+		t.Fatalf("pages %v", pages)
+	}
+}
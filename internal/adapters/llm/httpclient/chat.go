@@ -0,0 +1,169 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/ports"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Structured output modes of OpenAI-style chat completions, strongest first.
+const (
+	formatAuto       = "auto"
+	formatJSONSchema = "json_schema"
+	formatJSONObject = "json_object"
+	formatNone       = "none"
+)
+
+var formatOrder = []string{formatJSONSchema, formatJSONObject, formatNone}
+
+// detectedFormats remembers, per endpoint and model, the strongest response_format the server
+// accepted, so that only the first request pays for the detection.
+var detectedFormats sync.Map
+
+// ChatEndpoint is an OpenAI-compatible /chat/completions endpoint.
+type ChatEndpoint struct {
+	Provider string
+	URL      string
+	Headers  map[string]string
+	// Format is the response_format to send; empty or "auto" detects it.
+	Format string
+	// Extra are provider-specific body fields.
+	Extra map[string]any
+}
+
+// ChatCompletions runs a chat completion whose answer must be a JSON object with the given
+// string fields and returns the raw message content with the reported token usage. In auto mode a json_schema request the
+// server rejects with 400 or 422 is repeated with json_object, then without response_format.
+func (b *Base) ChatCompletions(ctx context.Context, ep ChatEndpoint, p ports.TranslateParams, schemaName string, keys []string) (string, ports.Usage, error) {
+	model := b.ModelFor(p)
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type requestBody struct {
+		Model          string    `json:"model"`
+		Messages       []message `json:"messages"`
+		Temperature    float64   `json:"temperature"`
+		TopP           *float64  `json:"top_p,omitempty"`
+		MaxTokens      int       `json:"max_tokens,omitempty"`
+		Seed           *int64    `json:"seed,omitempty"`
+		ResponseFormat any       `json:"response_format,omitempty"`
+	}
+	body := requestBody{
+		Model:       model,
+		Messages:    []message{{Role: "system", Content: p.SystemPrompt}, {Role: "user", Content: p.UserPrompt}},
+		Temperature: b.Temperature(p),
+		TopP:        b.Opts.TopP,
+		MaxTokens:   b.Opts.MaxTokens,
+		Seed:        b.Opts.Seed,
+	}
+	formats := formatOrder
+	cacheKey := ep.URL + "\x00" + model
+	switch ep.Format {
+	case "", formatAuto:
+		if f, ok := detectedFormats.Load(cacheKey); ok {
+			formats = formatOrder[indexOf(formatOrder, f.(string)):]
+		}
+	default:
+		formats = []string{ep.Format}
+	}
+	var lastErr error
+	for i, format := range formats {
+		body.ResponseFormat = responseFormat(format, schemaName, keys)
+		var resp struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			} `json:"choices"`
+			Usage struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		r := b.HTTP.R().SetContext(ctx).
+			SetHeader("Content-Type", "application/json").
+			SetHeaders(ep.Headers).
+			SetBody(b.Body(body, ep.Extra)).SetResult(&resp)
+		rr, err := r.Post(ep.URL)
+		if err != nil {
+			return "", ports.Usage{}, err
+		}
+		if rr.IsError() {
+			lastErr = HTTPError(ep.Provider, "translate", rr)
+			if i < len(formats)-1 && rejectsFormat(rr) {
+				continue
+			}
+			return "", ports.Usage{}, lastErr
+		}
+		if i > 0 {
+			detectedFormats.Store(cacheKey, format)
+		}
+		usage := ports.Usage{InputTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens}
+		if len(resp.Choices) == 0 {
+			return "", usage, fmt.Errorf("no choices returned: %w", ports.ErrMalformedResponse)
+		}
+		return strings.TrimSpace(resp.Choices[0].Message.Content), usage, nil
+	}
+	return "", ports.Usage{}, lastErr
+}
+
+// responseFormat builds the response_format value of a request, nil for none.
+func responseFormat(format, schemaName string, keys []string) any {
+	switch format {
+	case formatJSONSchema:
+		type schemaProps struct {
+			Type string `json:"type"`
+		}
+		type schemaDef struct {
+			Type                 string                 `json:"type"`
+			Properties           map[string]schemaProps `json:"properties"`
+			Required             []string               `json:"required"`
+			AdditionalProperties bool                   `json:"additionalProperties"`
+		}
+		type responseJSONSchema struct {
+			Name   string    `json:"name"`
+			Strict bool      `json:"strict"`
+			Schema schemaDef `json:"schema"`
+		}
+		props := make(map[string]schemaProps, len(keys))
+		for _, k := range keys {
+			props[k] = schemaProps{Type: "string"}
+		}
+		return struct {
+			Type       string             `json:"type"`
+			JSONSchema responseJSONSchema `json:"json_schema"`
+		}{
+			Type: "json_schema",
+			JSONSchema: responseJSONSchema{
+				Name:   schemaName,
+				Strict: true,
+				Schema: schemaDef{Type: "object", Properties: props, Required: keys, AdditionalProperties: false},
+			},
+		}
+	case formatJSONObject:
+		return struct {
+			Type string `json:"type"`
+		}{Type: "json_object"}
+	}
+	return nil
+}
+
+// rejectsFormat reports whether an error response may be caused by an unsupported
+// response_format.
+func rejectsFormat(r *resty.Response) bool {
+	return r.StatusCode() == 400 || r.StatusCode() == 422
+}
+
+func indexOf(list []string, v string) int {
+	for i, s := range list {
+		if s == v {
+			return i
+		}
+	}
+	return 0
+}
@@ -1,270 +1,177 @@
+// Package httpclient holds the HTTP plumbing shared by the provider adapters: the resty
+// client, JSON answer extraction, typed errors and OpenAI-style chat completions.
 package httpclient
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"locail/internal/ports"
+	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
-type Client struct {
-	ProviderType string
-	APIKey       string
-	BaseURL      string
-	Model        string
-	http         *resty.Client
-}
+// DefaultTimeout bounds a request when the provider options set no timeout_seconds.
+const DefaultTimeout = 30 * time.Second
 
-func New(providerType, apiKey, baseURL, model string) *Client {
-	// Increase default HTTP timeout to 30s to accommodate slower local/remote providers
-	c := resty.New().SetTimeout(30 * time.Second)
-	return &Client{ProviderType: strings.ToLower(providerType), APIKey: apiKey, BaseURL: baseURL, Model: model, http: c}
+// Options are the options_json keys every adapter understands. Unset sampling values leave
+// the provider's defaults.
+type Options struct {
+	Temperature *float64 `json:"temperature"`
+	TopP        *float64 `json:"top_p"`
+	MaxTokens   int      `json:"max_tokens"`
+	Seed        *int64   `json:"seed"`
+	// TimeoutSeconds bounds each request; big local models may need minutes.
+	TimeoutSeconds float64 `json:"timeout_seconds"`
+	// ExtraBody is merged into every request body, overriding what the adapter sends.
+	ExtraBody map[string]any `json:"extra_body"`
+	Network
 }
 
-func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
-	switch c.ProviderType {
-	case "openrouter":
-		return c.translateOpenRouter(ctx, p)
-	case "ollama":
-		return c.translateOllama(ctx, p)
-	default:
-		return ports.TranslateResult{}, fmt.Errorf("unsupported provider: %s", c.ProviderType)
+// Timeout returns the request timeout.
+func (o Options) Timeout() time.Duration {
+	if o.TimeoutSeconds > 0 {
+		return time.Duration(o.TimeoutSeconds * float64(time.Second))
 	}
+	return DefaultTimeout
 }
 
-func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
-	switch c.ProviderType {
-	case "ollama":
-		base := c.BaseURL
-		if base == "" {
-			base = "http://localhost:11434"
-		}
-		url := strings.TrimRight(base, "/") + "/api/tags"
-		var resp struct {
-			Models []struct {
-				Name string `json:"name"`
-			} `json:"models"`
-		}
-		r, err := c.http.R().SetContext(ctx).SetResult(&resp).Get(url)
-		if err != nil {
-			return nil, err
-		}
-		if r.IsError() {
-			return nil, fmt.Errorf("ollama list models: %s; body: %s", r.Status(), r.String())
-		}
-		out := make([]ports.ModelInfo, 0, len(resp.Models))
-		for _, m := range resp.Models {
-			out = append(out, ports.ModelInfo{Name: m.Name})
-		}
-		return out, nil
-	case "openrouter":
-		base := c.BaseURL
-		if base == "" {
-			base = "https://openrouter.ai"
-		}
-		url := openRouterURL(base, "/models")
-		var resp struct {
-			Data []struct {
-				ID            string `json:"id"`
-				Name          string `json:"name"`
-				ContextLength int    `json:"context_length"`
-			} `json:"data"`
-		}
-		r := c.http.R().SetContext(ctx).
-			SetHeader("Authorization", "Bearer "+c.APIKey).
-			SetResult(&resp)
-		rr, err := r.Get(url)
-		if err != nil {
-			return nil, err
-		}
-		if rr.IsError() {
-			return nil, fmt.Errorf("openrouter list models: %s; body: %s", rr.Status(), rr.String())
-		}
-		out := make([]ports.ModelInfo, 0, len(resp.Data))
-		for _, d := range resp.Data {
-			label := d.Name
-			if label == "" {
-				label = d.ID
-			}
-			out = append(out, ports.ModelInfo{Name: d.ID, Description: label, ContextTokens: d.ContextLength})
-		}
-		return out, nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", c.ProviderType)
-	}
+// Base is embedded by provider clients.
+type Base struct {
+	APIKey  string
+	BaseURL string
+	Model   string
+	Opts    Options
+	HTTP    *resty.Client
 }
 
-func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+// NewBase parses the common options out of options (the provider's options_json); invalid
+// JSON is ignored. The HTTP client uses the provider's network settings merged with the
+// global ones.
+func NewBase(apiKey, baseURL, model, options string) Base {
+	b := Base{APIKey: apiKey, BaseURL: baseURL, Model: model}
+	if strings.TrimSpace(options) != "" {
+		_ = json.Unmarshal([]byte(options), &b.Opts)
+	}
+	b.HTTP = resty.New().SetTimeout(b.Opts.Timeout())
+	b.Opts.Network = b.Opts.Network.Merge(DefaultNetwork())
+	if t, err := b.Opts.Network.Transport(); err != nil {
+		b.HTTP.SetTransport(failingTransport{err})
+	} else if t != nil {
+		b.HTTP.SetTransport(t)
+	}
+	return b
+}
 
-func (c *Client) translateOpenRouter(ctx context.Context, p ports.TranslateParams) (ports.TranslateResult, error) {
-	base := c.BaseURL
-	if base == "" {
-		base = "https://openrouter.ai"
-	}
-	url := openRouterURL(base, "/chat/completions")
-	model := p.Model
-	if model == "" {
-		model = c.Model
+// Temperature returns the configured temperature, else the request's.
+func (b *Base) Temperature(p ports.TranslateParams) float64 {
+	if b.Opts.Temperature != nil {
+		return *b.Opts.Temperature
 	}
-	// Prefer JSON schema for stronger structured output; fallback to json_object if 400
-	type message struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	}
-	type schemaProps struct {
-		Type string `json:"type"`
-	}
-	type schemaDef struct {
-		Type                 string                 `json:"type"`
-		Properties           map[string]schemaProps `json:"properties"`
-		Required             []string               `json:"required"`
-		AdditionalProperties bool                   `json:"additionalProperties"`
-	}
-	type responseJSONSchema struct {
-		Name   string    `json:"name"`
-		Strict bool      `json:"strict"`
-		Schema schemaDef `json:"schema"`
-	}
-	type responseFormat struct {
-		Type       string             `json:"type"`
-		JSONSchema responseJSONSchema `json:"json_schema"`
+	return p.Temperature
+}
+
+// Body returns body with the extra options merged in: extra_body from the options, then
+// extra (adapter-specific keys such as routing preferences). Nested objects are merged
+// key by key.
+func (b *Base) Body(body any, extra map[string]any) any {
+	if len(b.Opts.ExtraBody) == 0 && len(extra) == 0 {
+		return body
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return body
 	}
-	type requestBody struct {
-		Model          string    `json:"model"`
-		Messages       []message `json:"messages"`
-		Temperature    float64   `json:"temperature"`
-		ResponseFormat any       `json:"response_format"`
+	var m map[string]any
+	if json.Unmarshal(raw, &m) != nil {
+		return body
 	}
-	schema := responseFormat{
-		Type: "json_schema",
-		JSONSchema: responseJSONSchema{
-			Name:   "translation",
-			Strict: true,
-			Schema: schemaDef{
-				Type:                 "object",
-				Properties:           map[string]schemaProps{"translation": {Type: "string"}},
-				Required:             []string{"translation"},
-				AdditionalProperties: false,
-			},
-		},
+	mergeJSON(m, extra)
+	mergeJSON(m, b.Opts.ExtraBody)
+	return m
+}
+
+func mergeJSON(dst, src map[string]any) {
+	for k, v := range src {
+		sub, ok := v.(map[string]any)
+		if cur, isMap := dst[k].(map[string]any); ok && isMap {
+			mergeJSON(cur, sub)
+			continue
+		}
+		dst[k] = v
 	}
-	body := requestBody{
-		Model:          model,
-		Messages:       []message{{Role: "system", Content: p.SystemPrompt}, {Role: "user", Content: p.UserPrompt}},
-		Temperature:    p.Temperature,
-		ResponseFormat: schema,
+}
+
+// ModelFor returns the model of the request, falling back to the configured one.
+func (b *Base) ModelFor(p ports.TranslateParams) string {
+	if p.Model != "" {
+		return p.Model
 	}
-	var resp struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+	return b.Model
+}
+
+// URL joins the base URL, or def when none is configured, and tail.
+func (b *Base) URL(def, tail string) string {
+	base := strings.TrimRight(b.BaseURL, "/")
+	if base == "" {
+		base = def
 	}
-	r := c.http.R().SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+c.APIKey).
-		SetHeader("HTTP-Referer", "https://locail.app").
-		SetHeader("X-Title", "locail").
-		SetHeader("Content-Type", "application/json").
-		SetBody(body).SetResult(&resp)
-	rr, err := r.Post(url)
+	return base + tail
+}
+
+// ChatFunc asks a model for a JSON object with the given string fields and returns the
+// answer text. name names the answer (a schema or tool name).
+type ChatFunc func(ctx context.Context, p ports.TranslateParams, name string, keys []string) (string, ports.Usage, error)
+
+// TranslateJSON translates one segment with a chat answering {"<result key>": "..."}.
+func TranslateJSON(ctx context.Context, chat ChatFunc, p ports.TranslateParams) (ports.TranslateResult, error) {
+	key := ResultKey(p)
+	content, usage, err := chat(ctx, p, key, []string{key})
 	if err != nil {
 		return ports.TranslateResult{}, err
 	}
-	if rr.IsError() {
-		// Fallback to json_object if schema is not supported
-		if rr.StatusCode() == 400 {
-			body.ResponseFormat = struct {
-				Type string `json:"type"`
-			}{Type: "json_object"}
-			r = c.http.R().SetContext(ctx).
-				SetHeader("Authorization", "Bearer "+c.APIKey).
-				SetHeader("HTTP-Referer", "https://locail.app").
-				SetHeader("X-Title", "locail").
-				SetHeader("Content-Type", "application/json").
-				SetBody(body).SetResult(&resp)
-			rr2, err2 := r.Post(url)
-			if err2 != nil {
-				return ports.TranslateResult{}, err2
-			}
-			if rr2.IsError() {
-				return ports.TranslateResult{}, fmt.Errorf("openrouter translate: %s; body: %s", rr2.Status(), rr2.String())
-			}
-		} else {
-			return ports.TranslateResult{}, fmt.Errorf("openrouter translate: %s; body: %s", rr.Status(), rr.String())
-		}
-	}
-	if len(resp.Choices) == 0 {
-		return ports.TranslateResult{}, fmt.Errorf("no choices returned")
-	}
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
-	tr, err := extractTranslation(content)
+	tr, err := ExtractField(content, key)
 	if err != nil {
 		return ports.TranslateResult{}, err
 	}
-	return ports.TranslateResult{Translation: tr, Raw: content}, nil
+	return ports.TranslateResult{Translation: tr, Raw: content, Usage: usage}, nil
 }
 
-func (c *Client) translateOllama(ctx context.Context, p ports.TranslateParams) (ports.TranslateResult, error) {
-	base := c.BaseURL
-	if base == "" {
-		base = "http://localhost:11434"
-	}
-	url := strings.TrimRight(base, "/") + "/api/chat"
-	model := p.Model
-	if model == "" {
-		model = c.Model
-	}
-	type ollamaMsg struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	}
-	type ollamaOptions struct {
-		Temperature float64 `json:"temperature"`
-	}
-	type ollamaBody struct {
-		Model    string        `json:"model"`
-		Messages []ollamaMsg   `json:"messages"`
-		Stream   bool          `json:"stream"`
-		Format   string        `json:"format"`
-		Options  ollamaOptions `json:"options"`
-	}
-	body := ollamaBody{
-		Model:    model,
-		Messages: []ollamaMsg{{Role: "system", Content: p.SystemPrompt}, {Role: "user", Content: p.UserPrompt}},
-		Stream:   false,
-		Format:   "json",
-		Options:  ollamaOptions{Temperature: p.Temperature},
-	}
-	var resp struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	}
-	r := c.http.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(body).SetResult(&resp)
-	rr, err := r.Post(url)
+// TranslateBatchJSON sends all segments in one request, asking for a JSON object keyed by
+// segment key.
+func TranslateBatchJSON(ctx context.Context, chat ChatFunc, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	keys := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		keys = append(keys, seg.Key)
+	}
+	content, usage, err := chat(ctx, p, "translations", keys)
 	if err != nil {
-		return ports.TranslateResult{}, err
+		return ports.BatchResult{}, err
 	}
-	if rr.IsError() {
-		return ports.TranslateResult{}, fmt.Errorf("ollama translate: %s; body: %s", rr.Status(), rr.String())
-	}
-	content := strings.TrimSpace(resp.Message.Content)
-	tr, err := extractTranslation(content)
+	out, err := ExtractObject(content)
 	if err != nil {
-		return ports.TranslateResult{}, err
+		return ports.BatchResult{}, err
 	}
-	return ports.TranslateResult{Translation: tr, Raw: content}, nil
+	return ports.BatchResult{Translations: out, Raw: content, Usage: usage}, nil
 }
 
-var translationRE = regexp.MustCompile(`(?s)\"translation\"\s*:\s*\"(.*?)\"`)
+func ResultKey(p ports.TranslateParams) string {
+	if p.ResultKey != "" {
+		return p.ResultKey
+	}
+	return "translation"
+}
 
-func extractTranslation(content string) (string, error) {
+// ExtractField pulls a string field (e.g. "translation") out of a model response,
+// tolerating code fences, surrounding prose and plain-text answers.
+func ExtractField(content, key string) (string, error) {
+	fieldRE := regexp.MustCompile(`(?s)"` + regexp.QuoteMeta(key) + `"\s*:\s*"(.*?)"`)
 	s := strings.TrimSpace(content)
 	// If content contains fenced code, try to extract inner block
 	if idx := strings.Index(s, "```"); idx >= 0 {
@@ -276,14 +183,14 @@ func extractTranslation(content string) (string, error) {
 		}
 	}
 	// Try direct JSON first
-	var obj struct {
-		Translation string `json:"translation"`
-	}
-	if err := json.Unmarshal([]byte(s), &obj); err == nil && obj.Translation != "" {
-		return obj.Translation, nil
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(s), &obj); err == nil {
+		if v, ok := obj[key].(string); ok && v != "" {
+			return v, nil
+		}
 	}
 	// Regex fallback
-	if m := translationRE.FindStringSubmatch(s); len(m) == 2 {
+	if m := fieldRE.FindStringSubmatch(s); len(m) == 2 {
 		t := strings.ReplaceAll(m[1], `\n`, "\n")
 		t = strings.ReplaceAll(t, `\"`, `"`)
 		return t, nil
@@ -292,10 +199,12 @@ func extractTranslation(content string) (string, error) {
 	if i := strings.Index(s, "{"); i >= 0 {
 		if j := strings.LastIndex(s, "}"); j > i {
 			inner := s[i : j+1]
-			if err := json.Unmarshal([]byte(inner), &obj); err == nil && obj.Translation != "" {
-				return obj.Translation, nil
+			if err := json.Unmarshal([]byte(inner), &obj); err == nil {
+				if v, ok := obj[key].(string); ok && v != "" {
+					return v, nil
+				}
 			}
-			if m := translationRE.FindStringSubmatch(inner); len(m) == 2 {
+			if m := fieldRE.FindStringSubmatch(inner); len(m) == 2 {
 				t := strings.ReplaceAll(m[1], `\n`, "\n")
 				t = strings.ReplaceAll(t, `\"`, `"`)
 				return t, nil
@@ -306,7 +215,7 @@ func extractTranslation(content string) (string, error) {
 	if !strings.Contains(s, "{") {
 		// If there's a leading label like "Translation:" remove it
 		lower := strings.ToLower(s)
-		for _, k := range []string{"translation:", "translated:", "result:", "output:"} {
+		for _, k := range []string{key + ":", "translation:", "translated:", "result:", "output:"} {
 			if pos := strings.Index(lower, k); pos >= 0 && pos < 80 {
 				cand := strings.TrimSpace(s[pos+len(k):])
 				if cand != "" {
@@ -318,10 +227,79 @@ func extractTranslation(content string) (string, error) {
 			return s, nil
 		}
 	}
-	return "", fmt.Errorf("failed to parse translation JSON; content: %s", abbreviate(s, 2000))
+	return "", fmt.Errorf("failed to parse %s JSON: %w; content: %s", key, ports.ErrMalformedResponse, Abbreviate(s, 2000))
 }
 
-func abbreviate(s string, n int) string {
+// ExtractObject parses a JSON object of string values out of a model response, tolerating
+// code fences and surrounding prose. Non-string values are skipped.
+func ExtractObject(content string) (map[string]string, error) {
+	s := strings.TrimSpace(content)
+	if idx := strings.Index(s, "```"); idx >= 0 {
+		rest := strings.TrimPrefix(s[idx+3:], "json")
+		if j := strings.Index(rest, "```"); j >= 0 {
+			s = strings.TrimSpace(rest[:j])
+		}
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		i, j := strings.Index(s, "{"), strings.LastIndex(s, "}")
+		if i < 0 || j <= i || json.Unmarshal([]byte(s[i:j+1]), &obj) != nil {
+			return nil, fmt.Errorf("failed to parse batch JSON: %w; content: %s", ports.ErrMalformedResponse, Abbreviate(s, 2000))
+		}
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if str, ok := v.(string); ok {
+			out[k] = str
+		}
+	}
+	return out, nil
+}
+
+// maskedTokenRE matches the placeholders, tags and protected terms the translator masked.
+var maskedTokenRE = regexp.MustCompile(`__(?:PH|TAG|PT)_\d+__`)
+
+// ProtectTokens escapes text for a machine translation engine's markup mode and wraps every
+// masked token in an element the engine leaves untranslated.
+func ProtectTokens(text, open, close string) string {
+	escaped := html.EscapeString(text)
+	return maskedTokenRE.ReplaceAllStringFunc(escaped, func(tok string) string { return open + tok + close })
+}
+
+// RestoreTokens undoes ProtectTokens on a translation. The wrappers are matched loosely since
+// engines may add whitespace or attributes around them; wrapper must capture the token.
+func RestoreTokens(text string, wrapper *regexp.Regexp) string {
+	return html.UnescapeString(wrapper.ReplaceAllString(text, "$1"))
+}
+
+// HTTPError converts an unsuccessful response into a *ports.ProviderError.
+func HTTPError(provider, op string, r *resty.Response) error {
+	return &ports.ProviderError{
+		Provider:   provider,
+		Op:         op,
+		StatusCode: r.StatusCode(),
+		Status:     r.Status(),
+		RetryAfter: retryAfter(r.Header().Get("Retry-After"), time.Now()),
+		Body:       Abbreviate(r.String(), 2000),
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func Abbreviate(s string, n int) string {
 	if len(s) <= n {
 		return s
 	}
@@ -330,16 +308,3 @@ func abbreviate(s string, n int) string {
 	}
 	return s[:n-3] + "..."
 }
-
-// openRouterURL builds a URL for OpenRouter whether base contains /api/v1 or not.
-func openRouterURL(base, tail string) string {
-	b := strings.TrimRight(base, "/")
-	// If base already ends with /api/v1 or /api/v1/..., don't duplicate
-	if strings.Contains(b, "/api/v1") {
-		// ensure it ends with /api/v1
-		idx := strings.Index(b, "/api/v1")
-		b = b[:idx+len("/api/v1")]
-		return b + tail
-	}
-	return b + "/api/v1" + tail
-}
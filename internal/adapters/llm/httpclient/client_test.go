@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"locail/internal/ports"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"absent", "", 0},
+		{"seconds", "120", 2 * time.Minute},
+		{"padded seconds", " 5 ", 5 * time.Second},
+		{"zero", "0", 0},
+		{"negative", "-3", 0},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, now); got != tt.want {
+				t.Fatalf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseOptions(t *testing.T) {
+	b := NewBase("", "", "m", `{"temperature":0.2,"timeout_seconds":120,"extra_body":{"options":{"num_ctx":8192},"keep_alive":"5m"}}`)
+	if got := b.Temperature(ports.TranslateParams{Temperature: 0.9}); got != 0.2 {
+		t.Errorf("temperature %v, want the option", got)
+	}
+	if got := b.HTTP.GetClient().Timeout; got != 2*time.Minute {
+		t.Errorf("timeout %v", got)
+	}
+	body := map[string]any{"model": "m", "options": map[string]any{"temperature": 0.2, "num_ctx": 2048}}
+	got := b.Body(body, map[string]any{"keep_alive": "1m", "stream": false}).(map[string]any)
+	opts := got["options"].(map[string]any)
+	if opts["num_ctx"] != 8192.0 || opts["temperature"] != 0.2 || got["keep_alive"] != "5m" || got["stream"] != false || got["model"] != "m" {
+		t.Fatalf("body %v", got)
+	}
+
+	plain := NewBase("", "", "m", "not json")
+	if got := plain.Temperature(ports.TranslateParams{Temperature: 0.9}); got != 0.9 {
+		t.Errorf("temperature %v, want the request's", got)
+	}
+	if got := plain.HTTP.GetClient().Timeout; got != DefaultTimeout {
+		t.Errorf("timeout %v, want the default", got)
+	}
+	if got := plain.Body(body, nil); got.(map[string]any)["options"].(map[string]any)["num_ctx"] != 2048 {
+		t.Errorf("body %v, want it unchanged", got)
+	}
+}
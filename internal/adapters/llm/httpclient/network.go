@@ -0,0 +1,217 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyDirect as proxy_url connects directly, ignoring the global and environment proxies.
+const ProxyDirect = "direct"
+
+// Network are the connection settings of a provider, read from its options_json. Unset
+// fields fall back to the global defaults (SetDefaultNetwork), and the proxy finally to the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type Network struct {
+	// ProxyURL is an http, https, socks5 or socks5h URL, or ProxyDirect.
+	ProxyURL string `json:"proxy_url,omitempty"`
+	// NoProxy lists hosts, domains (".example.com") and CIDRs reached without the proxy.
+	NoProxy []string `json:"no_proxy,omitempty"`
+	// CAFile is a PEM bundle trusted in addition to the system roots, e.g. the certificate
+	// of a TLS-inspecting proxy.
+	CAFile         string `json:"ca_file,omitempty"`
+	ClientCertFile string `json:"client_cert_file,omitempty"`
+	ClientKeyFile  string `json:"client_key_file,omitempty"`
+	// InsecureSkipVerify disables certificate checks; anyone on the path can read the traffic.
+	InsecureSkipVerify *bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// InsecureWarning is shown wherever insecure_skip_verify is in effect.
+const InsecureWarning = "TLS certificate verification is disabled: API keys and texts can be intercepted. Prefer ca_file with your proxy's certificate."
+
+var (
+	networkMu      sync.RWMutex
+	defaultNetwork Network
+	// transports reuses connections across the clients built for one configuration, keyed by
+	// the settings and the modification times of the files they name.
+	transports = map[string]http.RoundTripper{}
+)
+
+// SetDefaultNetwork sets the global settings providers fall back to.
+func SetDefaultNetwork(n Network) {
+	networkMu.Lock()
+	defer networkMu.Unlock()
+	defaultNetwork = n
+	transports = map[string]http.RoundTripper{}
+}
+
+func DefaultNetwork() Network {
+	networkMu.RLock()
+	defer networkMu.RUnlock()
+	return defaultNetwork
+}
+
+// NetworkOf returns the settings in effect for a provider's options_json.
+func NetworkOf(options string) Network {
+	var n Network
+	if strings.TrimSpace(options) != "" {
+		_ = json.Unmarshal([]byte(options), &n)
+	}
+	return n.Merge(DefaultNetwork())
+}
+
+// Merge fills the unset fields of n from def. The client certificate and key are taken as
+// a pair.
+func (n Network) Merge(def Network) Network {
+	if n.ProxyURL == "" {
+		n.ProxyURL = def.ProxyURL
+	}
+	if len(n.NoProxy) == 0 {
+		n.NoProxy = def.NoProxy
+	}
+	if n.CAFile == "" {
+		n.CAFile = def.CAFile
+	}
+	if n.ClientCertFile == "" && n.ClientKeyFile == "" {
+		n.ClientCertFile, n.ClientKeyFile = def.ClientCertFile, def.ClientKeyFile
+	}
+	if n.InsecureSkipVerify == nil {
+		n.InsecureSkipVerify = def.InsecureSkipVerify
+	}
+	return n
+}
+
+func (n Network) Insecure() bool { return n.InsecureSkipVerify != nil && *n.InsecureSkipVerify }
+
+func (n Network) empty() bool {
+	return n.ProxyURL == "" && len(n.NoProxy) == 0 && n.CAFile == "" && n.ClientCertFile == "" && n.ClientKeyFile == "" && !n.Insecure()
+}
+
+// Validate checks the proxy URL and loads the certificate files.
+func (n Network) Validate() error {
+	_, err := n.transport()
+	return err
+}
+
+// Transport returns the round tripper for the settings; nil when none are set, leaving
+// resty's default.
+func (n Network) Transport() (http.RoundTripper, error) {
+	if n.empty() {
+		return nil, nil
+	}
+	key := n.cacheKey()
+	networkMu.RLock()
+	t, ok := transports[key]
+	networkMu.RUnlock()
+	if ok {
+		return t, nil
+	}
+	t, err := n.transport()
+	if err != nil {
+		return nil, err
+	}
+	if n.Insecure() {
+		log.Printf("warning: %s", InsecureWarning)
+	}
+	networkMu.Lock()
+	transports[key] = t
+	networkMu.Unlock()
+	return t, nil
+}
+
+// cacheKey identifies a transport; a replaced certificate file gives a new key, so that it
+// is loaded again.
+func (n Network) cacheKey() string {
+	raw, _ := json.Marshal(n)
+	key := string(raw)
+	for _, f := range []string{n.CAFile, n.ClientCertFile, n.ClientKeyFile} {
+		if st, err := os.Stat(f); err == nil {
+			key += fmt.Sprintf("\x00%d:%d", st.ModTime().UnixNano(), st.Size())
+		}
+	}
+	return key
+}
+
+func (n Network) transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	proxy, err := n.proxy()
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: n.Insecure()}
+	if n.CAFile != "" {
+		pem, err := os.ReadFile(n.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file: no PEM certificates in %s", n.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (n.ClientCertFile == "") != (n.ClientKeyFile == "") {
+		return nil, errors.New("client_cert_file and client_key_file must be set together")
+	}
+	if n.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(n.ClientCertFile, n.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = cfg
+	return t, nil
+}
+
+func (n Network) proxy() (func(*http.Request) (*url.URL, error), error) {
+	noProxy := strings.Join(n.NoProxy, ",")
+	switch n.ProxyURL {
+	case ProxyDirect:
+		return nil, nil
+	case "":
+		if noProxy == "" {
+			return http.ProxyFromEnvironment, nil
+		}
+		cfg := httpproxy.FromEnvironment()
+		cfg.NoProxy = noProxy
+		return proxyFunc(cfg), nil
+	}
+	u, err := url.Parse(n.ProxyURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("proxy_url: invalid URL %q", n.ProxyURL)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy_url: unsupported scheme %q (want http, https, socks5 or socks5h)", u.Scheme)
+	}
+	return proxyFunc(&httpproxy.Config{HTTPProxy: n.ProxyURL, HTTPSProxy: n.ProxyURL, NoProxy: noProxy}), nil
+}
+
+func proxyFunc(cfg *httpproxy.Config) func(*http.Request) (*url.URL, error) {
+	f := cfg.ProxyFunc()
+	return func(r *http.Request) (*url.URL, error) { return f(r.URL) }
+}
+
+// failingTransport fails every request with the error of invalid network settings, so
+// ListModels, Test and translations all report it.
+type failingTransport struct{ err error }
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("network settings: %w", t.err)
+}
@@ -0,0 +1,232 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	yes, no := true, false
+	def := Network{ProxyURL: "http://global:3128", NoProxy: []string{".corp"}, CAFile: "ca.pem", ClientCertFile: "c.pem", ClientKeyFile: "k.pem", InsecureSkipVerify: &yes}
+	tests := []struct {
+		name string
+		n    Network
+		want Network
+	}{
+		{"all from the defaults", Network{}, def},
+		{"own settings win", Network{ProxyURL: "direct", NoProxy: []string{"a"}, CAFile: "own.pem", InsecureSkipVerify: &no},
+			Network{ProxyURL: "direct", NoProxy: []string{"a"}, CAFile: "own.pem", ClientCertFile: "c.pem", ClientKeyFile: "k.pem", InsecureSkipVerify: &no}},
+		// a half pair is kept, not completed from the defaults, so that Validate reports it
+		{"certificate pair", Network{ClientCertFile: "own.pem"},
+			Network{ProxyURL: def.ProxyURL, NoProxy: def.NoProxy, CAFile: "ca.pem", ClientCertFile: "own.pem", InsecureSkipVerify: &yes}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.n.Merge(def)
+			if got.ProxyURL != tt.want.ProxyURL || strings.Join(got.NoProxy, ",") != strings.Join(tt.want.NoProxy, ",") ||
+				got.CAFile != tt.want.CAFile || got.ClientCertFile != tt.want.ClientCertFile || got.ClientKeyFile != tt.want.ClientKeyFile ||
+				got.Insecure() != tt.want.Insecure() {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://env:8080")
+	t.Setenv("HTTPS_PROXY", "http://env:8080")
+	t.Setenv("NO_PROXY", "")
+	tests := []struct {
+		name string
+		n    Network
+		url  string
+		want string
+		err  string
+	}{
+		{name: "explicit", n: Network{ProxyURL: "http://proxy:3128"}, url: "https://api.example.com", want: "http://proxy:3128"},
+		{name: "socks", n: Network{ProxyURL: "socks5h://proxy:1080"}, url: "https://api.example.com", want: "socks5h://proxy:1080"},
+		{name: "explicit with no_proxy domain", n: Network{ProxyURL: "http://proxy:3128", NoProxy: []string{".corp"}}, url: "https://llm.corp", want: ""},
+		{name: "explicit with no_proxy cidr", n: Network{ProxyURL: "http://proxy:3128", NoProxy: []string{"10.0.0.0/8"}}, url: "http://10.1.2.3:11434", want: ""},
+		{name: "direct", n: Network{ProxyURL: ProxyDirect}, url: "https://api.example.com", want: ""},
+		{name: "environment with no_proxy", n: Network{NoProxy: []string{"llm.internal"}}, url: "https://api.example.com", want: "http://env:8080"},
+		{name: "environment excluded by no_proxy", n: Network{NoProxy: []string{"llm.internal"}}, url: "https://llm.internal", want: ""},
+		{name: "bad scheme", n: Network{ProxyURL: "ftp://proxy:21"}, err: "unsupported scheme"},
+		{name: "no host", n: Network{ProxyURL: "proxy"}, err: "invalid URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.n.proxy()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if f != nil {
+				u, _ := url.Parse(tt.url)
+				p, err := f(&http.Request{URL: u})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p != nil {
+					got = p.String()
+				}
+			}
+			if got != tt.want {
+				t.Fatalf("proxy for %s = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+// writePEM writes a PEM block to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	dir := t.TempDir()
+	ca := writePEM(t, dir, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	junk := filepath.Join(dir, "junk.pem")
+	os.WriteFile(junk, []byte("not a certificate"), 0o600)
+	yes := true
+	tests := []struct {
+		name   string
+		n      Network
+		reject bool
+		err    string
+	}{
+		{name: "system roots only", n: Network{ProxyURL: ProxyDirect}, reject: true},
+		{name: "ca bundle", n: Network{ProxyURL: ProxyDirect, CAFile: ca}},
+		{name: "insecure", n: Network{ProxyURL: ProxyDirect, InsecureSkipVerify: &yes}},
+		{name: "missing bundle", n: Network{CAFile: filepath.Join(dir, "nope.pem")}, err: "ca_file"},
+		{name: "no certificates", n: Network{CAFile: junk}, err: "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.n.transport()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tr.CloseIdleConnections()
+			_, err = (&http.Client{Transport: tr}).Get(srv.URL)
+			if (err != nil) != tt.reject {
+				t.Fatalf("request error %v, want rejected %v", err, tt.reject)
+			}
+		})
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "client"}, NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherDER, _ := x509.MarshalECPrivateKey(other)
+	cert := writePEM(t, dir, "cert.pem", "CERTIFICATE", der)
+	keyFile := writePEM(t, dir, "key.pem", "EC PRIVATE KEY", keyDER)
+	otherKey := writePEM(t, dir, "other.pem", "EC PRIVATE KEY", otherDER)
+	tests := []struct {
+		name string
+		n    Network
+		err  string
+	}{
+		{"pair", Network{ClientCertFile: cert, ClientKeyFile: keyFile}, ""},
+		{"certificate only", Network{ClientCertFile: cert}, "must be set together"},
+		{"key only", Network{ClientKeyFile: keyFile}, "must be set together"},
+		{"mismatched key", Network{ClientCertFile: cert, ClientKeyFile: otherKey}, "client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.n.transport()
+			if tt.err == "" {
+				if err != nil || len(tr.TLSClientConfig.Certificates) != 1 {
+					t.Fatalf("got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTransportCache(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	t.Cleanup(func() { SetDefaultNetwork(Network{}) })
+	ca := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	n := Network{CAFile: ca}
+	first, err := n.Transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := n.Transport(); again != first {
+		t.Fatal("same settings built a new transport")
+	}
+	// a replaced bundle is loaded again
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(ca, later, later); err != nil {
+		t.Fatal(err)
+	}
+	replaced, _ := n.Transport()
+	if replaced == first {
+		t.Fatal("replaced ca_file served from the cache")
+	}
+	SetDefaultNetwork(Network{})
+	if fresh, _ := n.Transport(); fresh == replaced {
+		t.Fatal("SetDefaultNetwork kept the cached transports")
+	}
+	if tr, err := (Network{}).Transport(); tr != nil || err != nil {
+		t.Fatalf("empty settings: %v, %v", tr, err)
+	}
+}
+
+func TestInvalidNetworkFailsRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	b := NewBase("", srv.URL, "", `{"proxy_url":"ftp://proxy"}`)
+	_, err := b.HTTP.R().Get(srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "network settings: proxy_url") {
+		t.Fatalf("got %v", err)
+	}
+}
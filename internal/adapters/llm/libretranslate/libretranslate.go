@@ -0,0 +1,128 @@
+// Package libretranslate talks to a LibreTranslate server. It translates segment text
+// directly and ignores prompts.
+package libretranslate
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+	"regexp"
+	"strings"
+)
+
+const defaultBaseURL = "http://localhost:5000"
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "libretranslate",
+		Label:          "LibreTranslate",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL},
+			{Name: "api_key", Label: "API key", Kind: "secret", Help: "Only if the server requires one"},
+		},
+		Capabilities: registry.Capabilities{Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Text goes out as HTML with tokens in translate="no" spans.
+var wrapperRE = regexp.MustCompile(`<span[^>]*>\s*(__(?:PH|TAG|PT)_\d+__)\s*</span>`)
+
+func protect(text string) string {
+	return httpclient.ProtectTokens(text, `<span translate="no">`, `</span>`)
+}
+
+type Client struct {
+	httpclient.Base
+}
+
+func New(cfg registry.Config) *Client {
+	return &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+}
+
+// Promptless reports that LibreTranslate ignores prompts.
+func (c *Client) Promptless() bool { return true }
+
+func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	out, err := c.translate(ctx, []string{protect(seg.Text)}, p)
+	if err != nil {
+		return ports.TranslateResult{}, err
+	}
+	return ports.TranslateResult{Translation: httpclient.RestoreTokens(out[0], wrapperRE), Raw: out[0]}, nil
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	q := make([]string, len(segs))
+	for i, seg := range segs {
+		q[i] = protect(seg.Text)
+	}
+	out, err := c.translate(ctx, q, p)
+	if err != nil {
+		return ports.BatchResult{}, err
+	}
+	res := ports.BatchResult{Translations: make(map[string]string, len(segs))}
+	for i, seg := range segs {
+		res.Translations[seg.Key] = httpclient.RestoreTokens(out[i], wrapperRE)
+	}
+	return res, nil
+}
+
+func (c *Client) translate(ctx context.Context, q []string, p ports.TranslateParams) ([]string, error) {
+	body := map[string]any{
+		"q":      q,
+		"source": langCode(p.SourceLang, "auto"),
+		"target": langCode(p.TargetLang, ""),
+		"format": "html",
+	}
+	if c.APIKey != "" {
+		body["api_key"] = c.APIKey
+	}
+	var resp struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	rr, err := c.HTTP.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(c.Body(body, nil)).SetResult(&resp).Post(c.URL(defaultBaseURL, "/translate"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("libretranslate", "translate", rr)
+	}
+	if len(resp.TranslatedText) != len(q) {
+		return nil, fmt.Errorf("got %d translations for %d texts: %w", len(resp.TranslatedText), len(q), ports.ErrMalformedResponse)
+	}
+	return resp.TranslatedText, nil
+}
+
+// ListModels reports the engine as a single model; listing it checks the server.
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	var langs []struct {
+		Code string `json:"code"`
+	}
+	rr, err := c.HTTP.R().SetContext(ctx).SetResult(&langs).Get(c.URL(defaultBaseURL, "/languages"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("libretranslate", "list models", rr)
+	}
+	return []ports.ModelInfo{{Name: "default", Description: fmt.Sprintf("LibreTranslate (%d languages)", len(langs))}}, nil
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// langCode maps a locale to a LibreTranslate language code.
+func langCode(locale, empty string) string {
+	l := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	switch {
+	case l == "":
+		return empty
+	case l == "zh-tw" || l == "zh-hk" || strings.HasPrefix(l, "zh-hant"):
+		return "zt"
+	case l == "pt-br":
+		return "pb"
+	}
+	base, _, _ := strings.Cut(l, "-")
+	return base
+}
@@ -0,0 +1,97 @@
+package libretranslate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+// fakeEngine translates "Hi" to "Hallo" and, like real engines, pads and decorates the
+// elements it must leave alone.
+func fakeEngine(s string) string {
+	s = strings.ReplaceAll(s, "Hi", "Hallo")
+	return strings.ReplaceAll(s, `<span translate="no">`, `<span translate="no" class="notranslate"> `)
+}
+
+type request struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key"`
+}
+
+func serve(t *testing.T, got *[]request) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" {
+			t.Errorf("request to %s", r.URL.Path)
+		}
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		*got = append(*got, req)
+		out := make([]string, len(req.Q))
+		for i, q := range req.Q {
+			out[i] = fakeEngine(q)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"translatedText": out})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTranslateProtectsTokens(t *testing.T) {
+	var got []request
+	srv := serve(t, &got)
+	c := New(registry.Config{APIKey: "secret", BaseURL: srv.URL + "/"})
+	res, err := c.Translate(context.Background(), ports.Segment{Key: "k", Text: "Hi __PH_0__ & __TAG_0__you__TAG_1__ <3"}, ports.TranslateParams{SourceLang: "en_US", TargetLang: "pt-BR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hallo __PH_0__ & __TAG_0__you__TAG_1__ <3"; res.Translation != want {
+		t.Fatalf("got %q, want %q", res.Translation, want)
+	}
+	want := request{
+		Q:      []string{`Hi <span translate="no">__PH_0__</span> &amp; <span translate="no">__TAG_0__</span>you<span translate="no">__TAG_1__</span> &lt;3`},
+		Source: "en",
+		Target: "pb",
+		Format: "html",
+		APIKey: "secret",
+	}
+	if len(got) != 1 || got[0].Q[0] != want.Q[0] || got[0].Source != want.Source || got[0].Target != want.Target || got[0].Format != want.Format || got[0].APIKey != want.APIKey {
+		t.Fatalf("sent %+v, want %+v", got, want)
+	}
+}
+
+func TestTranslateBatch(t *testing.T) {
+	var got []request
+	srv := serve(t, &got)
+	c := New(registry.Config{BaseURL: srv.URL})
+	segs := []ports.Segment{{Key: "a", Text: "Hi"}, {Key: "b", Text: "Hi __PH_0__"}}
+	res, err := c.TranslateBatch(context.Background(), segs, ports.TranslateParams{TargetLang: "zh-Hant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Translations["a"] != "Hallo" || res.Translations["b"] != "Hallo __PH_0__" {
+		t.Fatalf("got %+v", res.Translations)
+	}
+	if len(got) != 1 || len(got[0].Q) != 2 || got[0].Source != "auto" || got[0].Target != "zt" || got[0].APIKey != "" {
+		t.Fatalf("sent %+v", got)
+	}
+}
+
+func TestPromptless(t *testing.T) {
+	var p ports.Provider = New(registry.Config{})
+	if pl, ok := p.(ports.PromptlessProvider); !ok || !pl.Promptless() {
+		t.Fatal("libretranslate does not report itself promptless")
+	}
+}
@@ -2,27 +2,143 @@ package ollama
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
 	"locail/internal/ports"
+	"strings"
 )
 
+const (
+	defaultBaseURL = "http://localhost:11434"
+	// defaultNumCtx is the context window Ollama loads a model with when num_ctx is unset.
+	defaultNumCtx = 2048
+)
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "ollama",
+		Label:          "Ollama",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL},
+			{Name: "model", Label: "Model", Kind: "text", Placeholder: "llama3.1", Help: "Default model; jobs may override it"},
+		},
+		Options: append([]registry.Field{
+			{Name: "num_ctx", Label: "Context window (num_ctx)", Kind: "number", Placeholder: "2048"},
+			{Name: "keep_alive", Label: "Keep alive", Kind: "text", Placeholder: "5m", Help: "How long the model stays loaded, e.g. 30m or -1m for forever"},
+		}, registry.SamplingOptions...),
+		Capabilities: registry.Capabilities{Prompts: true, Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Options configure the ollama provider type.
+type Options struct {
+	NumCtx    int    `json:"num_ctx"`
+	KeepAlive string `json:"keep_alive"`
+}
+
 type Client struct {
-	BaseURL string
-	Model   string
+	httpclient.Base
+	opts Options
 }
 
-func New(baseURL, model string) *Client {
-	return &Client{BaseURL: baseURL, Model: model}
+// New returns a client; invalid options JSON is ignored.
+func New(cfg registry.Config) *Client {
+	c := &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+	if strings.TrimSpace(cfg.Options) != "" {
+		_ = json.Unmarshal([]byte(cfg.Options), &c.opts)
+	}
+	return c
 }
 
 func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
-	return ports.TranslateResult{}, errors.New("ollama translate: not implemented yet")
+	return httpclient.TranslateJSON(ctx, c.chat, p)
 }
 
-func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
-	return nil, errors.New("ollama list models: not implemented yet")
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	return httpclient.TranslateBatchJSON(ctx, c.chat, segs, p)
 }
 
-func (c *Client) Test(ctx context.Context) error {
-	return errors.New("ollama test: not implemented yet")
+// chat runs a chat request in JSON mode and returns the raw message content. Ollama's JSON
+// mode takes no schema, so the keys are left to the prompt.
+func (c *Client) chat(ctx context.Context, p ports.TranslateParams, _ string, _ []string) (string, ports.Usage, error) {
+	type ollamaMsg struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type ollamaOptions struct {
+		Temperature float64  `json:"temperature"`
+		TopP        *float64 `json:"top_p,omitempty"`
+		NumPredict  int      `json:"num_predict,omitempty"`
+		Seed        *int64   `json:"seed,omitempty"`
+		NumCtx      int      `json:"num_ctx,omitempty"`
+	}
+	type ollamaBody struct {
+		Model     string        `json:"model"`
+		Messages  []ollamaMsg   `json:"messages"`
+		Stream    bool          `json:"stream"`
+		Format    string        `json:"format"`
+		Options   ollamaOptions `json:"options"`
+		KeepAlive string        `json:"keep_alive,omitempty"`
+	}
+	body := ollamaBody{
+		Model:    c.ModelFor(p),
+		Messages: []ollamaMsg{{Role: "system", Content: p.SystemPrompt}, {Role: "user", Content: p.UserPrompt}},
+		Stream:   false,
+		Format:   "json",
+		Options: ollamaOptions{
+			Temperature: c.Temperature(p),
+			TopP:        c.Opts.TopP,
+			NumPredict:  c.Opts.MaxTokens,
+			Seed:        c.Opts.Seed,
+			NumCtx:      c.opts.NumCtx,
+		},
+		KeepAlive: c.opts.KeepAlive,
+	}
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	r := c.HTTP.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(c.Body(body, nil)).SetResult(&resp)
+	rr, err := r.Post(c.URL(defaultBaseURL, "/api/chat"))
+	if err != nil {
+		return "", ports.Usage{}, err
+	}
+	if rr.IsError() {
+		return "", ports.Usage{}, httpclient.HTTPError("ollama", "translate", rr)
+	}
+	usage := ports.Usage{InputTokens: resp.PromptEvalCount, OutputTokens: resp.EvalCount}
+	return strings.TrimSpace(resp.Message.Content), usage, nil
 }
+
+// ListModels lists the installed models with the context window requests run with: num_ctx,
+// else Ollama's default.
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	r, err := c.HTTP.R().SetContext(ctx).SetResult(&resp).Get(c.URL(defaultBaseURL, "/api/tags"))
+	if err != nil {
+		return nil, err
+	}
+	if r.IsError() {
+		return nil, httpclient.HTTPError("ollama", "list models", r)
+	}
+	numCtx := c.opts.NumCtx
+	if numCtx <= 0 {
+		numCtx = defaultNumCtx
+	}
+	out := make([]ports.ModelInfo, 0, len(resp.Models))
+	for _, m := range resp.Models {
+		out = append(out, ports.ModelInfo{Name: m.Name, ContextTokens: numCtx})
+	}
+	return out, nil
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
@@ -0,0 +1,55 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+func TestContextWindow(t *testing.T) {
+	var numCtx int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models":[{"name":"llama3.1"}]}`))
+		case "/api/chat":
+			var body struct {
+				Options struct {
+					NumCtx int `json:"num_ctx"`
+				} `json:"options"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			numCtx = body.Options.NumCtx
+			w.Write([]byte(`{"message":{"content":"{\"translation\":\"Hallo\"}"},"prompt_eval_count":9,"eval_count":3}`))
+		}
+	}))
+	defer srv.Close()
+	tests := []struct {
+		options string
+		listed  int
+		sent    int
+	}{
+		{"", defaultNumCtx, 0},
+		{`{"num_ctx":16384}`, 16384, 16384},
+	}
+	for _, tt := range tests {
+		c := New(registry.Config{BaseURL: srv.URL, Model: "llama3.1", Options: tt.options})
+		models, err := c.ListModels(context.Background())
+		if err != nil || len(models) != 1 || models[0].ContextTokens != tt.listed {
+			t.Fatalf("%s: got %+v, %v; want context %d", tt.options, models, err, tt.listed)
+		}
+		res, err := c.Translate(context.Background(), ports.Segment{Key: "k"}, ports.TranslateParams{UserPrompt: "Hi"})
+		if err != nil || res.Translation != "Hallo" || res.Usage != (ports.Usage{InputTokens: 9, OutputTokens: 3}) {
+			t.Fatalf("%s: got %+v, %v", tt.options, res, err)
+		}
+		if numCtx != tt.sent {
+			t.Fatalf("%s: sent num_ctx %d, want %d", tt.options, numCtx, tt.sent)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package openai talks to any OpenAI-compatible server (OpenAI, LM Studio, vLLM, llama.cpp
+// server, LocalAI).
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+	"strings"
+)
+
+const defaultBaseURL = "https://api.openai.com"
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "openai",
+		Label:          "OpenAI-compatible",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL, Help: "Any OpenAI-compatible server, e.g. http://localhost:1234 for LM Studio"},
+			{Name: "api_key", Label: "API key", Kind: "secret", Help: "Optional for local servers"},
+			{Name: "model", Label: "Model", Kind: "text", Help: "Default model; jobs may override it"},
+		},
+		Options: append([]registry.Field{
+			{Name: "response_format", Label: "Response format", Kind: "select", Choices: []string{"auto", "json_schema", "json_object", "none"}, Help: "auto detects what the server accepts"},
+			{Name: "auth_header", Label: "Auth header", Kind: "text", Placeholder: "Authorization"},
+			{Name: "auth_scheme", Label: "Auth scheme", Kind: "text", Placeholder: "Bearer"},
+			{Name: "path_prefix", Label: "Path prefix", Kind: "text", Placeholder: "/v1"},
+			{Name: "headers", Label: "Extra headers", Kind: "object"},
+		}, registry.SamplingOptions...),
+		Capabilities: registry.Capabilities{Prompts: true, JSONSchema: true, Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Options configure the openai provider type.
+type Options struct {
+	// AuthHeader carries the API key; defaults to Authorization.
+	AuthHeader string `json:"auth_header"`
+	// AuthScheme prefixes the key; defaults to Bearer for the Authorization header. An empty
+	// string sends the bare key.
+	AuthScheme *string `json:"auth_scheme"`
+	// Headers are sent with every request.
+	Headers map[string]string `json:"headers"`
+	// PathPrefix is inserted between the base URL and /chat/completions; defaults to /v1.
+	PathPrefix *string `json:"path_prefix"`
+	// ResponseFormat is auto, json_schema, json_object or none.
+	ResponseFormat string `json:"response_format"`
+}
+
+type Client struct {
+	httpclient.Base
+	opts Options
+}
+
+// New returns a client; invalid options JSON is ignored.
+func New(cfg registry.Config) *Client {
+	c := &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+	if strings.TrimSpace(cfg.Options) != "" {
+		_ = json.Unmarshal([]byte(cfg.Options), &c.opts)
+	}
+	return c
+}
+
+func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	return httpclient.TranslateJSON(ctx, c.chat, p)
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	return httpclient.TranslateBatchJSON(ctx, c.chat, segs, p)
+}
+
+func (c *Client) chat(ctx context.Context, p ports.TranslateParams, schemaName string, keys []string) (string, ports.Usage, error) {
+	return c.ChatCompletions(ctx, httpclient.ChatEndpoint{
+		Provider: "openai",
+		URL:      c.url("/chat/completions"),
+		Headers:  c.headers(),
+		Format:   c.opts.ResponseFormat,
+	}, p, schemaName, keys)
+}
+
+func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
+	// servers report the context window under different names
+	var resp struct {
+		Data []struct {
+			ID            string `json:"id"`
+			ContextLength int    `json:"context_length"`
+			MaxModelLen   int    `json:"max_model_len"`
+			Meta          struct {
+				NCtxTrain int `json:"n_ctx_train"`
+			} `json:"meta"`
+		} `json:"data"`
+	}
+	rr, err := c.HTTP.R().SetContext(ctx).SetHeaders(c.headers()).SetResult(&resp).Get(c.url("/models"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("openai", "list models", rr)
+	}
+	out := make([]ports.ModelInfo, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		ctxTokens := d.ContextLength
+		if ctxTokens == 0 {
+			ctxTokens = d.MaxModelLen
+		}
+		if ctxTokens == 0 {
+			ctxTokens = d.Meta.NCtxTrain
+		}
+		out = append(out, ports.ModelInfo{Name: d.ID, Description: d.ID, ContextTokens: ctxTokens})
+	}
+	return out, nil
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// url joins the base URL, the path prefix and tail, not repeating a prefix the base URL
+// already ends with.
+func (c *Client) url(tail string) string {
+	base := c.URL(defaultBaseURL, "")
+	prefix := "/v1"
+	if c.opts.PathPrefix != nil {
+		prefix = strings.TrimRight(*c.opts.PathPrefix, "/")
+		if prefix != "" && !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+	}
+	if prefix != "" && !strings.HasSuffix(base, prefix) {
+		base += prefix
+	}
+	return base + tail
+}
+
+func (c *Client) headers() map[string]string {
+	h := map[string]string{}
+	if c.APIKey != "" {
+		name := c.opts.AuthHeader
+		if name == "" {
+			name = "Authorization"
+		}
+		scheme := ""
+		if c.opts.AuthScheme != nil {
+			scheme = *c.opts.AuthScheme
+		} else if strings.EqualFold(name, "Authorization") {
+			scheme = "Bearer"
+		}
+		h[name] = strings.TrimSpace(scheme + " " + c.APIKey)
+	}
+	for k, v := range c.opts.Headers {
+		h[k] = v
+	}
+	return h
+}
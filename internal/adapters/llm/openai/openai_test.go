@@ -0,0 +1,210 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"locail/internal/adapters/llm/registry"
+	"locail/internal/ports"
+)
+
+// chatServer is an OpenAI-compatible server accepting only some response formats; "none"
+// stands for a request without response_format.
+type chatServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	formats []string
+	headers []http.Header
+	paths   []string
+}
+
+func newChatServer(t *testing.T, accept ...string) *chatServer {
+	t.Helper()
+	s := &chatServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseFormat *struct {
+				Type       string `json:"type"`
+				JSONSchema struct {
+					Name   string `json:"name"`
+					Strict bool   `json:"strict"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		format := "none"
+		if body.ResponseFormat != nil {
+			format = body.ResponseFormat.Type
+			if format == "json_schema" && (body.ResponseFormat.JSONSchema.Name != "translation" || !body.ResponseFormat.JSONSchema.Strict) {
+				t.Errorf("json_schema %+v", body.ResponseFormat.JSONSchema)
+			}
+		}
+		s.mu.Lock()
+		s.formats = append(s.formats, format)
+		s.headers = append(s.headers, r.Header.Clone())
+		s.paths = append(s.paths, r.URL.Path)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if !slices.Contains(accept, format) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"response_format not supported"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"translation\":\"Hallo\"}"}}],"usage":{"prompt_tokens":21,"completion_tokens":4}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func translate(t *testing.T, c *Client) (ports.TranslateResult, error) {
+	t.Helper()
+	return c.Translate(context.Background(), ports.Segment{Key: "k", Text: "Hi"}, ports.TranslateParams{SystemPrompt: "sys", UserPrompt: "Hi"})
+}
+
+func TestResponseFormatFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  string
+		accept  []string
+		sent    []string
+		thenErr bool
+	}{
+		{name: "json_schema", accept: []string{"json_schema", "json_object", "none"}, sent: []string{"json_schema"}},
+		{name: "json_object", accept: []string{"json_object", "none"}, sent: []string{"json_schema", "json_object"}},
+		{name: "none", accept: []string{"none"}, sent: []string{"json_schema", "json_object", "none"}},
+		{name: "fixed format is not retried", option: "json_schema", accept: []string{"json_object"}, sent: []string{"json_schema"}, thenErr: true},
+		{name: "fixed none", option: "none", accept: []string{"none"}, sent: []string{"none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newChatServer(t, tt.accept...)
+			opts := ""
+			if tt.option != "" {
+				opts = `{"response_format":"` + tt.option + `"}`
+			}
+			c := New(registry.Config{BaseURL: srv.URL, Model: "m", Options: opts})
+			res, err := translate(t, c)
+			if tt.thenErr {
+				var pe *ports.ProviderError
+				if !errors.As(err, &pe) || pe.StatusCode != http.StatusBadRequest {
+					t.Fatalf("got %v, want the 400", err)
+				}
+			} else if err != nil || res.Translation != "Hallo" {
+				t.Fatalf("got %q, %v", res.Translation, err)
+			}
+			if !slices.Equal(srv.formats, tt.sent) {
+				t.Fatalf("sent %v, want %v", srv.formats, tt.sent)
+			}
+			if tt.thenErr {
+				return
+			}
+			// the detected format is reused
+			if _, err := translate(t, c); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := srv.formats[len(srv.formats)-1], tt.sent[len(tt.sent)-1]; len(srv.formats) != len(tt.sent)+1 || got != want {
+				t.Fatalf("second request sent %v, want only %s", srv.formats[len(tt.sent):], want)
+			}
+		})
+	}
+}
+
+func TestUsageAndErrors(t *testing.T) {
+	srv := newChatServer(t, "json_schema")
+	c := New(registry.Config{BaseURL: srv.URL, Model: "m"})
+	res, err := translate(t, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Usage != (ports.Usage{InputTokens: 21, OutputTokens: 4}) {
+		t.Fatalf("usage %+v", res.Usage)
+	}
+
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusUnauthorized, false},
+	}
+	for _, tt := range tests {
+		var calls int
+		errSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(tt.status)
+		}))
+		_, err := translate(t, New(registry.Config{BaseURL: errSrv.URL, Model: "m"}))
+		errSrv.Close()
+		var pe *ports.ProviderError
+		if !errors.As(err, &pe) || pe.StatusCode != tt.status || pe.Retryable() != tt.retryable {
+			t.Errorf("status %d: got %v", tt.status, err)
+		}
+		if calls != 1 {
+			t.Errorf("status %d: %d requests, want no format fallback", tt.status, calls)
+		}
+	}
+}
+
+func TestHeadersAndPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		base    string
+		options string
+		path    string
+		header  string
+		value   string
+	}{
+		{"bearer", "sk", "", "", "/v1/chat/completions", "Authorization", "Bearer sk"},
+		{"base with prefix", "sk", "/v1/", "", "/v1/chat/completions", "Authorization", "Bearer sk"},
+		{"custom header", "sk", "", `{"auth_header":"api-key"}`, "/v1/chat/completions", "Api-Key", "sk"},
+		{"custom scheme", "sk", "", `{"auth_scheme":"Token"}`, "/v1/chat/completions", "Authorization", "Token sk"},
+		{"no prefix", "", "", `{"path_prefix":"","headers":{"X-Team":"loc"}}`, "/chat/completions", "X-Team", "loc"},
+		{"other prefix", "", "", `{"path_prefix":"openai/v1"}`, "/openai/v1/chat/completions", "Authorization", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newChatServer(t, "json_schema")
+			c := New(registry.Config{APIKey: tt.key, BaseURL: srv.URL + tt.base, Model: "m", Options: tt.options})
+			if _, err := translate(t, c); err != nil {
+				t.Fatal(err)
+			}
+			if srv.paths[0] != tt.path {
+				t.Errorf("path %s, want %s", srv.paths[0], tt.path)
+			}
+			if got := srv.headers[0].Get(tt.header); got != tt.value {
+				t.Errorf("%s: %q, want %q", tt.header, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestListModelsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"id":"a","context_length":8192},{"id":"b","max_model_len":4096},{"id":"c","meta":{"n_ctx_train":2048}},{"id":"d"}]}`))
+	}))
+	defer srv.Close()
+	models, err := New(registry.Config{BaseURL: srv.URL}).ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"a": 8192, "b": 4096, "c": 2048, "d": 0}
+	if len(models) != len(want) {
+		t.Fatalf("got %+v", models)
+	}
+	for _, m := range models {
+		if m.ContextTokens != want[m.Name] {
+			t.Errorf("%s: context %d, want %d", m.Name, m.ContextTokens, want[m.Name])
+		}
+	}
+}
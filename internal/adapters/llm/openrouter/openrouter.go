@@ -2,28 +2,149 @@ package openrouter
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
 	"locail/internal/ports"
+	"strconv"
+	"strings"
 )
 
+const defaultBaseURL = "https://openrouter.ai"
+
+func init() {
+	registry.Register(registry.Descriptor{
+		Type:           "openrouter",
+		Label:          "OpenRouter",
+		DefaultBaseURL: defaultBaseURL,
+		Fields: []registry.Field{
+			{Name: "base_url", Label: "Base URL", Kind: "text", Placeholder: defaultBaseURL},
+			{Name: "api_key", Label: "API key", Kind: "secret", Required: true},
+			{Name: "model", Label: "Model", Kind: "text", Placeholder: "openai/gpt-4o-mini", Help: "Default model; jobs may override it"},
+		},
+		Options: append([]registry.Field{
+			{Name: "provider", Label: "Provider routing", Kind: "json", Help: `e.g. {"order": ["groq"], "allow_fallbacks": false}`},
+			{Name: "transforms", Label: "Transforms", Kind: "list", Placeholder: "middle-out"},
+		}, registry.SamplingOptions...),
+		Capabilities: registry.Capabilities{Prompts: true, JSONSchema: true, Batch: true, ListModels: true},
+	}, func(cfg registry.Config) ports.Provider { return New(cfg) })
+}
+
+// Options configure the openrouter provider type.
+type Options struct {
+	// Provider is OpenRouter's provider routing object (order, only, ignore, allow_fallbacks,
+	// ...), passed through as is; pinning a provider keeps some models consistent.
+	Provider map[string]any `json:"provider"`
+	// Transforms are OpenRouter prompt transforms such as "middle-out".
+	Transforms []string `json:"transforms"`
+}
+
 type Client struct {
-	APIKey  string
-	BaseURL string
-	Model   string
+	httpclient.Base
+	opts Options
 }
 
-func New(apiKey, baseURL, model string) *Client {
-	return &Client{APIKey: apiKey, BaseURL: baseURL, Model: model}
+// New returns a client; invalid options JSON is ignored.
+func New(cfg registry.Config) *Client {
+	c := &Client{Base: httpclient.NewBase(cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.Options)}
+	if strings.TrimSpace(cfg.Options) != "" {
+		_ = json.Unmarshal([]byte(cfg.Options), &c.opts)
+	}
+	return c
 }
 
 func (c *Client) Translate(ctx context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
-	return ports.TranslateResult{}, errors.New("openrouter translate: not implemented yet")
+	return httpclient.TranslateJSON(ctx, c.chat, p)
+}
+
+func (c *Client) TranslateBatch(ctx context.Context, segs []ports.Segment, p ports.TranslateParams) (ports.BatchResult, error) {
+	return httpclient.TranslateBatchJSON(ctx, c.chat, segs, p)
+}
+
+// chat runs a chat completion whose answer must be a JSON object with the given string fields.
+func (c *Client) chat(ctx context.Context, p ports.TranslateParams, schemaName string, keys []string) (string, ports.Usage, error) {
+	return c.ChatCompletions(ctx, httpclient.ChatEndpoint{
+		Provider: "openrouter",
+		URL:      c.url("/chat/completions"),
+		Headers: map[string]string{
+			"Authorization": "Bearer " + c.APIKey,
+			"HTTP-Referer":  "https://locail.app",
+			"X-Title":       "locail",
+		},
+		Extra: c.extra(),
+	}, p, schemaName, keys)
+}
+
+// extra returns the routing fields of a request body.
+func (c *Client) extra() map[string]any {
+	extra := map[string]any{}
+	if len(c.opts.Provider) > 0 {
+		extra["provider"] = c.opts.Provider
+	}
+	if len(c.opts.Transforms) > 0 {
+		extra["transforms"] = c.opts.Transforms
+	}
+	return extra
 }
 
 func (c *Client) ListModels(ctx context.Context) ([]ports.ModelInfo, error) {
-	return nil, errors.New("openrouter list models: not implemented yet")
+	var resp struct {
+		Data []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			ContextLength int    `json:"context_length"`
+			// Pricing holds USD per token as decimal strings.
+			Pricing struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+		} `json:"data"`
+	}
+	r := c.HTTP.R().SetContext(ctx).
+		SetHeader("Authorization", "Bearer "+c.APIKey).
+		SetResult(&resp)
+	rr, err := r.Get(c.url("/models"))
+	if err != nil {
+		return nil, err
+	}
+	if rr.IsError() {
+		return nil, httpclient.HTTPError("openrouter", "list models", rr)
+	}
+	out := make([]ports.ModelInfo, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		label := d.Name
+		if label == "" {
+			label = d.ID
+		}
+		out = append(out, ports.ModelInfo{
+			Name:          d.ID,
+			Description:   label,
+			ContextTokens: d.ContextLength,
+			InputPrice:    perMillion(d.Pricing.Prompt),
+			OutputPrice:   perMillion(d.Pricing.Completion),
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) Test(ctx context.Context) error { _, err := c.ListModels(ctx); return err }
+
+// perMillion converts a per-token price string to USD per million tokens; unknown or negative
+// (variable) prices are zero.
+func perMillion(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v * 1e6
 }
 
-func (c *Client) Test(ctx context.Context) error {
-	return errors.New("openrouter test: not implemented yet")
+// url builds a URL for OpenRouter whether the base URL contains /api/v1 or not.
+func (c *Client) url(tail string) string {
+	b := c.URL(defaultBaseURL, "")
+	// If base already ends with /api/v1 or /api/v1/..., don't duplicate
+	if idx := strings.Index(b, "/api/v1"); idx >= 0 {
+		return b[:idx+len("/api/v1")] + tail
+	}
+	return b + "/api/v1" + tail
 }
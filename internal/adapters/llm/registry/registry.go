@@ -1,53 +1,228 @@
 package registry
 
 import (
-	"context"
-	"errors"
+	"encoding/json"
+	"fmt"
 	"locail/internal/ports"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 )
 
-// Registry holds named Provider implementations.
+// Config is the stored configuration a provider is built from.
+type Config struct {
+	APIKey  string
+	BaseURL string
+	Model   string
+	// Options is the provider's options_json.
+	Options string
+}
+
+// Constructor builds a provider from its configuration.
+type Constructor func(Config) ports.Provider
+
+// Field describes an input of the provider form. Kind is text, secret, number, bool, select,
+// list (an array of strings), object (an object of strings) or json (any JSON object).
+type Field struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Kind        string   `json:"kind"`
+	Required    bool     `json:"required,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Help        string   `json:"help,omitempty"`
+}
+
+// Capabilities tell callers what a provider type supports.
+type Capabilities struct {
+	// Prompts is false for machine translation engines that ignore prompt templates.
+	Prompts bool `json:"prompts"`
+	// JSONSchema means answers are constrained by a schema rather than only asked for as JSON.
+	JSONSchema bool `json:"json_schema"`
+	Batch      bool `json:"batch"`
+	ListModels bool `json:"list_models"`
+}
+
+// Descriptor describes a provider type. Fields are the columns of the provider record
+// (base_url, api_key, model) the type uses; Options are keys of its options_json.
+type Descriptor struct {
+	Type           string       `json:"type"`
+	Label          string       `json:"label"`
+	DefaultBaseURL string       `json:"default_base_url"`
+	Fields         []Field      `json:"fields"`
+	Options        []Field      `json:"options"`
+	Capabilities   Capabilities `json:"capabilities"`
+}
+
+// Field returns the field of the given name.
+func (d Descriptor) Field(name string) (Field, bool) {
+	for _, f := range d.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// SamplingOptions are the generation settings of LLM provider types.
+var SamplingOptions = []Field{
+	{Name: "temperature", Label: "Temperature", Kind: "number", Placeholder: "0"},
+	{Name: "top_p", Label: "Top P", Kind: "number"},
+	{Name: "max_tokens", Label: "Max tokens", Kind: "number"},
+	{Name: "seed", Label: "Seed", Kind: "number"},
+}
+
+// commonOptions apply to every provider type.
+var commonOptions = []Field{
+	{Name: "timeout_seconds", Label: "Request timeout (s)", Kind: "number", Placeholder: "30", Help: "Big local models may need 300"},
+	{Name: "requests_per_minute", Label: "Requests per minute", Kind: "number", Help: "0 = unlimited"},
+	{Name: "tokens_per_minute", Label: "Tokens per minute", Kind: "number", Help: "0 = unlimited"},
+	{Name: "extra_body", Label: "Extra body", Kind: "json", Help: "Merged into every request body"},
+	{Name: "input_price", Label: "Input price", Kind: "number", Help: "USD per million input tokens; overrides listed prices"},
+	{Name: "output_price", Label: "Output price", Kind: "number", Help: "USD per million output tokens; overrides listed prices"},
+	{Name: "proxy_url", Label: "Proxy URL", Kind: "text", Placeholder: "http://proxy:3128", Help: "http, https, socks5 or socks5h; \"direct\" ignores the global proxy"},
+	{Name: "no_proxy", Label: "No proxy", Kind: "list", Help: "Hosts, domains and CIDRs reached without the proxy"},
+	{Name: "ca_file", Label: "CA bundle", Kind: "text", Placeholder: "/path/to/ca.pem", Help: "PEM certificates trusted besides the system roots"},
+	{Name: "client_cert_file", Label: "Client certificate", Kind: "text", Placeholder: "/path/to/cert.pem"},
+	{Name: "client_key_file", Label: "Client key", Kind: "text", Placeholder: "/path/to/key.pem"},
+	{Name: "insecure_skip_verify", Label: "Skip TLS verification", Kind: "bool", Help: "Insecure: API keys and texts can be intercepted"},
+}
+
+// Registry holds the registered provider types.
 type Registry struct {
-	mu        sync.RWMutex
-	providers map[string]ports.Provider
+	mu    sync.RWMutex
+	types []entry
 }
 
-func New() *Registry {
-	return &Registry{providers: make(map[string]ports.Provider)}
+type entry struct {
+	desc Descriptor
+	new  Constructor
 }
 
-func (r *Registry) Register(name string, p ports.Provider) {
+func New() *Registry { return &Registry{} }
+
+// Default is the registry provider packages register with from their init functions.
+var Default = New()
+
+// Register adds a provider type, replacing one of the same name.
+func Register(d Descriptor, c Constructor) { Default.Register(d, c) }
+
+// Build returns a provider of the given type.
+func Build(typ string, cfg Config) (ports.Provider, bool) { return Default.Build(typ, cfg) }
+
+// Lookup returns the descriptor of a provider type.
+func Lookup(typ string) (Descriptor, bool) { return Default.Lookup(typ) }
+
+// Descriptors lists the registered provider types by label.
+func Descriptors() []Descriptor { return Default.Descriptors() }
+
+func (r *Registry) Register(d Descriptor, c Constructor) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.providers[name] = p
+	d.Type = strings.ToLower(d.Type)
+	d.Options = append(slices.Clone(d.Options), commonOptions...)
+	for i, e := range r.types {
+		if e.desc.Type == d.Type {
+			r.types[i] = entry{d, c}
+			return
+		}
+	}
+	r.types = append(r.types, entry{d, c})
 }
 
-func (r *Registry) Get(name string) (ports.Provider, bool) {
+func (r *Registry) Lookup(typ string) (Descriptor, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	p, ok := r.providers[name]
-	return p, ok
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	for _, e := range r.types {
+		if e.desc.Type == typ {
+			return e.desc, true
+		}
+	}
+	return Descriptor{}, false
 }
 
-func (r *Registry) MustGet(name string) ports.Provider {
-	if p, ok := r.Get(name); ok {
-		return p
+func (r *Registry) Build(typ string, cfg Config) (ports.Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	for _, e := range r.types {
+		if e.desc.Type == typ {
+			return e.new(cfg), true
+		}
 	}
-	panic("provider not registered: " + name)
+	return nil, false
 }
 
-// HealthCheck tests all providers (used by Settings UI).
-func (r *Registry) HealthCheck(ctx context.Context) map[string]error {
+func (r *Registry) Descriptors() []Descriptor {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	out := make(map[string]error, len(r.providers))
-	for name, p := range r.providers {
-		if p == nil {
-			out[name] = errors.New("nil provider")
+	out := make([]Descriptor, 0, len(r.types))
+	for _, e := range r.types {
+		out = append(out, e.desc)
+	}
+	slices.SortFunc(out, func(a, b Descriptor) int { return strings.Compare(a.Label, b.Label) })
+	return out
+}
+
+// ValidateOptions checks that raw options are a JSON object whose keys the descriptor lists,
+// with values of the right kind.
+func ValidateOptions(d Descriptor, raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var opts map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &opts); err != nil {
+		return fmt.Errorf("invalid options_json: %w", err)
+	}
+	for _, k := range slices.Sorted(maps.Keys(opts)) {
+		if !slices.ContainsFunc(d.Options, func(f Field) bool { return f.Name == k }) {
+			return fmt.Errorf("unknown option %s", k)
+		}
+	}
+	for _, f := range d.Options {
+		v, ok := opts[f.Name]
+		if !ok || string(v) == "null" {
 			continue
 		}
-		out[name] = p.Test(ctx)
+		switch f.Kind {
+		case "number":
+			var n float64
+			if err := json.Unmarshal(v, &n); err != nil || n < 0 {
+				return fmt.Errorf("%s must be a non-negative number", f.Name)
+			}
+		case "bool":
+			var b bool
+			if err := json.Unmarshal(v, &b); err != nil {
+				return fmt.Errorf("%s must be true or false", f.Name)
+			}
+		case "select":
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil || (s != "" && !slices.Contains(f.Choices, s)) {
+				return fmt.Errorf("%s must be one of %s", f.Name, strings.Join(f.Choices, ", "))
+			}
+		case "list":
+			var l []string
+			if err := json.Unmarshal(v, &l); err != nil {
+				return fmt.Errorf("%s must be a list of strings", f.Name)
+			}
+		case "object":
+			var m map[string]string
+			if err := json.Unmarshal(v, &m); err != nil {
+				return fmt.Errorf("%s must be an object of strings", f.Name)
+			}
+		case "json":
+			var m map[string]any
+			if err := json.Unmarshal(v, &m); err != nil {
+				return fmt.Errorf("%s must be a JSON object", f.Name)
+			}
+		default:
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return fmt.Errorf("%s must be a string", f.Name)
+			}
+		}
 	}
-	return out
+	return nil
 }
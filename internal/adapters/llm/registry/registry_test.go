@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"locail/internal/ports"
+)
+
+func TestValidateOptions(t *testing.T) {
+	r := New()
+	r.Register(Descriptor{
+		Type: "Fake",
+		Options: []Field{
+			{Name: "mode", Kind: "select", Choices: []string{"a", "b"}},
+			{Name: "label", Kind: "text"},
+			{Name: "headers", Kind: "object"},
+		},
+	}, func(Config) ports.Provider { return nil })
+	d, ok := r.Lookup(" fake ")
+	if !ok {
+		t.Fatal("type not registered case-insensitively")
+	}
+	tests := []struct {
+		raw string
+		err string
+	}{
+		{"", ""},
+		{`{}`, ""},
+		{`{"mode":"b","label":"x","headers":{"X":"y"},"timeout_seconds":null}`, ""},
+		{`{"mode":"","requests_per_minute":30,"no_proxy":["*.local"],"insecure_skip_verify":true,"extra_body":{"a":[1]}}`, ""},
+		{`[1]`, "invalid options_json"},
+		{`{"mode":"c"}`, "mode must be one of a, b"},
+		{`{"label":3}`, "label must be a string"},
+		{`{"headers":{"X":1}}`, "headers must be an object of strings"},
+		{`{"requests_per_minute":-1}`, "requests_per_minute must be a non-negative number"},
+		{`{"timeout_seconds":"30"}`, "timeout_seconds must be a non-negative number"},
+		{`{"insecure_skip_verify":"yes"}`, "insecure_skip_verify must be true or false"},
+		{`{"no_proxy":"a,b"}`, "no_proxy must be a list of strings"},
+		{`{"extra_body":[]}`, "extra_body must be a JSON object"},
+		{`{"mode":"a","sede":1}`, "unknown option sede"},
+	}
+	for _, tt := range tests {
+		err := ValidateOptions(d, tt.raw)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.raw, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: got %v, want %q", tt.raw, err, tt.err)
+		}
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	r := New()
+	r.Register(Descriptor{Type: "x", Label: "Old"}, func(Config) ports.Provider { return nil })
+	r.Register(Descriptor{Type: "X", Label: "New"}, func(Config) ports.Provider { return nil })
+	ds := r.Descriptors()
+	if len(ds) != 1 || ds[0].Label != "New" || len(ds[0].Options) != len(commonOptions) {
+		t.Fatalf("got %+v", ds)
+	}
+	if _, ok := r.Build("y", Config{}); ok {
+		t.Fatal("built an unregistered type")
+	}
+}
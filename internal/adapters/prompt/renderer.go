@@ -3,24 +3,50 @@ package prompt
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"locail/internal/domain"
 	"locail/internal/ports"
+	"strings"
 	"text/template"
 )
 
+// Funcs are the helper functions available to prompt templates.
+var Funcs = template.FuncMap{"join": strings.Join, "json": toJSON}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
 type Renderer struct {
 	Templates ports.TemplateRepository
 }
 
 func New(templates ports.TemplateRepository) *Renderer { return &Renderer{Templates: templates} }
 
-func (r *Renderer) Render(ctx context.Context, scope string, refID *int64, typ, role string, data ports.PromptData) (string, error) {
-	// Load effective template from repository; if none, fallback to builtins.
-	t, _ := r.Templates.GetEffective(ctx, scope, refID, typ, role)
-	body := builtinTemplate(typ, role)
-	if t != nil && t.Body != "" {
-		body = t.Body
+func (r *Renderer) Render(ctx context.Context, scope ports.TemplateScope, typ, role, override string, data ports.PromptData) (ports.RenderedPrompt, error) {
+	out := ports.RenderedPrompt{Source: "override"}
+	body := override
+	if body == "" {
+		// Load effective template from repository; if none, fallback to builtins.
+		out.Source = "builtin"
+		body = builtinTemplate(typ, role)
+		if t, _ := r.Templates.GetEffective(ctx, scope, typ, role); t != nil && t.Body != "" {
+			body, out.Source, out.TemplateID = t.Body, t.Scope, t.ID
+		}
 	}
-	tpl, err := template.New("prompt").Parse(body)
+	text, err := Execute(body, data)
+	if err != nil {
+		return ports.RenderedPrompt{}, err
+	}
+	out.Text = text
+	return out, nil
+}
+
+// Execute renders a template body against data.
+func Execute(body string, data ports.PromptData) (string, error) {
+	tpl, err := template.New("prompt").Funcs(Funcs).Parse(body)
 	if err != nil {
 		return "", err
 	}
@@ -31,12 +57,84 @@ func (r *Renderer) Render(ctx context.Context, scope string, refID *int64, typ,
 	return buf.String(), nil
 }
 
+// Validate parses body and executes it against sample data so that syntax errors and
+// references to unknown fields are reported when a template is saved, not when a job runs.
+func Validate(body string) error {
+	sample := ports.PromptData{
+		Project:      "project",
+		FilePath:     "file.txt",
+		Key:          "key",
+		Text:         "text",
+		SrcLang:      "en",
+		TgtLang:      "de",
+		Placeholders: []string{"{0}"},
+		Tags:         []string{"<b>"},
+		Neighbors:    []ports.NeighborUnit{{Key: "key", Text: "text"}},
+		Glossary:     []ports.GlossaryEntry{{Term: "term", Translations: []string{"t"}, Forbidden: []string{"f"}}},
+		Examples:     []ports.Example{{Source: "s", Translation: "t", Score: 100}},
+		Units:        []ports.PromptUnit{{Key: "key", Text: "text"}},
+	}
+	_, err := Execute(body, sample)
+	return err
+}
+
+// Builtin returns the compiled-in default body for a template type and role.
+func Builtin(typ, role string) string { return builtinTemplate(typ, role) }
+
+// builtinKeys are the (type, role) pairs with a builtin body.
+var builtinKeys = [][2]string{
+	{"translate_single", "system"},
+	{"translate_single", "user"},
+	{"translate_file", "system"},
+	{"translate_file", "user"},
+	{"detect_language", "system"},
+	{"detect_language", "user"},
+}
+
+// SeedDefaults stores the builtin bodies as global templates. Global templates that were
+// edited (no longer default) are kept; outdated defaults are updated with their history.
+func SeedDefaults(ctx context.Context, repo ports.TemplateRepository) error {
+	for _, k := range builtinKeys {
+		body := builtinTemplate(k[0], k[1])
+		t, err := repo.GetEffective(ctx, ports.TemplateScope{}, k[0], k[1])
+		if err != nil {
+			return err
+		}
+		if t != nil && (!t.IsDefault || t.Body == body) {
+			continue
+		}
+		if err := repo.Upsert(ctx, &domain.Template{Scope: "global", Type: k[0], Role: k[1], Body: body, IsDefault: true}); err != nil {
+			return fmt.Errorf("seed %s/%s template: %w", k[0], k[1], err)
+		}
+	}
+	return nil
+}
+
 func builtinTemplate(typ, role string) string {
 	if typ == "translate_single" && role == "system" {
-		return "You are a professional localization translator. Translate from {{.SrcLang}} to {{.TgtLang}}. Preserve placeholders exactly (e.g., {{.Placeholders}}) and Valve tags like <sfx>, <clr:...>. Do not change whitespace or punctuation. Return only JSON: {\"translation\":\"...\"}."
+		return "You are a professional localization translator. Translate from {{.SrcLang}} to {{.TgtLang}}. Preserve placeholders exactly (e.g., {{.Placeholders}}) and Valve tags like <sfx>, <clr:...>. " +
+			"Do not change whitespace or punctuation{{if .Punctuation}} except to follow these punctuation conventions: {{.Punctuation}}{{end}}." +
+			"{{if .Formality}}\nFormality: {{.Formality}}.{{end}}{{if .Tone}}\nTone: {{.Tone}}.{{end}}{{if .Audience}}\nAudience: {{.Audience}}.{{end}}{{if .StyleNotes}}\nStyle notes: {{.StyleNotes}}{{end}}" +
+			"\nReturn only JSON: {\"translation\":\"...\"}."
 	}
 	if typ == "translate_single" && role == "user" {
-		return "project: {{.Project}} file: {{.FilePath}} key: {{.Key}} context: {{.Context}}\nsource: {{.Text}}"
+		return "project: {{.Project}} file: {{.FilePath}} key: {{.Key}} context: {{.Context}}" +
+			"{{if .Neighbors}}\nnearby units (context only, do not translate):{{range .Neighbors}}\n- {{.Key}}: {{.Text}}{{end}}{{end}}" +
+			"{{if .Glossary}}\nglossary (use these translations):{{range .Glossary}}\n- {{.Term}}{{if .DoNotTranslate}}: keep as is{{else}}{{if .Translations}}: {{join .Translations \" / \"}}{{end}}{{end}}{{if .Forbidden}} (never: {{join .Forbidden \", \"}}){{end}}{{if .PartOfSpeech}} [{{.PartOfSpeech}}]{{end}}{{end}}{{end}}" +
+			"{{if .Examples}}\napproved translations of similar strings (follow their tone and terminology):{{range .Examples}}\n- {{.Source}} => {{.Translation}}{{end}}{{end}}" +
+			"\nsource: {{.Text}}"
+	}
+	if typ == "translate_file" && role == "system" {
+		return "You are a professional localization translator. Translate the text of every unit from {{.SrcLang}} to {{.TgtLang}}. Preserve placeholders and tokens like __PH_0__ exactly, as well as Valve tags like <sfx>, <clr:...>. " +
+			"Do not change whitespace or punctuation{{if .Punctuation}} except to follow these punctuation conventions: {{.Punctuation}}{{end}}." +
+			"{{if .Formality}}\nFormality: {{.Formality}}.{{end}}{{if .Tone}}\nTone: {{.Tone}}.{{end}}{{if .Audience}}\nAudience: {{.Audience}}.{{end}}{{if .StyleNotes}}\nStyle notes: {{.StyleNotes}}{{end}}" +
+			"\nReturn only a JSON object mapping every unit key to its translation: {\"<key>\":\"...\"}."
+	}
+	if typ == "translate_file" && role == "user" {
+		return "project: {{.Project}} file: {{.FilePath}}" +
+			"{{if .Glossary}}\nglossary (use these translations):{{range .Glossary}}\n- {{.Term}}{{if .DoNotTranslate}}: keep as is{{else}}{{if .Translations}}: {{join .Translations \" / \"}}{{end}}{{end}}{{if .Forbidden}} (never: {{join .Forbidden \", \"}}){{end}}{{if .PartOfSpeech}} [{{.PartOfSpeech}}]{{end}}{{end}}{{end}}" +
+			"{{if .Examples}}\napproved translations of similar strings (follow their tone and terminology):{{range .Examples}}\n- {{.Source}} => {{.Translation}}{{end}}{{end}}" +
+			"\nunits:\n{{json .Units}}"
 	}
 	if typ == "detect_language" && role == "system" {
 		return "Identify the ISO 639-1 language code of the text. Return only JSON: {\"language\":\"<code>\"}."
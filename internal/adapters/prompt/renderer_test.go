@@ -0,0 +1,117 @@
+package prompt
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+func newTemplateRepo(t *testing.T) *dbsqlite.TemplateRepo {
+	t.Helper()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return dbsqlite.NewTemplateRepo(db)
+}
+
+func TestRenderPrecedence(t *testing.T) {
+	ctx := context.Background()
+	repo := newTemplateRepo(t)
+	r := New(repo)
+	project, provider, otherProject := int64(1), int64(2), int64(3)
+	scope := ports.TemplateScope{ProjectID: &project, ProviderID: &provider}
+	data := ports.PromptData{Text: "Hi"}
+	tests := []struct {
+		name     string
+		store    *domain.Template
+		override string
+		scope    ports.TemplateScope
+		source   string
+		text     string
+	}{
+		{name: "builtin", scope: scope, source: "builtin", text: "units:\nnull"},
+		{name: "global", store: &domain.Template{Scope: "global", Body: "global {{.Text}}"}, scope: scope, source: "global", text: "global Hi"},
+		{name: "project", store: &domain.Template{Scope: "project", RefID: &project, Body: "project {{.Text}}"}, scope: scope, source: "project", text: "project Hi"},
+		{name: "other project", scope: ports.TemplateScope{ProjectID: &otherProject}, source: "global", text: "global Hi"},
+		{name: "provider", store: &domain.Template{Scope: "provider", RefID: &provider, Body: "provider {{.Text}}"}, scope: scope, source: "provider", text: "provider Hi"},
+		{name: "provider without project", scope: ports.TemplateScope{ProviderID: &provider}, source: "provider", text: "provider Hi"},
+		{name: "job override", override: "job {{.Text}}", scope: scope, source: "override", text: "job Hi"},
+	}
+	// each case stores its level on top of the previous ones
+	for _, tt := range tests {
+		if tt.store != nil {
+			tt.store.Type, tt.store.Role = "translate_file", "user"
+			if err := repo.Upsert(ctx, tt.store); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := r.Render(ctx, tt.scope, "translate_file", "user", tt.override, data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.Source != tt.source || !strings.HasSuffix(got.Text, tt.text) {
+			t.Errorf("%s: rendered %q from %s, want %q from %s", tt.name, got.Text, got.Source, tt.text, tt.source)
+		}
+		if tt.store != nil && got.TemplateID != tt.store.ID {
+			t.Errorf("%s: template id %d, want %d", tt.name, got.TemplateID, tt.store.ID)
+		}
+	}
+}
+
+func TestSeedDefaults(t *testing.T) {
+	ctx := context.Background()
+	repo := newTemplateRepo(t)
+
+	edited := domain.Template{Scope: "global", Type: "detect_language", Role: "user", Body: "language of: {{.Text}}"}
+	if err := repo.Upsert(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := SeedDefaults(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	// seeding again changes nothing
+	if err := SeedDefaults(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range builtinKeys {
+		got, err := repo.GetEffective(ctx, ports.TemplateScope{}, k[0], k[1])
+		if err != nil || got == nil {
+			t.Fatalf("%s/%s: %v, %v", k[0], k[1], got, err)
+		}
+		want := builtinTemplate(k[0], k[1])
+		if k[0] == "detect_language" && k[1] == "user" {
+			want = edited.Body
+		}
+		if got.Body != want {
+			t.Errorf("%s/%s: body %q, want %q", k[0], k[1], got.Body, want)
+		}
+	}
+
+	// the outdated default of migration 002 is kept in the history
+	single, err := repo.GetEffective(ctx, ports.TemplateScope{}, "translate_single", "system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := repo.ListVersions(ctx, single.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Body == single.Body {
+		t.Fatalf("versions %+v, want the migrated default", versions)
+	}
+}
+
+func TestBuiltinTemplatesValidate(t *testing.T) {
+	for _, k := range builtinKeys {
+		if err := Validate(builtinTemplate(k[0], k[1])); err != nil {
+			t.Errorf("%s/%s: %v", k[0], k[1], err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	keyringService = "locail"
+	keyringPrefix  = "keyring:"
+	// keyringProbeTimeout bounds the startup check; a secret service that never answers
+	// must not hang the app.
+	keyringProbeTimeout = 3 * time.Second
+)
+
+// keyringAvailable reports whether the OS secret service (Keychain, Credential Manager,
+// Secret Service over D-Bus) accepts writes.
+func keyringAvailable() bool {
+	done := make(chan bool, 1)
+	go func() {
+		account := "probe-" + newAccount()
+		if err := keyring.Set(keyringService, account, "ok"); err != nil {
+			done <- false
+			return
+		}
+		_ = keyring.Delete(keyringService, account)
+		done <- true
+	}()
+	select {
+	case ok := <-done:
+		return ok
+	case <-time.After(keyringProbeTimeout):
+		return false
+	}
+}
+
+// newAccount returns a random keyring account name; the database keeps only this reference.
+func newAccount() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,145 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+// ProviderRepo wraps a provider repository so API keys are sealed by the Store on write and
+// opened on read; callers see plaintext keys, the database never does.
+type ProviderRepo struct {
+	ports.ProviderRepository
+	store *Store
+}
+
+func NewProviderRepo(inner ports.ProviderRepository, store *Store) *ProviderRepo {
+	return &ProviderRepo{ProviderRepository: inner, store: store}
+}
+
+func (r *ProviderRepo) Create(ctx context.Context, p *domain.Provider) error {
+	sealed, err := r.store.Seal(ctx, "", p.APIKey)
+	if err != nil {
+		return lockedAs(p.Name, err)
+	}
+	row := *p
+	row.APIKey = sealed
+	if err := r.ProviderRepository.Create(ctx, &row); err != nil {
+		_ = r.store.Forget(ctx, sealed)
+		return err
+	}
+	p.ID = row.ID
+	return nil
+}
+
+// Update seals a new key. A masked key leaves the stored one untouched, so other fields can
+// be edited while the vault is locked.
+func (r *ProviderRepo) Update(ctx context.Context, p *domain.Provider) error {
+	if domain.IsMaskedKey(p.APIKey) {
+		return r.ProviderRepository.Update(ctx, p)
+	}
+	existing, err := r.ProviderRepository.Get(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	sealed, err := r.store.Seal(ctx, existing.APIKey, p.APIKey)
+	if err != nil {
+		return lockedAs(p.Name, err)
+	}
+	row := *p
+	row.APIKey = sealed
+	return r.ProviderRepository.Update(ctx, &row)
+}
+
+func (r *ProviderRepo) Get(ctx context.Context, id int64) (*domain.Provider, error) {
+	p, err := r.ProviderRepository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.APIKey, err = r.store.Open(ctx, p.APIKey); err != nil {
+		if errors.Is(err, ErrLocked) {
+			return nil, &LockedError{Provider: p.Name}
+		}
+		return nil, fmt.Errorf("provider %s: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+// List opens every key; keys that cannot be opened (a locked vault) are returned masked, so
+// forms still see that a key is set.
+func (r *ProviderRepo) List(ctx context.Context) ([]*domain.Provider, error) {
+	list, err := r.ProviderRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range list {
+		if p.APIKey, err = r.store.Open(ctx, p.APIKey); err != nil {
+			p.APIKey = domain.MaskedKeyPrefix
+		}
+	}
+	return list, nil
+}
+
+func (r *ProviderRepo) Delete(ctx context.Context, id int64) error {
+	existing, err := r.ProviderRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.ProviderRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.store.Forget(ctx, existing.APIKey)
+}
+
+// lockedAs names the provider in a locked error.
+func lockedAs(provider string, err error) error {
+	if errors.Is(err, ErrLocked) {
+		return &LockedError{Provider: provider}
+	}
+	return err
+}
+
+// Plaintext counts providers whose key is still stored unencrypted.
+func (r *ProviderRepo) Plaintext(ctx context.Context) (int, error) {
+	list, err := r.ProviderRepository.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, p := range list {
+		if p.APIKey != "" && !Sealed(p.APIKey) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SealPlaintext seals keys stored before encryption was introduced. In passphrase mode it
+// does nothing until the vault is unlocked.
+func (r *ProviderRepo) SealPlaintext(ctx context.Context) (int, error) {
+	list, err := r.ProviderRepository.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, p := range list {
+		if p.APIKey == "" || Sealed(p.APIKey) {
+			continue
+		}
+		sealed, err := r.store.Seal(ctx, "", p.APIKey)
+		if errors.Is(err, ErrLocked) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		p.APIKey = sealed
+		if err := r.ProviderRepository.Update(ctx, p); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/ports"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Storage modes for new secrets.
+const (
+	ModeKeyring    = "keyring"
+	ModePassphrase = "passphrase"
+)
+
+// Store seals secrets for storage in the database. With an OS secret service the secret is
+// kept there and the database holds a "keyring:<account>" reference; otherwise it is
+// encrypted by the Vault. Values without a known prefix are legacy plaintext.
+type Store struct {
+	vault   *Vault
+	keyring bool
+}
+
+// NewStore probes the OS secret service; without one secrets use the passphrase vault.
+func NewStore(settings ports.SettingsRepository) *Store {
+	return &Store{vault: NewVault(settings), keyring: keyringAvailable()}
+}
+
+func (s *Store) Mode() string {
+	if s.keyring {
+		return ModeKeyring
+	}
+	return ModePassphrase
+}
+
+func (s *Store) Vault() *Vault { return s.vault }
+
+// Sealed reports whether stored is a keyring reference or an encrypted value.
+func Sealed(stored string) bool {
+	return strings.HasPrefix(stored, keyringPrefix) || strings.HasPrefix(stored, vaultPrefix)
+}
+
+// Seal stores secret and returns the value to keep in the database. prev is the value
+// currently stored for the same record; its keyring entry is reused.
+func (s *Store) Seal(ctx context.Context, prev, secret string) (string, error) {
+	if secret == "" {
+		return "", s.Forget(ctx, prev)
+	}
+	if !s.keyring {
+		sealed, err := s.vault.Seal(secret)
+		if err != nil {
+			return "", err
+		}
+		return sealed, s.Forget(ctx, prev)
+	}
+	account, ok := strings.CutPrefix(prev, keyringPrefix)
+	if !ok {
+		account = newAccount()
+	}
+	if err := keyring.Set(keyringService, account, secret); err != nil {
+		return "", fmt.Errorf("store secret in keyring: %w", err)
+	}
+	return keyringPrefix + account, nil
+}
+
+// Open returns the secret behind a stored value; plaintext values are returned as is.
+func (s *Store) Open(ctx context.Context, stored string) (string, error) {
+	switch {
+	case strings.HasPrefix(stored, keyringPrefix):
+		secret, err := keyring.Get(keyringService, strings.TrimPrefix(stored, keyringPrefix))
+		if err != nil {
+			return "", fmt.Errorf("read secret from keyring: %w", err)
+		}
+		return secret, nil
+	case strings.HasPrefix(stored, vaultPrefix):
+		return s.vault.Open(stored)
+	}
+	return stored, nil
+}
+
+// Forget removes the keyring entry behind stored, if any. Entries are left behind when the
+// secret service is no longer available.
+func (s *Store) Forget(ctx context.Context, stored string) error {
+	account, ok := strings.CutPrefix(stored, keyringPrefix)
+	if !ok || !s.keyring {
+		return nil
+	}
+	if err := keyring.Delete(keyringService, account); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("delete secret from keyring: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"locail/internal/ports"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// kdfSettingKey holds the vault's salt, Argon2id parameters and passphrase check.
+	kdfSettingKey = "secrets.kdf"
+	// checkText is encrypted with the derived key to verify a passphrase on unlock.
+	checkText    = "locail-secrets-v1"
+	vaultPrefix  = "enc:v1:"
+	vaultKeyLen  = 32
+	vaultSaltLen = 16
+	// MinPassphrase is the minimum passphrase length in characters.
+	MinPassphrase = 8
+)
+
+// LockedCode starts the message of every LockedError. Errors reach the UI as plain strings,
+// which turns this code into an unlock prompt.
+const LockedCode = "secrets_locked"
+
+// LockedError reports that a secret is needed while the passphrase vault is locked.
+type LockedError struct {
+	// Provider names the provider whose key was needed; empty when none was involved.
+	Provider string
+}
+
+func (e *LockedError) Error() string {
+	if e.Provider == "" {
+		return LockedCode + ": api keys are locked: unlock them with the passphrase"
+	}
+	return fmt.Sprintf("%s: the api key of %s is locked: unlock it with the passphrase", LockedCode, e.Provider)
+}
+
+// Is makes every LockedError match ErrLocked.
+func (e *LockedError) Is(target error) bool { return target == ErrLocked }
+
+var (
+	ErrLocked        error = &LockedError{}
+	ErrWrongPassword       = errors.New("wrong passphrase")
+	errInvalidSecret       = errors.New("invalid encrypted secret")
+)
+
+var defaultKDFParams = kdfParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+type kdfParams struct {
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"` // KiB
+	Threads uint8  `json:"threads"`
+	Check   string `json:"check"`
+}
+
+// Vault encrypts secrets with AES-GCM under a key derived from a passphrase with Argon2id.
+// The key is kept in memory once unlocked, for the rest of the session.
+type Vault struct {
+	settings ports.SettingsRepository
+
+	mu  sync.RWMutex
+	key []byte
+}
+
+func NewVault(settings ports.SettingsRepository) *Vault { return &Vault{settings: settings} }
+
+func (v *Vault) params(ctx context.Context) (*kdfParams, error) {
+	raw, err := v.settings.Get(ctx, kdfSettingKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p kdfParams
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return nil, fmt.Errorf("read vault settings: %w", err)
+	}
+	return &p, nil
+}
+
+// Initialized reports whether a passphrase has been set.
+func (v *Vault) Initialized(ctx context.Context) (bool, error) {
+	p, err := v.params(ctx)
+	return p != nil, err
+}
+
+func (v *Vault) Locked() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.key == nil
+}
+
+// Unlock derives the key from passphrase and keeps it for the session. The first call sets
+// the passphrase; later calls fail with ErrWrongPassword when it does not match.
+func (v *Vault) Unlock(ctx context.Context, passphrase string) error {
+	p, err := v.params(ctx)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return v.initialize(ctx, passphrase)
+	}
+	salt, err := base64.StdEncoding.DecodeString(p.Salt)
+	if err != nil {
+		return fmt.Errorf("read vault settings: %w", err)
+	}
+	key := argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, vaultKeyLen)
+	check, err := decrypt(key, p.Check)
+	if err != nil || subtle.ConstantTimeCompare([]byte(check), []byte(checkText)) != 1 {
+		return ErrWrongPassword
+	}
+	v.setKey(key)
+	return nil
+}
+
+func (v *Vault) initialize(ctx context.Context, passphrase string) error {
+	if len([]rune(passphrase)) < MinPassphrase {
+		return fmt.Errorf("passphrase must be at least %d characters", MinPassphrase)
+	}
+	salt := make([]byte, vaultSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	p := defaultKDFParams
+	p.Salt = base64.StdEncoding.EncodeToString(salt)
+	key := argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, vaultKeyLen)
+	check, err := encrypt(key, checkText)
+	if err != nil {
+		return err
+	}
+	p.Check = check
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := v.settings.Set(ctx, kdfSettingKey, string(raw)); err != nil {
+		return err
+	}
+	v.setKey(key)
+	return nil
+}
+
+func (v *Vault) setKey(key []byte) {
+	v.mu.Lock()
+	v.key = key
+	v.mu.Unlock()
+}
+
+// Lock drops the key; secrets cannot be read or written until the next Unlock.
+func (v *Vault) Lock() {
+	v.mu.Lock()
+	clear(v.key)
+	v.key = nil
+	v.mu.Unlock()
+}
+
+// Seal encrypts secret into an "enc:v1:" value.
+func (v *Vault) Seal(secret string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.key == nil {
+		return "", ErrLocked
+	}
+	ct, err := encrypt(v.key, secret)
+	if err != nil {
+		return "", err
+	}
+	return vaultPrefix + ct, nil
+}
+
+// Open decrypts a value produced by Seal.
+func (v *Vault) Open(sealed string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.key == nil {
+		return "", ErrLocked
+	}
+	return decrypt(v.key, strings.TrimPrefix(sealed, vaultPrefix))
+}
+
+// encrypt returns base64(nonce || ciphertext).
+func encrypt(key []byte, plain string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+func decrypt(key []byte, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return "", errInvalidSecret
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errInvalidSecret
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
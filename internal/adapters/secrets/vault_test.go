@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type memSettings map[string]string
+
+func (m memSettings) Get(_ context.Context, key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return v, nil
+}
+
+func (m memSettings) Set(_ context.Context, key, value string) error {
+	m[key] = value
+	return nil
+}
+
+func init() {
+	// keep Argon2id cheap in tests
+	defaultKDFParams = kdfParams{Time: 1, Memory: 1024, Threads: 1}
+}
+
+func TestVaultSealOpen(t *testing.T) {
+	ctx := context.Background()
+	settings := memSettings{}
+	v := NewVault(settings)
+	if err := v.Unlock(ctx, "correct horse"); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	sealed, err := v.Seal("sk-secret")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, vaultPrefix) || strings.Contains(sealed, "sk-secret") {
+		t.Fatalf("sealed value %q", sealed)
+	}
+	v.Lock()
+
+	tests := []struct {
+		name       string
+		passphrase string
+		wantErr    error
+	}{
+		{"wrong passphrase", "wrong horse", ErrWrongPassword},
+		{"empty passphrase", "", ErrWrongPassword},
+		{"correct passphrase", "correct horse", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVault(settings)
+			err := v.Unlock(ctx, tt.passphrase)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unlock: got %v, want %v", err, tt.wantErr)
+			}
+			got, err := v.Open(sealed)
+			if tt.wantErr != nil {
+				if !errors.Is(err, ErrLocked) {
+					t.Fatalf("open after failed unlock: got %v, want ErrLocked", err)
+				}
+				return
+			}
+			if err != nil || got != "sk-secret" {
+				t.Fatalf("open: got %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestVaultLocked(t *testing.T) {
+	v := NewVault(memSettings{})
+	if !v.Locked() {
+		t.Fatal("new vault is unlocked")
+	}
+	if _, err := v.Seal("x"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("seal: got %v, want ErrLocked", err)
+	}
+	if _, err := v.Open(vaultPrefix + "AAAA"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("open: got %v, want ErrLocked", err)
+	}
+	err := &LockedError{Provider: "OpenAI"}
+	if !errors.Is(err, ErrLocked) || !strings.HasPrefix(err.Error(), LockedCode) {
+		t.Fatalf("LockedError %q does not match ErrLocked", err)
+	}
+}
+
+func TestVaultInitializeShortPassphrase(t *testing.T) {
+	ctx := context.Background()
+	settings := memSettings{}
+	v := NewVault(settings)
+	if err := v.Unlock(ctx, "short"); err == nil {
+		t.Fatal("short passphrase accepted")
+	}
+	if ok, _ := v.Initialized(ctx); ok {
+		t.Fatal("vault initialized after a rejected passphrase")
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	key := make([]byte, vaultKeyLen)
+	sealed, err := encrypt(key, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		sealed string
+	}{
+		{"not base64", "%%%"},
+		{"too short", "AAAA"},
+		{"flipped byte", sealed[:len(sealed)-4] + "AAAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decrypt(key, tt.sealed); !errors.Is(err, errInvalidSecret) {
+				t.Fatalf("got %v, want errInvalidSecret", err)
+			}
+		})
+	}
+	other := make([]byte, vaultKeyLen)
+	other[0] = 1
+	if _, err := decrypt(other, sealed); !errors.Is(err, errInvalidSecret) {
+		t.Fatalf("wrong key: got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package termbase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"locail/internal/domain"
+	"strings"
+)
+
+// ParseCSV reads a glossary table. The header must contain a "term" (or "source") column;
+// optional columns are description, part_of_speech (pos), case_sensitive and
+// do_not_translate (dnt). Every other column is a locale with approved translations;
+// "<locale>:forbidden" columns hold forbidden ones. Multiple values are separated by "|".
+func ParseCSV(data []byte) ([]*domain.GlossaryTerm, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	if sc := sniffSeparator(data); sc != 0 {
+		r.Comma = sc
+	}
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("glossary csv is empty")
+	}
+	termIdx := -1
+	cols := make([]string, len(rows[0]))
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(h))
+		cols[i] = h
+		if termIdx < 0 && (h == "term" || h == "source") {
+			termIdx = i
+		}
+	}
+	if termIdx < 0 {
+		return nil, errors.New("glossary csv missing 'term' column")
+	}
+	var out []*domain.GlossaryTerm
+	for _, row := range rows[1:] {
+		if termIdx >= len(row) || strings.TrimSpace(row[termIdx]) == "" {
+			continue
+		}
+		t := &domain.GlossaryTerm{Term: strings.TrimSpace(row[termIdx])}
+		for i, v := range row {
+			if i == termIdx || i >= len(cols) {
+				continue
+			}
+			v = strings.TrimSpace(v)
+			switch cols[i] {
+			case "description", "definition", "note", "notes":
+				t.Description = v
+			case "part_of_speech", "pos", "partofspeech":
+				t.PartOfSpeech = v
+			case "case_sensitive", "case":
+				t.CaseSensitive = isTrue(v)
+			case "do_not_translate", "dnt", "donottranslate":
+				t.DoNotTranslate = isTrue(v)
+			case "":
+			default:
+				locale, forbidden := cols[i], false
+				if l, ok := strings.CutSuffix(locale, ":forbidden"); ok {
+					locale, forbidden = l, true
+				}
+				for _, text := range strings.Split(v, "|") {
+					if text = strings.TrimSpace(text); text != "" {
+						t.Translations = append(t.Translations, domain.GlossaryTranslation{Locale: locale, Text: text, Forbidden: forbidden})
+					}
+				}
+			}
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// sniffSeparator picks ';' or tab when the header line uses it instead of a comma.
+func sniffSeparator(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	best, count := rune(0), bytes.Count(line, []byte(","))
+	for _, sep := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(sep))); n > count {
+			best, count = sep, n
+		}
+	}
+	return best
+}
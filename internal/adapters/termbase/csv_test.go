@@ -0,0 +1,65 @@
+package termbase
+
+import (
+	"locail/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []*domain.GlossaryTerm
+	}{
+		{
+			name: "comma with options and forbidden column",
+			data: "\xEF\xBB\xBFTerm,Description,POS,DNT,de,de:forbidden\n" +
+				"Save,store a file,verb,no,Speichern|Sichern,Retten\n" +
+				"Steam,,noun,yes,,\n" +
+				",orphan,,,x,\n",
+			want: []*domain.GlossaryTerm{
+				{Term: "Save", Description: "store a file", PartOfSpeech: "verb", Translations: []domain.GlossaryTranslation{
+					{Locale: "de", Text: "Speichern"},
+					{Locale: "de", Text: "Sichern"},
+					{Locale: "de", Text: "Retten", Forbidden: true},
+				}},
+				{Term: "Steam", PartOfSpeech: "noun", DoNotTranslate: true},
+			},
+		},
+		{
+			name: "semicolon separated with source column",
+			data: "source;fr;case_sensitive\nOK; D'accord ;true\n",
+			want: []*domain.GlossaryTerm{
+				{Term: "OK", CaseSensitive: true, Translations: []domain.GlossaryTranslation{{Locale: "fr", Text: "D'accord"}}},
+			},
+		},
+		{
+			name: "tab separated, short rows",
+			data: "term\tes\tit\nHello\tHola\nBye\n",
+			want: []*domain.GlossaryTerm{
+				{Term: "Hello", Translations: []domain.GlossaryTranslation{{Locale: "es", Text: "Hola"}}},
+				{Term: "Bye"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCSV([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseCSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %s, want %s", dump(got), dump(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	for _, data := range []string{"", "name,de\nSave,Speichern\n"} {
+		if _, err := ParseCSV([]byte(data)); err == nil {
+			t.Errorf("ParseCSV(%q) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package termbase
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"locail/internal/domain"
+	"strings"
+)
+
+const xmlNS = "http://www.w3.org/XML/1998/namespace"
+
+type tbxTerm struct {
+	lang   string
+	text   string
+	pos    string
+	status string
+	dnt    bool
+}
+
+type tbxEntry struct {
+	descr string
+	terms []tbxTerm
+}
+
+// ParseTBX reads TBX (TBX-Basic, TBX v2 <martif> and TBX v3 <tbx>) concept entries.
+// Terms in srcLang become glossary terms; terms in other languages become their
+// translations, with deprecated/superseded terms marked forbidden. When srcLang is
+// empty the document's xml:lang is used.
+func ParseTBX(data []byte, srcLang string) ([]*domain.GlossaryTerm, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		entries  []tbxEntry
+		entry    *tbxEntry
+		term     *tbxTerm
+		lang     string
+		docLang  string
+		field    string // element whose text is being collected
+		fieldTyp string
+		text     strings.Builder
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid TBX: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "martif", "tbx":
+				docLang = attr(t, "lang")
+			case "termEntry", "conceptEntry":
+				entries = append(entries, tbxEntry{})
+				entry = &entries[len(entries)-1]
+			case "langSet", "langSec":
+				lang = attr(t, "lang")
+			case "tig", "ntig", "termSec":
+				if entry != nil {
+					entry.terms = append(entry.terms, tbxTerm{lang: lang})
+					term = &entry.terms[len(entry.terms)-1]
+				}
+			case "term", "termNote", "descrip":
+				field, fieldTyp = t.Name.Local, attr(t, "type")
+				text.Reset()
+			}
+		case xml.CharData:
+			if field != "" {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local != field {
+				switch t.Name.Local {
+				case "termEntry", "conceptEntry":
+					entry, term = nil, nil
+				case "tig", "ntig", "termSec":
+					term = nil
+				}
+				continue
+			}
+			val := strings.TrimSpace(text.String())
+			switch {
+			case field == "term" && term != nil:
+				term.text = val
+			case field == "termNote" && term != nil:
+				switch strings.ToLower(fieldTyp) {
+				case "partofspeech":
+					term.pos = val
+				case "administrativestatus", "normativeauthorization":
+					term.status = strings.ToLower(val)
+				default:
+					if isDNTType(fieldTyp) {
+						term.dnt = isTrue(val)
+					}
+				}
+			case field == "descrip" && entry != nil:
+				if isDNTType(fieldTyp) && term != nil {
+					term.dnt = isTrue(val)
+				} else if (fieldTyp == "definition" || fieldTyp == "context" || fieldTyp == "") && entry.descr == "" {
+					entry.descr = val
+				}
+			}
+			field, fieldTyp = "", ""
+		}
+	}
+	if srcLang == "" {
+		srcLang = docLang
+	}
+	return tbxToTerms(entries, srcLang), nil
+}
+
+func tbxToTerms(entries []tbxEntry, srcLang string) []*domain.GlossaryTerm {
+	var out []*domain.GlossaryTerm
+	for _, e := range entries {
+		var sources []tbxTerm
+		var targets []tbxTerm
+		for _, t := range e.terms {
+			if t.text == "" {
+				continue
+			}
+			if langMatches(t.lang, srcLang) {
+				sources = append(sources, t)
+			} else {
+				targets = append(targets, t)
+			}
+		}
+		if srcLang == "" && len(sources) == 0 && len(targets) > 0 {
+			sources, targets = targets[:1], targets[1:]
+		}
+		for _, s := range sources {
+			if isForbiddenStatus(s.status) {
+				continue
+			}
+			gt := &domain.GlossaryTerm{
+				Term:           s.text,
+				Description:    e.descr,
+				PartOfSpeech:   s.pos,
+				DoNotTranslate: s.dnt,
+			}
+			for _, t := range targets {
+				gt.Translations = append(gt.Translations, domain.GlossaryTranslation{
+					Locale:    t.lang,
+					Text:      t.text,
+					Forbidden: isForbiddenStatus(t.status),
+				})
+			}
+			out = append(out, gt)
+		}
+	}
+	return out
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name && (a.Name.Space == "" || a.Name.Space == xmlNS || a.Name.Space == "xml") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func langMatches(lang, src string) bool {
+	lang, src = normalizeLang(lang), normalizeLang(src)
+	if lang == "" || src == "" {
+		return false
+	}
+	return lang == src || strings.HasPrefix(lang, src+"-") || strings.HasPrefix(src, lang+"-")
+}
+
+func isForbiddenStatus(s string) bool {
+	return strings.HasPrefix(s, "deprecated") || strings.HasPrefix(s, "superseded")
+}
+
+func isDNTType(typ string) bool {
+	t := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(typ))
+	return strings.Contains(t, "donottranslate") || t == "xdnt" || t == "dnt"
+}
+
+func isTrue(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", "y", "x":
+		return true
+	}
+	return false
+}
+
+func normalizeLang(l string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(l)), "_", "-")
+}
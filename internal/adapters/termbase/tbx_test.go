@@ -0,0 +1,92 @@
+package termbase
+
+import (
+	"fmt"
+	"locail/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tbxV2 = `<?xml version="1.0"?>
+<martif type="TBX" xml:lang="en"><text><body>
+<termEntry>
+  <descrip type="definition">To store data</descrip>
+  <langSet xml:lang="en-US"><tig><term>Save</term><termNote type="partOfSpeech">verb</termNote></tig></langSet>
+  <langSet xml:lang="de"><tig><term>Speichern</term></tig>
+    <tig><term>Abspeichern</term><termNote type="administrativeStatus">deprecatedTerm-admn-sts</termNote></tig></langSet>
+</termEntry>
+<termEntry>
+  <langSet xml:lang="en"><tig><term>Steam</term><termNote type="x-doNotTranslate">yes</termNote></tig></langSet>
+</termEntry>
+</body></text></martif>`
+
+const tbxV3 = `<?xml version="1.0"?>
+<tbx type="TBX-Basic" style="dca" xml:lang="fr" xmlns="urn:iso:std:iso:30042:ed-2"><text><body>
+<conceptEntry id="c1">
+  <langSec xml:lang="fr"><termSec><term>Ouvrir</term></termSec>
+    <termSec><term>Ouvre</term><termNote type="administrativeStatus">supersededTerm-admn-sts</termNote></termSec></langSec>
+  <langSec xml:lang="en"><termSec><term>Open</term></termSec></langSec>
+</conceptEntry>
+</body></text></tbx>`
+
+func TestParseTBX(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		srcLang string
+		want    []*domain.GlossaryTerm
+	}{
+		{
+			name: "TBX v2 with document language",
+			data: tbxV2,
+			want: []*domain.GlossaryTerm{
+				{Term: "Save", Description: "To store data", PartOfSpeech: "verb", Translations: []domain.GlossaryTranslation{
+					{Locale: "de", Text: "Speichern"},
+					{Locale: "de", Text: "Abspeichern", Forbidden: true},
+				}},
+				{Term: "Steam", DoNotTranslate: true},
+			},
+		},
+		{
+			name:    "TBX v2 with another source language",
+			data:    tbxV2,
+			srcLang: "de_DE",
+			want: []*domain.GlossaryTerm{
+				{Term: "Speichern", Description: "To store data", Translations: []domain.GlossaryTranslation{{Locale: "en-US", Text: "Save"}}},
+			},
+		},
+		{
+			name: "TBX v3 skips superseded source terms",
+			data: tbxV3,
+			want: []*domain.GlossaryTerm{
+				{Term: "Ouvrir", Translations: []domain.GlossaryTranslation{{Locale: "en", Text: "Open"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTBX([]byte(tt.data), tt.srcLang)
+			if err != nil {
+				t.Fatalf("ParseTBX: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %s, want %s", dump(got), dump(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseTBXInvalid(t *testing.T) {
+	if _, err := ParseTBX([]byte("<martif><text>"), ""); err == nil {
+		t.Fatal("truncated TBX accepted")
+	}
+}
+
+func dump(terms []*domain.GlossaryTerm) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = fmt.Sprintf("%+v", *t)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,270 @@
+package tmx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Version written into exported documents.
+const Version = "1.4"
+
+const dateLayout = "20060102T150405Z"
+
+// Unit is a translation unit reduced to one source/target pair.
+type Unit struct {
+	SrcLang   string
+	TgtLang   string
+	Source    string
+	Target    string
+	CreatedAt time.Time
+	ChangedAt time.Time
+	// Props are TMX <prop> values keyed by type (e.g. "x-project").
+	Props map[string]string
+}
+
+type document struct {
+	XMLName xml.Name `xml:"tmx"`
+	Version string   `xml:"version,attr"`
+	Header  header   `xml:"header"`
+	Body    []tu     `xml:"body>tu"`
+}
+
+type header struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTmf                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+	CreationDate        string `xml:"creationdate,attr,omitempty"`
+}
+
+type tu struct {
+	SrcLang      string `xml:"srclang,attr,omitempty"`
+	CreationDate string `xml:"creationdate,attr,omitempty"`
+	ChangeDate   string `xml:"changedate,attr,omitempty"`
+	Props        []prop `xml:"prop"`
+	Tuvs         []tuv  `xml:"tuv"`
+}
+
+type prop struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tuv struct {
+	Lang         string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	LegacyLang   string `xml:"lang,attr,omitempty"` // TMX 1.1
+	CreationDate string `xml:"creationdate,attr,omitempty"`
+	ChangeDate   string `xml:"changedate,attr,omitempty"`
+	Seg          seg    `xml:"seg"`
+}
+
+func (v tuv) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+// seg keeps the text of a segment. Inline elements (<ph>, <bpt>, <ept>, <it>, <hi>, ...)
+// are flattened to their content, which holds the original native codes.
+type seg struct {
+	Text string
+}
+
+func (s *seg) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	depth := 1
+	for depth > 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	s.Text = b.String()
+	return nil
+}
+
+func (s seg) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(s.Text, start)
+}
+
+// Decode reads a TMX document and returns one Unit per source/target pair. The source
+// variant is chosen by the unit's or header's srclang; with "*all*" the first variant is used.
+func Decode(data []byte) ([]Unit, error) {
+	var doc document
+	dec := xml.NewDecoder(bytes.NewReader(toUTF8(data)))
+	dec.CharsetReader = charsetReader
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid TMX: %w", err)
+	}
+	var out []Unit
+	for _, t := range doc.Body {
+		if len(t.Tuvs) < 2 {
+			continue
+		}
+		srcLang := t.SrcLang
+		if srcLang == "" {
+			srcLang = doc.Header.SrcLang
+		}
+		srcIdx := 0
+		if srcLang != "" && srcLang != "*all*" {
+			srcIdx = -1
+			for i, v := range t.Tuvs {
+				if strings.EqualFold(v.lang(), srcLang) {
+					srcIdx = i
+					break
+				}
+			}
+			if srcIdx < 0 {
+				continue
+			}
+		}
+		src := t.Tuvs[srcIdx]
+		props := make(map[string]string, len(t.Props))
+		for _, p := range t.Props {
+			props[p.Type] = strings.TrimSpace(p.Value)
+		}
+		for i, v := range t.Tuvs {
+			if i == srcIdx || strings.TrimSpace(v.Seg.Text) == "" || strings.TrimSpace(src.Seg.Text) == "" {
+				continue
+			}
+			out = append(out, Unit{
+				SrcLang:   src.lang(),
+				TgtLang:   v.lang(),
+				Source:    src.Seg.Text,
+				Target:    v.Seg.Text,
+				CreatedAt: firstDate(v.CreationDate, t.CreationDate),
+				ChangedAt: firstDate(v.ChangeDate, t.ChangeDate),
+				Props:     props,
+			})
+		}
+	}
+	return out, nil
+}
+
+// charsetReader lets documents that declare UTF-16 through, since toUTF8 has already
+// converted them; other non-UTF-8 encodings are rejected.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(label) {
+	case "utf-8", "utf8", "us-ascii", "ascii", "utf-16", "utf16", "utf-16le", "utf-16be":
+		return input, nil
+	}
+	return nil, errors.New("unsupported TMX encoding: " + label + " (convert to UTF-8)")
+}
+
+// toUTF8 strips a UTF-8 BOM and converts BOM-marked UTF-16 (common in CAT tool exports).
+func toUTF8(data []byte) []byte {
+	if bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
+		return data[3:]
+	}
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		order = binary.BigEndian
+	default:
+		return data
+	}
+	data = data[2:]
+	u16 := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		u16 = append(u16, order.Uint16(data[i:]))
+	}
+	return []byte(string(utf16.Decode(u16)))
+}
+
+func firstDate(vals ...string) time.Time {
+	for _, v := range vals {
+		if t, err := time.Parse(dateLayout, strings.TrimSpace(v)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Encode writes units as a TMX 1.4b document. srcLang is recorded in the header and may be
+// "*all*" when the units have mixed source languages. Every unit needs its source and target
+// language, as <tuv> elements without xml:lang are invalid.
+func Encode(units []Unit, srcLang string) ([]byte, error) {
+	if srcLang == "" {
+		srcLang = "*all*"
+	}
+	doc := document{
+		Version: Version,
+		Header: header{
+			CreationTool:        "locail",
+			CreationToolVersion: "1",
+			SegType:             "sentence",
+			OTmf:                "locail",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+			CreationDate:        time.Now().UTC().Format(dateLayout),
+		},
+		Body: make([]tu, 0, len(units)),
+	}
+	for _, u := range units {
+		t := tu{
+			CreationDate: formatDate(u.CreatedAt),
+			ChangeDate:   formatDate(u.ChangedAt),
+			Tuvs: []tuv{
+				{Lang: u.SrcLang, Seg: seg{Text: u.Source}},
+				{Lang: u.TgtLang, Seg: seg{Text: u.Target}},
+			},
+		}
+		if srcLang == "*all*" {
+			t.SrcLang = u.SrcLang
+		}
+		for _, k := range sortedKeys(u.Props) {
+			if u.Props[k] != "" {
+				t.Props = append(t.Props, prop{Type: k, Value: u.Props[k]})
+			}
+		}
+		doc.Body = append(doc.Body, t)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(dateLayout)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
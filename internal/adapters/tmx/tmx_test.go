@@ -0,0 +1,146 @@
+package tmx
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []Unit
+	}{
+		{
+			name: "TMX 1.1 lang",
+			doc: `<?xml version="1.0"?>
+<tmx version="1.1"><header srclang="EN-US"/><body>
+<tu><tuv lang="EN-US"><seg>Open</seg></tuv><tuv lang="DE-DE"><seg>Öffnen</seg></tuv></tu>
+</body></tmx>`,
+			want: []Unit{{SrcLang: "EN-US", TgtLang: "DE-DE", Source: "Open", Target: "Öffnen"}},
+		},
+		{
+			name: "TMX 1.4b xml:lang",
+			doc: `<?xml version="1.0"?>
+<tmx version="1.4"><header srclang="en"/><body>
+<tu><tuv xml:lang="de"><seg>Schließen</seg></tuv><tuv xml:lang="en"><seg>Close</seg></tuv><tuv xml:lang="fr"><seg>Fermer</seg></tuv></tu>
+</body></tmx>`,
+			want: []Unit{
+				{SrcLang: "en", TgtLang: "de", Source: "Close", Target: "Schließen"},
+				{SrcLang: "en", TgtLang: "fr", Source: "Close", Target: "Fermer"},
+			},
+		},
+		{
+			name: "unit srclang overrides the header",
+			doc: `<tmx version="1.4"><header srclang="en"/><body>
+<tu srclang="fr"><tuv xml:lang="en"><seg>Yes</seg></tuv><tuv xml:lang="fr"><seg>Oui</seg></tuv></tu>
+</body></tmx>`,
+			want: []Unit{{SrcLang: "fr", TgtLang: "en", Source: "Oui", Target: "Yes"}},
+		},
+		{
+			name: "all languages takes the first variant",
+			doc: `<tmx version="1.4"><header srclang="*all*"/><body>
+<tu><tuv xml:lang="es"><seg>Sí</seg></tuv><tuv xml:lang="en"><seg>Yes</seg></tuv></tu>
+</body></tmx>`,
+			want: []Unit{{SrcLang: "es", TgtLang: "en", Source: "Sí", Target: "Yes"}},
+		},
+		{
+			name: "missing source variant and empty segments are skipped",
+			doc: `<tmx version="1.4"><header srclang="en"/><body>
+<tu><tuv xml:lang="de"><seg>Ja</seg></tuv><tuv xml:lang="fr"><seg>Oui</seg></tuv></tu>
+<tu><tuv xml:lang="en"><seg>No</seg></tuv><tuv xml:lang="de"><seg> </seg></tuv></tu>
+<tu><tuv xml:lang="en"><seg>Alone</seg></tuv></tu>
+</body></tmx>`,
+			want: nil,
+		},
+		{
+			name: "inline codes, props and dates",
+			doc: `<tmx version="1.4"><header srclang="en"/><body>
+<tu creationdate="20240102T030405Z"><prop type="x-project"> Shop </prop>
+<tuv xml:lang="en"><seg>Hello <ph>{name}</ph>!</seg></tuv>
+<tuv xml:lang="de" changedate="20240203T000000Z"><seg>Hallo <ph>{name}</ph>!</seg></tuv></tu>
+</body></tmx>`,
+			want: []Unit{{
+				SrcLang: "en", TgtLang: "de", Source: "Hello {name}!", Target: "Hallo {name}!",
+				CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				ChangedAt: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+				Props:     map[string]string{"x-project": "Shop"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			assertUnits(t, got, tt.want)
+		})
+	}
+}
+
+func TestDecodeEncodings(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-16"?><tmx version="1.4"><header srclang="en"/><body>` +
+		`<tu><tuv xml:lang="en"><seg>Save</seg></tuv><tuv xml:lang="ja"><seg>保存</seg></tuv></tu></body></tmx>`
+	u16 := utf16.Encode([]rune(doc))
+	le := []byte{0xFF, 0xFE}
+	for _, c := range u16 {
+		le = binary.LittleEndian.AppendUint16(le, c)
+	}
+	got, err := Decode(le)
+	if err != nil {
+		t.Fatalf("UTF-16LE: %v", err)
+	}
+	assertUnits(t, got, []Unit{{SrcLang: "en", TgtLang: "ja", Source: "Save", Target: "保存"}})
+
+	latin1 := `<?xml version="1.0" encoding="ISO-8859-1"?><tmx version="1.4"><header srclang="en"/><body/></tmx>`
+	if _, err := Decode([]byte(latin1)); err == nil || !strings.Contains(err.Error(), "ISO-8859-1") {
+		t.Fatalf("ISO-8859-1: got %v, want an unsupported encoding error", err)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	units := []Unit{
+		{SrcLang: "en", TgtLang: "de", Source: "A & B", Target: "A & B <ok>", Props: map[string]string{"x-status": "approved", "x-empty": ""}},
+		{SrcLang: "fr", TgtLang: "en", Source: "Oui", Target: "Yes", ChangedAt: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)},
+	}
+	out, err := Encode(units, "*all*")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if !strings.Contains(string(out), `xml:lang="de"`) {
+		t.Fatalf("export does not use xml:lang:\n%s", out)
+	}
+	got, err := Decode(out)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	units[0].Props = map[string]string{"x-status": "approved"}
+	units[1].Props = map[string]string{}
+	assertUnits(t, got, units)
+}
+
+func assertUnits(t *testing.T, got, want []Unit) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d units, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.SrcLang != w.SrcLang || g.TgtLang != w.TgtLang || g.Source != w.Source || g.Target != w.Target ||
+			!g.CreatedAt.Equal(w.CreatedAt) || !g.ChangedAt.Equal(w.ChangedAt) {
+			t.Errorf("unit %d: got %+v, want %+v", i, g, w)
+		}
+		for k, v := range w.Props {
+			if g.Props[k] != v {
+				t.Errorf("unit %d: prop %s = %q, want %q", i, k, g.Props[k], v)
+			}
+		}
+		if w.Props != nil && len(g.Props) != len(w.Props) {
+			t.Errorf("unit %d: props %v, want %v", i, g.Props, w.Props)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheAPI exposes inspection and maintenance of the translation cache.
+type CacheAPI struct {
+	repo     ports.CacheRepository
+	settings ports.SettingsRepository
+}
+
+func NewCacheAPI(repo ports.CacheRepository, settings ports.SettingsRepository) *CacheAPI {
+	return &CacheAPI{repo: repo, settings: settings}
+}
+
+func (a *CacheAPI) Stats() (*domain.CacheStats, error) {
+	ctx := context.Background()
+	return a.repo.Stats(ctx)
+}
+
+func (a *CacheAPI) List(f domain.CacheFilter) ([]*domain.CacheEntry, error) {
+	ctx := context.Background()
+	return a.repo.List(ctx, f)
+}
+
+func (a *CacheAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Purge deletes entries matching the filter (project, provider/model, locale, text query).
+// It returns the number of deleted entries.
+func (a *CacheAPI) Purge(f domain.CacheFilter) (int64, error) {
+	ctx := context.Background()
+	return a.repo.Purge(ctx, f)
+}
+
+// PurgeExpired deletes entries older than the configured TTL.
+func (a *CacheAPI) PurgeExpired() (int64, error) {
+	ctx := context.Background()
+	hours, err := a.GetTTLHours()
+	if err != nil || hours <= 0 {
+		return 0, err
+	}
+	return a.repo.PurgeOlderThan(ctx, time.Now().Add(-time.Duration(hours)*time.Hour))
+}
+
+// GetTTLHours returns the cache TTL in hours; 0 means entries never expire.
+func (a *CacheAPI) GetTTLHours() (int, error) {
+	ctx := context.Background()
+	v, err := a.settings.Get(ctx, translator.CacheTTLSettingKey)
+	if err != nil || strings.TrimSpace(v) == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(v))
+}
+
+func (a *CacheAPI) SetTTLHours(hours int) (bool, error) {
+	ctx := context.Background()
+	if hours < 0 {
+		return false, errors.New("ttl must be >= 0")
+	}
+	return true, a.settings.Set(ctx, translator.CacheTTLSettingKey, strconv.Itoa(hours))
+}
+
+type cacheDump struct {
+	Version int                  `json:"version"`
+	Entries []*domain.CacheEntry `json:"entries"`
+}
+
+type CacheExportResponse struct {
+	Filename   string `json:"filename"`
+	Entries    int    `json:"entries"`
+	ContentB64 string `json:"content_b64"`
+}
+
+// ExportBase64 dumps matching cache entries as JSON.
+func (a *CacheAPI) ExportBase64(f domain.CacheFilter) (CacheExportResponse, error) {
+	ctx := context.Background()
+	dump := cacheDump{Version: 1}
+	f.Limit, f.Offset = 1000, 0
+	for {
+		page, err := a.repo.List(ctx, f)
+		if err != nil {
+			return CacheExportResponse{}, err
+		}
+		dump.Entries = append(dump.Entries, page...)
+		if len(page) < f.Limit {
+			break
+		}
+		f.Offset += len(page)
+	}
+	b, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return CacheExportResponse{}, err
+	}
+	name := fmt.Sprintf("locail-cache-%s.json", time.Now().UTC().Format("20060102"))
+	return CacheExportResponse{Filename: name, Entries: len(dump.Entries), ContentB64: base64.StdEncoding.EncodeToString(b)}, nil
+}
+
+// ImportBase64 loads entries produced by ExportBase64. Existing entries with the same key are overwritten.
+func (a *CacheAPI) ImportBase64(contentB64 string) (int, error) {
+	ctx := context.Background()
+	b, err := base64.StdEncoding.DecodeString(contentB64)
+	if err != nil {
+		return 0, err
+	}
+	var dump cacheDump
+	if err := json.Unmarshal(b, &dump); err != nil {
+		return 0, fmt.Errorf("invalid cache dump: %w", err)
+	}
+	n := 0
+	for _, e := range dump.Entries {
+		if e == nil || e.SourceText == "" || e.Translation == "" {
+			continue
+		}
+		// Project ids are local to the exporting database.
+		e.ProjectID = nil
+		if err := a.repo.Put(ctx, e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
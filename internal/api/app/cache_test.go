@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/domain"
+)
+
+func newCacheAPI(t *testing.T) (*CacheAPI, *dbsqlite.CacheRepo, int64) {
+	t.Helper()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	p := &domain.Project{Name: "Game", SourceLang: "en"}
+	if err := dbsqlite.NewProjectRepo(db).Create(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	repo := dbsqlite.NewCacheRepo(db)
+	return NewCacheAPI(repo, dbsqlite.NewSettingsRepo(db)), repo, p.ID
+}
+
+func TestCacheExportImportPurge(t *testing.T) {
+	ctx := context.Background()
+	src, repo, pid := newCacheAPI(t)
+	entries := []*domain.CacheEntry{
+		{SourceText: "Hi __PH_0__", SrcLang: "en", TgtLang: "de", Provider: "openai", Model: "gpt-4o", TemplateVersion: "v1", Translation: "Hallo __PH_0__", ProjectID: &pid},
+		{SourceText: "Menu", SrcLang: "en", TgtLang: "de", Provider: "openai", Model: "gpt-4o", TemplateVersion: "v1", Translation: "Menü"},
+		{SourceText: "Menu", SrcLang: "en", TgtLang: "fr", Provider: "ollama", Model: "llama3", TemplateVersion: "v1", Translation: "Menu"},
+	}
+	for _, e := range entries {
+		if err := repo.Put(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exp, err := src.ExportBase64(domain.CacheFilter{Provider: "openai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.Entries != 2 {
+		t.Fatalf("exported %d entries, want 2", exp.Entries)
+	}
+
+	dst, dstRepo, _ := newCacheAPI(t)
+	n, err := dst.ImportBase64(exp.ContentB64)
+	if err != nil || n != 2 {
+		t.Fatalf("imported %d, %v", n, err)
+	}
+	// importing again overwrites instead of duplicating
+	if n, err := dst.ImportBase64(exp.ContentB64); err != nil || n != 2 {
+		t.Fatalf("re-import %d, %v", n, err)
+	}
+	got, err := dstRepo.Get(ctx, domain.CacheKey{SourceText: "Hi __PH_0__", SrcLang: "en", TgtLang: "de", Provider: "openai", Model: "gpt-4o", TemplateVersion: "v1"})
+	if err != nil || got == nil || got.Translation != "Hallo __PH_0__" {
+		t.Fatalf("imported entry %+v, %v", got, err)
+	}
+	if got.ProjectID != nil {
+		t.Errorf("project id %d survived the import", *got.ProjectID)
+	}
+	if stats, _ := dst.Stats(); stats.Entries != 2 {
+		t.Errorf("%d entries after import, want 2", stats.Entries)
+	}
+
+	if _, err := dst.ImportBase64("not base64!"); err == nil {
+		t.Error("invalid base64 accepted")
+	}
+
+	tests := []struct {
+		filter  domain.CacheFilter
+		deleted int64
+		left    int
+	}{
+		{domain.CacheFilter{TgtLang: "fr"}, 1, 2},
+		{domain.CacheFilter{ProjectID: &pid}, 1, 1},
+		{domain.CacheFilter{}, 1, 0},
+	}
+	for _, tt := range tests {
+		n, err := src.Purge(tt.filter)
+		if err != nil || n != tt.deleted {
+			t.Fatalf("Purge(%+v) = %d, %v, want %d", tt.filter, n, err, tt.deleted)
+		}
+		if stats, _ := src.Stats(); stats.Entries != tt.left {
+			t.Fatalf("Purge(%+v) left %d entries, want %d", tt.filter, stats.Entries, tt.left)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/glossary"
+	"path/filepath"
+)
+
+// GlossaryAPI manages project glossaries (termbases).
+type GlossaryAPI struct {
+	svc  *glossary.Service
+	repo ports.GlossaryRepository
+}
+
+func NewGlossaryAPI(svc *glossary.Service, repo ports.GlossaryRepository) *GlossaryAPI {
+	return &GlossaryAPI{svc: svc, repo: repo}
+}
+
+func (a *GlossaryAPI) List(projectID int64) ([]*domain.GlossaryTerm, error) {
+	ctx := context.Background()
+	return a.repo.ListByProject(ctx, projectID)
+}
+
+// Save creates a term, or updates it when ID is set. Translations are replaced.
+func (a *GlossaryAPI) Save(t domain.GlossaryTerm) (*domain.GlossaryTerm, error) {
+	ctx := context.Background()
+	if err := a.svc.Save(ctx, &t); err != nil {
+		return nil, err
+	}
+	return a.repo.Get(ctx, t.ID)
+}
+
+func (a *GlossaryAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type GlossaryImportRequest struct {
+	ProjectID  int64  `json:"project_id"`
+	Filename   string `json:"filename"`
+	Format     string `json:"format"` // tbx | csv; derived from filename when empty
+	SrcLang    string `json:"src_lang"`
+	ContentB64 string `json:"content_b64"`
+}
+
+// ImportBase64 loads a TBX or CSV termbase into a project glossary and returns the number of terms.
+func (a *GlossaryAPI) ImportBase64(req GlossaryImportRequest) (int, error) {
+	ctx := context.Background()
+	b, err := base64.StdEncoding.DecodeString(req.ContentB64)
+	if err != nil {
+		return 0, err
+	}
+	format := req.Format
+	if format == "" {
+		format = filepath.Ext(req.Filename)
+	}
+	return a.svc.Import(ctx, req.ProjectID, format, b, req.SrcLang)
+}
+
+type GlossaryCheckRequest struct {
+	UnitID int64  `json:"unit_id"`
+	Locale string `json:"locale"`
+	Text   string `json:"text"`
+}
+
+// Check returns glossary violations of a translation of a unit.
+func (a *GlossaryAPI) Check(req GlossaryCheckRequest) ([]domain.TermIssue, error) {
+	ctx := context.Background()
+	return a.svc.CheckUnit(ctx, req.UnitID, req.Locale, req.Text)
+}
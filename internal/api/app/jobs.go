@@ -2,8 +2,13 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"locail/internal/adapters/prompt"
+	"locail/internal/domain"
 	"locail/internal/ports"
 	"locail/internal/usecase/jobs"
+	"locail/internal/usecase/translator"
 	"time"
 )
 
@@ -20,6 +25,18 @@ type StartTranslateFileRequest struct {
 	FileID     int64    `json:"file_id"`
 	Locales    []string `json:"locales"`
 	Model      string   `json:"model"`
+	UseTM      bool     `json:"use_tm"`
+	// Mode is "per_key" (default) or "batch", which takes no prompt overrides.
+	Mode string `json:"mode"`
+	// SystemPrompt and UserPrompt are template bodies overriding the stored templates.
+	SystemPrompt string `json:"system_prompt"`
+	UserPrompt   string `json:"user_prompt"`
+	// Fallbacks are tried in order for items the provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks"`
+	// Options override the provider's options_json for this job.
+	Options json.RawMessage `json:"options"`
+	// Budget caps the job's cost in USD; 0 means no cap.
+	Budget float64 `json:"budget"`
 }
 
 type StartJobResponse struct {
@@ -28,7 +45,13 @@ type StartJobResponse struct {
 
 func (a *JobsAPI) StartTranslateFile(req StartTranslateFileRequest) (StartJobResponse, error) {
 	ctx := context.Background()
-	jid, err := a.r.StartTranslateFile(ctx, req.ProjectID, req.ProviderID, jobs.TranslateFileParams{FileID: req.FileID, TargetLocales: req.Locales, Model: req.Model})
+	if err := validatePromptOverrides(req.SystemPrompt, req.UserPrompt); err != nil {
+		return StartJobResponse{}, err
+	}
+	if err := validateFallbacks(req.Fallbacks); err != nil {
+		return StartJobResponse{}, err
+	}
+	jid, err := a.r.StartTranslateFile(ctx, req.ProjectID, req.ProviderID, fileParams(req))
 	if err != nil {
 		return StartJobResponse{}, err
 	}
@@ -42,12 +65,26 @@ type StartTranslateUnitRequest struct {
 	Locales    []string `json:"locales"`
 	Model      string   `json:"model"`
 	Force      bool     `json:"force"`
+	UseTM      bool     `json:"use_tm"`
+	// SystemPrompt and UserPrompt are template bodies overriding the stored templates.
+	SystemPrompt string `json:"system_prompt"`
+	UserPrompt   string `json:"user_prompt"`
+	// Fallbacks are tried in order for items the provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks"`
+	// Options override the provider's options_json for this job.
+	Options json.RawMessage `json:"options"`
 }
 
 func (a *JobsAPI) StartTranslateUnit(req StartTranslateUnitRequest) (StartJobResponse, error) {
 	ctx := context.Background()
+	if err := validatePromptOverrides(req.SystemPrompt, req.UserPrompt); err != nil {
+		return StartJobResponse{}, err
+	}
+	if err := validateFallbacks(req.Fallbacks); err != nil {
+		return StartJobResponse{}, err
+	}
 	// Always force re-translation from backend to ensure fresh output when requested from UI
-	jid, err := a.r.StartTranslateUnit(ctx, req.ProjectID, req.ProviderID, jobs.TranslateUnitParams{UnitID: req.UnitID, Locales: req.Locales, Model: req.Model, Force: true})
+	jid, err := a.r.StartTranslateUnit(ctx, req.ProjectID, req.ProviderID, jobs.TranslateUnitParams{UnitID: req.UnitID, Locales: req.Locales, Model: req.Model, Force: true, UseTM: req.UseTM, SystemPrompt: req.SystemPrompt, UserPrompt: req.UserPrompt, Fallbacks: req.Fallbacks, Options: req.Options})
 	if err != nil {
 		return StartJobResponse{}, err
 	}
@@ -61,12 +98,108 @@ type StartTranslateUnitsRequest struct {
 	Locales    []string `json:"locales"`
 	Model      string   `json:"model"`
 	Force      bool     `json:"force"`
+	UseTM      bool     `json:"use_tm"`
+	// Mode is "per_key" (default) or "batch", which takes no prompt overrides.
+	Mode string `json:"mode"`
+	// SystemPrompt and UserPrompt are template bodies overriding the stored templates.
+	SystemPrompt string `json:"system_prompt"`
+	UserPrompt   string `json:"user_prompt"`
+	// Fallbacks are tried in order for items the provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks"`
+	// Options override the provider's options_json for this job.
+	Options json.RawMessage `json:"options"`
+	// Budget caps the job's cost in USD; 0 means no cap.
+	Budget float64 `json:"budget"`
 }
 
 func (a *JobsAPI) StartTranslateUnits(req StartTranslateUnitsRequest) (StartJobResponse, error) {
 	ctx := context.Background()
+	if err := validatePromptOverrides(req.SystemPrompt, req.UserPrompt); err != nil {
+		return StartJobResponse{}, err
+	}
+	if err := validateFallbacks(req.Fallbacks); err != nil {
+		return StartJobResponse{}, err
+	}
+	jid, err := a.r.StartTranslateUnits(ctx, req.ProjectID, req.ProviderID, unitsParams(req))
+	if err != nil {
+		return StartJobResponse{}, err
+	}
+	return StartJobResponse{JobID: jid}, nil
+}
+
+func fileParams(req StartTranslateFileRequest) jobs.TranslateFileParams {
+	return jobs.TranslateFileParams{FileID: req.FileID, TargetLocales: req.Locales, Model: req.Model, UseTM: req.UseTM, Mode: req.Mode, SystemPrompt: req.SystemPrompt, UserPrompt: req.UserPrompt, Fallbacks: req.Fallbacks, Options: req.Options, Budget: req.Budget}
+}
+
+func unitsParams(req StartTranslateUnitsRequest) jobs.TranslateUnitsParams {
 	// Always force re-translation from backend to ensure fresh output when requested from UI
-	jid, err := a.r.StartTranslateUnits(ctx, req.ProjectID, req.ProviderID, jobs.TranslateUnitsParams{UnitIDs: req.UnitIDs, Locales: req.Locales, Model: req.Model, Force: true})
+	return jobs.TranslateUnitsParams{UnitIDs: req.UnitIDs, Locales: req.Locales, Model: req.Model, Force: true, UseTM: req.UseTM, Mode: req.Mode, SystemPrompt: req.SystemPrompt, UserPrompt: req.UserPrompt, Fallbacks: req.Fallbacks, Options: req.Options, Budget: req.Budget}
+}
+
+// EstimateTranslateFile predicts the segments, tokens, cost and duration of the job
+// StartTranslateFile would start for req, without starting it.
+func (a *JobsAPI) EstimateTranslateFile(req StartTranslateFileRequest) (jobs.Estimate, error) {
+	ctx := context.Background()
+	if err := validatePromptOverrides(req.SystemPrompt, req.UserPrompt); err != nil {
+		return jobs.Estimate{}, err
+	}
+	return a.r.EstimateTranslateFile(ctx, req.ProjectID, req.ProviderID, fileParams(req))
+}
+
+// EstimateTranslateUnits predicts the job StartTranslateUnits would start for req.
+func (a *JobsAPI) EstimateTranslateUnits(req StartTranslateUnitsRequest) (jobs.Estimate, error) {
+	ctx := context.Background()
+	if err := validatePromptOverrides(req.SystemPrompt, req.UserPrompt); err != nil {
+		return jobs.Estimate{}, err
+	}
+	return a.r.EstimateTranslateUnits(ctx, req.ProjectID, req.ProviderID, unitsParams(req))
+}
+
+// validatePromptOverrides rejects job template overrides that would fail on every item.
+func validatePromptOverrides(bodies ...string) error {
+	for _, b := range bodies {
+		if b == "" {
+			continue
+		}
+		if err := prompt.Validate(b); err != nil {
+			return fmt.Errorf("invalid prompt override: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateFallbacks(fallbacks []translator.Fallback) error {
+	for i, fb := range fallbacks {
+		if fb.ProviderID <= 0 {
+			return fmt.Errorf("fallback %d: provider_id is required", i+1)
+		}
+	}
+	return nil
+}
+
+type StartDetectLanguageRequest struct {
+	ProjectID     int64   `json:"project_id"`
+	ProviderID    int64   `json:"provider_id"`
+	FileID        int64   `json:"file_id"`
+	SampleSize    int     `json:"sample_size"`
+	Model         string  `json:"model"`
+	Mode          string  `json:"mode"`
+	MinConfidence float64 `json:"min_confidence"`
+	ApplyTo       string  `json:"apply_to"`
+}
+
+// StartDetectLanguage starts a job detecting the source language of a file.
+// ProviderID may be 0 to use only the offline detector.
+func (a *JobsAPI) StartDetectLanguage(req StartDetectLanguageRequest) (StartJobResponse, error) {
+	ctx := context.Background()
+	jid, err := a.r.StartDetectLanguage(ctx, req.ProjectID, req.ProviderID, jobs.DetectLanguageParams{
+		FileID:        req.FileID,
+		SampleSize:    req.SampleSize,
+		Model:         req.Model,
+		Mode:          req.Mode,
+		MinConfidence: req.MinConfidence,
+		ApplyTo:       req.ApplyTo,
+	})
 	if err != nil {
 		return StartJobResponse{}, err
 	}
@@ -82,6 +215,9 @@ type JobDTO struct {
 	Status   string `json:"status"`
 	Progress int    `json:"progress"`
 	Total    int    `json:"total"`
+	Result   string `json:"result_json,omitempty"`
+	// Usage sums the tokens and cost of the job's items; only Get fills it.
+	Usage *domain.Usage `json:"usage,omitempty"`
 }
 
 func (a *JobsAPI) Get(jobID int64) (*JobDTO, error) {
@@ -90,7 +226,11 @@ func (a *JobsAPI) Get(jobID int64) (*JobDTO, error) {
 	if err != nil || j == nil {
 		return nil, err
 	}
-	return &JobDTO{ID: j.ID, Type: j.Type, Status: j.Status, Progress: j.Progress, Total: j.Total}, nil
+	dto := &JobDTO{ID: j.ID, Type: j.Type, Status: j.Status, Progress: j.Progress, Total: j.Total, Result: j.ResultRaw}
+	if u, err := a.repo.Usage(ctx, jobID); err == nil && u.Items > 0 {
+		dto.Usage = &u
+	}
+	return dto, nil
 }
 
 func (a *JobsAPI) List(limit int) ([]*JobDTO, error) {
@@ -101,7 +241,7 @@ func (a *JobsAPI) List(limit int) ([]*JobDTO, error) {
 	}
 	out := make([]*JobDTO, 0, len(js))
 	for _, j := range js {
-		out = append(out, &JobDTO{ID: j.ID, Type: j.Type, Status: j.Status, Progress: j.Progress, Total: j.Total})
+		out = append(out, &JobDTO{ID: j.ID, Type: j.Type, Status: j.Status, Progress: j.Progress, Total: j.Total, Result: j.ResultRaw})
 	}
 	return out, nil
 }
@@ -112,6 +252,8 @@ type JobItemDTO struct {
 	Locale *string `json:"locale"`
 	Status string  `json:"status"`
 	Error  string  `json:"error"`
+	// Meta records how the item was produced (e.g. few-shot examples used).
+	Meta string `json:"meta_json,omitempty"`
 }
 
 func (a *JobsAPI) Items(jobID int64) ([]*JobItemDTO, error) {
@@ -122,7 +264,7 @@ func (a *JobsAPI) Items(jobID int64) ([]*JobItemDTO, error) {
 	}
 	out := make([]*JobItemDTO, 0, len(items))
 	for _, it := range items {
-		out = append(out, &JobItemDTO{ID: it.ID, UnitID: it.UnitID, Locale: it.Locale, Status: it.Status, Error: it.Error})
+		out = append(out, &JobItemDTO{ID: it.ID, UnitID: it.UnitID, Locale: it.Locale, Status: it.Status, Error: it.Error, Meta: it.MetaRaw})
 	}
 	return out, nil
 }
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/ports"
+)
+
+// NetworkSettingKey holds the global network settings as JSON.
+const NetworkSettingKey = "network"
+
+// NetworkAPI edits the global proxy and TLS settings that providers fall back to.
+type NetworkAPI struct {
+	settings ports.SettingsRepository
+}
+
+func NewNetworkAPI(settings ports.SettingsRepository) *NetworkAPI {
+	return &NetworkAPI{settings: settings}
+}
+
+// LoadNetworkDefaults applies the stored global network settings; called at startup.
+func LoadNetworkDefaults(ctx context.Context, settings ports.SettingsRepository) error {
+	raw, err := settings.Get(ctx, NetworkSettingKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var n httpclient.Network
+	if err := json.Unmarshal([]byte(raw), &n); err != nil {
+		return err
+	}
+	httpclient.SetDefaultNetwork(n)
+	return nil
+}
+
+func (a *NetworkAPI) Get() httpclient.Network { return httpclient.DefaultNetwork() }
+
+// NetworkResult reports saved settings; Warning is set when they weaken TLS.
+type NetworkResult struct {
+	Ok      bool   `json:"ok"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// Set validates, stores and applies the global settings. Providers built afterwards use them.
+func (a *NetworkAPI) Set(n httpclient.Network) (NetworkResult, error) {
+	ctx := context.Background()
+	if err := n.Validate(); err != nil {
+		return NetworkResult{}, err
+	}
+	raw, err := json.Marshal(n)
+	if err != nil {
+		return NetworkResult{}, err
+	}
+	if err := a.settings.Set(ctx, NetworkSettingKey, string(raw)); err != nil {
+		return NetworkResult{}, err
+	}
+	httpclient.SetDefaultNetwork(n)
+	res := NetworkResult{Ok: true}
+	if n.Insecure() {
+		res.Warning = httpclient.InsecureWarning
+	}
+	return res, nil
+}
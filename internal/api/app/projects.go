@@ -2,16 +2,24 @@ package app
 
 import (
 	"context"
+	"errors"
 	"locail/internal/domain"
 	"locail/internal/ports"
+	"locail/internal/usecase/translator"
 )
 
 type ProjectAPI struct {
-	repo ports.ProjectRepository
+	repo     ports.ProjectRepository
+	settings ports.SettingsRepository
 }
 
 func NewProjectAPI(repo ports.ProjectRepository) *ProjectAPI { return &ProjectAPI{repo: repo} }
 
+// NewProjectAPIWithSettings also exposes per-project prompt settings.
+func NewProjectAPIWithSettings(repo ports.ProjectRepository, settings ports.SettingsRepository) *ProjectAPI {
+	return &ProjectAPI{repo: repo, settings: settings}
+}
+
 func (a *ProjectAPI) Create(name, sourceLang string) (*domain.Project, error) {
 	ctx := context.Background()
 	p := &domain.Project{Name: name, SourceLang: sourceLang}
@@ -58,3 +66,21 @@ func (a *ProjectAPI) ListLocales(projectID int64) ([]*domain.ProjectLocale, erro
 	ctx := context.Background()
 	return a.repo.ListLocales(ctx, projectID)
 }
+
+// GetFewShot returns the project's few-shot example settings.
+func (a *ProjectAPI) GetFewShot(projectID int64) (translator.FewShotConfig, error) {
+	ctx := context.Background()
+	return translator.LoadFewShotConfig(ctx, a.settings, projectID), nil
+}
+
+// SetFewShot updates the project's few-shot example settings (K, similarity threshold, opt-out).
+func (a *ProjectAPI) SetFewShot(projectID int64, cfg translator.FewShotConfig) (bool, error) {
+	ctx := context.Background()
+	if a.settings == nil {
+		return false, errors.New("settings repository not configured")
+	}
+	if err := translator.SaveFewShotConfig(ctx, a.settings, projectID, cfg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"regexp"
+	"strings"
+)
+
+// ProtectedTermsAPI manages per-project do-not-translate terms.
+type ProtectedTermsAPI struct {
+	repo ports.ProtectedTermRepository
+}
+
+func NewProtectedTermsAPI(repo ports.ProtectedTermRepository) *ProtectedTermsAPI {
+	return &ProtectedTermsAPI{repo: repo}
+}
+
+func (a *ProtectedTermsAPI) List(projectID int64) ([]*domain.ProtectedTerm, error) {
+	ctx := context.Background()
+	return a.repo.ListByProject(ctx, projectID)
+}
+
+type AddProtectedTermRequest struct {
+	ProjectID int64  `json:"project_id"`
+	Pattern   string `json:"pattern"`
+	IsRegex   bool   `json:"is_regex"`
+	Note      string `json:"note"`
+}
+
+func (a *ProtectedTermsAPI) Add(req AddProtectedTermRequest) (*domain.ProtectedTerm, error) {
+	ctx := context.Background()
+	if req.ProjectID == 0 {
+		return nil, errors.New("project_id is required")
+	}
+	if strings.TrimSpace(req.Pattern) == "" {
+		return nil, errors.New("pattern is required")
+	}
+	if req.IsRegex {
+		re, err := regexp.Compile(req.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		if re.MatchString("") {
+			return nil, errors.New("regular expression must not match empty text")
+		}
+	}
+	t := &domain.ProtectedTerm{ProjectID: req.ProjectID, Pattern: req.Pattern, IsRegex: req.IsRegex, Note: req.Note}
+	if err := a.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (a *ProtectedTermsAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Test returns the protected strings the project's rules find in text.
+func (a *ProtectedTermsAPI) Test(projectID int64, text string) ([]string, error) {
+	ctx := context.Background()
+	terms, err := a.repo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	seen := map[string]struct{}{}
+	for _, re := range translator.CompileProtected(terms) {
+		for _, m := range re.FindAllString(text, -1) {
+			if _, ok := seen[m]; !ok && m != "" {
+				seen[m] = struct{}{}
+				out = append(out, m)
+			}
+		}
+	}
+	return out, nil
+}
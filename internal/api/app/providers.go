@@ -3,9 +3,13 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"locail/internal/adapters/llm/factory"
+	"locail/internal/adapters/llm/httpclient"
+	"locail/internal/adapters/llm/registry"
 	"locail/internal/domain"
 	"locail/internal/ports"
+	"locail/internal/usecase/jobs"
 	"strings"
 )
 
@@ -20,6 +24,12 @@ func (a *ProviderAPI) Create(p domain.Provider) (*domain.Provider, error) {
 	if p.Type == "" || p.Name == "" {
 		return nil, errors.New("type and name are required")
 	}
+	if err := normalizeConcurrency(&p); err != nil {
+		return nil, err
+	}
+	if err := validateProvider(&p); err != nil {
+		return nil, err
+	}
 	// Normalize model identifiers where needed (e.g., OpenRouter)
 	_ = a.normalizeModel(ctx, &p)
 	if err := a.repo.Create(ctx, &p); err != nil {
@@ -35,13 +45,15 @@ func (a *ProviderAPI) Update(p domain.Provider) (*domain.Provider, error) {
 	if p.ID == 0 {
 		return nil, errors.New("id is required")
 	}
-	// Preserve existing API key if masked or empty provided from UI
-	if strings.HasPrefix(p.APIKey, "****") || p.APIKey == "" {
-		existing, err := a.repo.Get(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		p.APIKey = existing.APIKey
+	if err := normalizeConcurrency(&p); err != nil {
+		return nil, err
+	}
+	// a masked or empty key from the UI keeps the stored one without reading it
+	if p.APIKey == "" {
+		p.APIKey = domain.MaskedKeyPrefix
+	}
+	if err := validateProvider(&p); err != nil {
+		return nil, err
 	}
 	// Normalize model identifiers where needed (e.g., OpenRouter)
 	_ = a.normalizeModel(ctx, &p)
@@ -64,9 +76,47 @@ func (a *ProviderAPI) List() ([]*domain.Provider, error) {
 	return list, nil
 }
 
+// maxConcurrency bounds the parallel requests per provider.
+const maxConcurrency = 32
+
+func normalizeConcurrency(p *domain.Provider) error {
+	if p.Concurrency == 0 {
+		p.Concurrency = jobs.DefaultConcurrency
+	}
+	if p.Concurrency < 1 || p.Concurrency > maxConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", maxConcurrency)
+	}
+	return nil
+}
+
+// Types lists the registered provider types; the provider form is built from them.
+func (a *ProviderAPI) Types() []registry.Descriptor { return registry.Descriptors() }
+
+// validateProvider checks the record against its type's descriptor: the type is registered,
+// required fields are set and options_json holds valid values, including loadable network
+// settings.
+func validateProvider(p *domain.Provider) error {
+	d, ok := registry.Lookup(p.Type)
+	if !ok {
+		return fmt.Errorf("unsupported provider type %q", p.Type)
+	}
+	values := map[string]string{"base_url": p.BaseURL, "api_key": p.APIKey, "model": p.Model}
+	for _, f := range d.Fields {
+		if f.Required && strings.TrimSpace(values[f.Name]) == "" {
+			return fmt.Errorf("%s is required for %s", strings.ToLower(f.Label), d.Label)
+		}
+	}
+	if err := registry.ValidateOptions(d, p.OptionsRaw); err != nil {
+		return err
+	}
+	return httpclient.NetworkOf(p.OptionsRaw).Validate()
+}
+
 type ModelInfo struct {
 	Name, Description string
 	ContextTokens     int
+	// InputPrice and OutputPrice are USD per million tokens; zero when unknown.
+	InputPrice, OutputPrice float64
 }
 
 func (a *ProviderAPI) ListModels(id int64) ([]ModelInfo, error) {
@@ -83,15 +133,13 @@ func (a *ProviderAPI) ListModels(id int64) ([]ModelInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	out := make([]ModelInfo, 0, len(models))
+	// keep the cached list and its prices current for cost accounting
+	cache := make([]*domain.ProviderModel, 0, len(models))
 	for _, m := range models {
-		out = append(out, ModelInfo{
-			Name:          m.Name,
-			Description:   m.Description,
-			ContextTokens: m.ContextTokens,
-		})
+		cache = append(cache, &domain.ProviderModel{ProviderID: id, Name: m.Name, ContextTokens: m.ContextTokens, InputPrice: m.InputPrice, OutputPrice: m.OutputPrice})
 	}
-	return out, nil
+	_ = a.repo.SaveModelCache(ctx, id, cache)
+	return modelInfos(models), nil
 }
 
 // ProviderTestResult contains details of a connectivity/translate test.
@@ -100,6 +148,8 @@ type ProviderTestResult struct {
 	Translation string `json:"translation,omitempty"`
 	Raw         string `json:"raw,omitempty"`
 	Error       string `json:"error,omitempty"`
+	// Warning is set when the provider's network settings disable TLS verification.
+	Warning string `json:"warning,omitempty"`
 }
 
 // Test performs a live translation of a simple phrase to validate a provider.
@@ -135,16 +185,22 @@ func (a *ProviderAPI) Test(id int64) (ProviderTestResult, error) {
 		SystemPrompt: system,
 		UserPrompt:   user,
 	})
+	var warning string
+	if httpclient.NetworkOf(p.OptionsRaw).Insecure() {
+		warning = httpclient.InsecureWarning
+	}
 	if trErr != nil {
 		return ProviderTestResult{
-			Ok:    false,
-			Error: trErr.Error(),
+			Ok:      false,
+			Error:   trErr.Error(),
+			Warning: warning,
 		}, nil
 	}
 	return ProviderTestResult{
 		Ok:          true,
 		Translation: res.Translation,
 		Raw:         res.Raw,
+		Warning:     warning,
 	}, nil
 }
 
@@ -165,7 +221,15 @@ func (a *ProviderAPI) normalizeModel(ctx context.Context, p *domain.Provider) er
 	if !strings.Contains(m, " ") && !strings.Contains(m, "(") && !strings.Contains(m, ")") {
 		return nil // likely already an ID
 	}
-	prov, ok := factory.FromProvider(p)
+	q := *p
+	if domain.IsMaskedKey(q.APIKey) {
+		existing, err := a.repo.Get(ctx, q.ID)
+		if err != nil {
+			return err
+		}
+		q.APIKey = existing.APIKey
+	}
+	prov, ok := factory.FromProvider(&q)
 	if !ok {
 		return nil
 	}
@@ -194,6 +258,12 @@ func (a *ProviderAPI) Delete(id int64) (bool, error) {
 // without persisting it. Useful for configuring a provider before saving.
 func (a *ProviderAPI) ListModelsPreview(p domain.Provider) ([]ModelInfo, error) {
 	ctx := context.Background()
+	// the form of a saved provider only holds its masked key
+	if p.ID != 0 {
+		if err := a.restoreKey(ctx, &p); err != nil {
+			return nil, err
+		}
+	}
 	prov, ok := factory.FromProvider(&p)
 	if !ok {
 		return nil, errors.New("unsupported provider type")
@@ -202,20 +272,44 @@ func (a *ProviderAPI) ListModelsPreview(p domain.Provider) ([]ModelInfo, error)
 	if err != nil {
 		return nil, err
 	}
+	return modelInfos(models), nil
+}
+
+func modelInfos(models []ports.ModelInfo) []ModelInfo {
 	out := make([]ModelInfo, 0, len(models))
 	for _, m := range models {
 		out = append(out, ModelInfo{
 			Name:          m.Name,
 			Description:   m.Description,
 			ContextTokens: m.ContextTokens,
+			InputPrice:    m.InputPrice,
+			OutputPrice:   m.OutputPrice,
 		})
 	}
-	return out, nil
+	return out
+}
+
+// restoreKey replaces a masked or empty key sent back by the UI with the stored one.
+func (a *ProviderAPI) restoreKey(ctx context.Context, p *domain.Provider) error {
+	if !domain.IsMaskedKey(p.APIKey) && p.APIKey != "" {
+		return nil
+	}
+	existing, err := a.repo.Get(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	p.APIKey = existing.APIKey
+	return nil
 }
 
+// mask hides an API key for the UI. Only the last 4 characters of long keys are shown;
+// short keys are hidden entirely.
 func mask(s string) string {
-	if len(s) <= 4 {
+	switch {
+	case s == "", domain.IsMaskedKey(s):
 		return s
+	case len(s) <= 12:
+		return domain.MaskedKeyPrefix
 	}
-	return "****" + s[len(s)-4:]
+	return domain.MaskedKeyPrefix + s[len(s)-4:]
 }
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"locail/internal/adapters/secrets"
+)
+
+// SecretsAPI reports how API keys are stored and unlocks the passphrase vault.
+type SecretsAPI struct {
+	store     *secrets.Store
+	providers *secrets.ProviderRepo
+}
+
+func NewSecretsAPI(store *secrets.Store, providers *secrets.ProviderRepo) *SecretsAPI {
+	return &SecretsAPI{store: store, providers: providers}
+}
+
+// SecretsStatus describes API key storage. Locked is only set in passphrase mode; there,
+// Initialized is false until the first Unlock sets the passphrase.
+type SecretsStatus struct {
+	Mode        string `json:"mode"`
+	Initialized bool   `json:"initialized"`
+	Locked      bool   `json:"locked"`
+	// Plaintext counts providers whose key is still stored unencrypted.
+	Plaintext int `json:"plaintext"`
+}
+
+func (a *SecretsAPI) Status() (SecretsStatus, error) {
+	ctx := context.Background()
+	st := SecretsStatus{Mode: a.store.Mode(), Initialized: true}
+	if st.Mode == secrets.ModePassphrase {
+		var err error
+		if st.Initialized, err = a.store.Vault().Initialized(ctx); err != nil {
+			return st, err
+		}
+		st.Locked = a.store.Vault().Locked()
+	}
+	n, err := a.providers.Plaintext(ctx)
+	st.Plaintext = n
+	return st, err
+}
+
+// Unlock opens the passphrase vault for this session, setting the passphrase on first use,
+// and encrypts keys still stored in plaintext.
+func (a *SecretsAPI) Unlock(passphrase string) (SecretsStatus, error) {
+	ctx := context.Background()
+	if err := a.store.Vault().Unlock(ctx, passphrase); err != nil {
+		return SecretsStatus{}, err
+	}
+	if _, err := a.providers.SealPlaintext(ctx); err != nil {
+		return SecretsStatus{}, err
+	}
+	return a.Status()
+}
+
+// Lock forgets the vault key; providers with encrypted keys fail until the next Unlock.
+func (a *SecretsAPI) Lock() (SecretsStatus, error) {
+	a.store.Vault().Lock()
+	return a.Status()
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"strings"
+)
+
+// StyleGuidesAPI manages per-project and per-locale style guides.
+type StyleGuidesAPI struct {
+	repo ports.StyleGuideRepository
+}
+
+func NewStyleGuidesAPI(repo ports.StyleGuideRepository) *StyleGuidesAPI {
+	return &StyleGuidesAPI{repo: repo}
+}
+
+func (a *StyleGuidesAPI) List(projectID int64) ([]*domain.StyleGuide, error) {
+	ctx := context.Background()
+	return a.repo.ListByProject(ctx, projectID)
+}
+
+// Save stores the guide for its project and locale; an empty locale applies to all locales.
+func (a *StyleGuidesAPI) Save(g domain.StyleGuide) (*domain.StyleGuide, error) {
+	ctx := context.Background()
+	if g.ProjectID == 0 {
+		return nil, errors.New("project_id is required")
+	}
+	g.Locale = strings.TrimSpace(g.Locale)
+	if err := a.repo.Upsert(ctx, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (a *StyleGuidesAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Effective returns the merged guide used in prompts for a target locale.
+func (a *StyleGuidesAPI) Effective(projectID int64, locale string) (domain.StyleGuide, error) {
+	ctx := context.Background()
+	guides, err := a.repo.ListByProject(ctx, projectID)
+	if err != nil {
+		return domain.StyleGuide{}, err
+	}
+	return translator.ResolveStyle(guides, locale), nil
+}
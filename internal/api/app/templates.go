@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/adapters/prompt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"strings"
+)
+
+// TemplatesAPI backs the prompt editor: templates per scope, validation, preview and history.
+type TemplatesAPI struct {
+	repo  ports.TemplateRepository
+	units ports.UnitRepository
+	trans *translator.Service
+}
+
+func NewTemplatesAPI(repo ports.TemplateRepository, units ports.UnitRepository, trans *translator.Service) *TemplatesAPI {
+	return &TemplatesAPI{repo: repo, units: units, trans: trans}
+}
+
+// List returns templates of a scope ("" for all); refID narrows project/provider scopes.
+func (a *TemplatesAPI) List(scope string, refID *int64) ([]*domain.Template, error) {
+	ctx := context.Background()
+	return a.repo.List(ctx, scope, refID)
+}
+
+func (a *TemplatesAPI) Get(id int64) (*domain.Template, error) {
+	ctx := context.Background()
+	return a.repo.Get(ctx, id)
+}
+
+// Save validates and stores a template, keeping the previous body in its history.
+func (a *TemplatesAPI) Save(t domain.Template) (*domain.Template, error) {
+	ctx := context.Background()
+	if err := validateTemplateKey(t.Scope, t.RefID, t.Type, t.Role); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(t.Body) == "" {
+		return nil, errors.New("template body is empty")
+	}
+	if err := prompt.Validate(t.Body); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	t.IsDefault = false
+	if err := a.repo.Upsert(ctx, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (a *TemplatesAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Versions returns previous bodies of a template, newest first.
+func (a *TemplatesAPI) Versions(id int64) ([]*domain.TemplateVersion, error) {
+	ctx := context.Background()
+	return a.repo.ListVersions(ctx, id)
+}
+
+type TemplatePreviewRequest struct {
+	Type   string `json:"type"`
+	Role   string `json:"role"`
+	Body   string `json:"body"`
+	UnitID int64  `json:"unit_id"`
+	Locale string `json:"locale"`
+}
+
+// Preview renders a (possibly unsaved) body with the prompt data of a real unit.
+func (a *TemplatesAPI) Preview(req TemplatePreviewRequest) (string, error) {
+	ctx := context.Background()
+	body := req.Body
+	if strings.TrimSpace(body) == "" {
+		body = prompt.Builtin(req.Type, req.Role)
+	}
+	u, err := a.units.Get(ctx, req.UnitID)
+	if err != nil {
+		return "", err
+	}
+	data, err := a.trans.PromptData(ctx, u, req.Locale)
+	if err != nil {
+		return "", err
+	}
+	return prompt.Execute(body, data)
+}
+
+// Reset restores the builtin default for a global template and removes project or
+// provider overrides so the next level applies again.
+func (a *TemplatesAPI) Reset(scope string, refID *int64, typ, role string) (*domain.Template, error) {
+	ctx := context.Background()
+	if err := validateTemplateKey(scope, refID, typ, role); err != nil {
+		return nil, err
+	}
+	if scope != "global" {
+		list, err := a.repo.List(ctx, scope, refID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range list {
+			if t.Type == typ && t.Role == role {
+				if err := a.repo.Delete(ctx, t.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return nil, nil
+	}
+	body := prompt.Builtin(typ, role)
+	if body == "" {
+		return nil, fmt.Errorf("no builtin template for %s/%s", typ, role)
+	}
+	t := domain.Template{Scope: "global", Type: typ, Role: role, Body: body, IsDefault: true}
+	if err := a.repo.Upsert(ctx, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func validateTemplateKey(scope string, refID *int64, typ, role string) error {
+	switch scope {
+	case "global":
+		if refID != nil {
+			return errors.New("global templates have no ref_id")
+		}
+	case "project", "provider":
+		if refID == nil || *refID == 0 {
+			return fmt.Errorf("ref_id is required for %s templates", scope)
+		}
+	default:
+		return fmt.Errorf("unknown template scope %q", scope)
+	}
+	switch typ {
+	case "translate_single", "translate_file", "detect_language":
+	default:
+		return fmt.Errorf("unknown template type %q", typ)
+	}
+	if role != "system" && role != "user" {
+		return fmt.Errorf("unknown template role %q", role)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/adapters/prompt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+func newTemplatesAPI(t *testing.T) (*TemplatesAPI, *dbsqlite.TemplateRepo, int64) {
+	t.Helper()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	p := &domain.Project{Name: "Game", SourceLang: "en"}
+	if err := dbsqlite.NewProjectRepo(db).Create(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	repo := dbsqlite.NewTemplateRepo(db)
+	return NewTemplatesAPI(repo, nil, nil), repo, p.ID
+}
+
+func TestTemplatesSaveValidates(t *testing.T) {
+	a, _, pid := newTemplatesAPI(t)
+	tests := []struct {
+		name string
+		t    domain.Template
+		err  string
+	}{
+		{"unknown scope", domain.Template{Scope: "team", Type: "translate_single", Role: "user", Body: "x"}, "unknown template scope"},
+		{"global with ref", domain.Template{Scope: "global", RefID: &pid, Type: "translate_single", Role: "user", Body: "x"}, "no ref_id"},
+		{"project without ref", domain.Template{Scope: "project", Type: "translate_single", Role: "user", Body: "x"}, "ref_id is required"},
+		{"unknown type", domain.Template{Scope: "global", Type: "summarize", Role: "user", Body: "x"}, "unknown template type"},
+		{"unknown role", domain.Template{Scope: "global", Type: "translate_single", Role: "assistant", Body: "x"}, "unknown template role"},
+		{"empty body", domain.Template{Scope: "global", Type: "translate_single", Role: "user", Body: " \n"}, "body is empty"},
+		{"syntax error", domain.Template{Scope: "global", Type: "translate_single", Role: "user", Body: "{{.Text"}, "invalid template"},
+		{"unknown field", domain.Template{Scope: "global", Type: "translate_single", Role: "user", Body: "{{.Txt}}"}, "invalid template"},
+	}
+	for _, tt := range tests {
+		if _, err := a.Save(tt.t); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestTemplatesVersionsAndReset(t *testing.T) {
+	ctx := context.Background()
+	a, repo, pid := newTemplatesAPI(t)
+	if err := prompt.SeedDefaults(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	builtin := prompt.Builtin("translate_single", "user")
+	var saved *domain.Template
+	for _, body := range []string{"first {{.Text}}", "second {{.Text}}"} {
+		var err error
+		if saved, err = a.Save(domain.Template{Scope: "global", Type: "translate_single", Role: "user", Body: body, IsDefault: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if saved.IsDefault {
+		t.Fatal("a saved template is still the default")
+	}
+	versions, err := a.Versions(saved.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the oldest version is the default of migration 002 that seeding replaced
+	if len(versions) != 3 || versions[0].Body != "first {{.Text}}" || versions[1].Body != builtin {
+		t.Fatalf("%d versions, want the first body, then the builtin", len(versions))
+	}
+
+	// a project override is removed by its reset
+	if _, err := a.Save(domain.Template{Scope: "project", RefID: &pid, Type: "translate_single", Role: "user", Body: "project {{.Text}}"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := a.Reset("project", &pid, "translate_single", "user"); err != nil || got != nil {
+		t.Fatalf("project reset: %+v, %v", got, err)
+	}
+	eff, err := repo.GetEffective(ctx, ports.TemplateScope{ProjectID: &pid}, "translate_single", "user")
+	if err != nil || eff.Scope != "global" {
+		t.Fatalf("effective %+v, %v, want the global template", eff, err)
+	}
+
+	// the global reset restores the builtin and keeps the edited body
+	reset, err := a.Reset("global", nil, "translate_single", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset.ID != saved.ID || reset.Body != builtin || !reset.IsDefault {
+		t.Fatalf("reset %+v", reset)
+	}
+	if versions, _ = a.Versions(saved.ID); len(versions) != 4 || versions[0].Body != "second {{.Text}}" {
+		t.Fatalf("%d versions, want the second body kept", len(versions))
+	}
+	if _, err := a.Reset("global", nil, "translate_single", "assistant"); err == nil {
+		t.Fatal("reset an unknown role")
+	}
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/tm"
+	"time"
+)
+
+// TMAPI exposes the translation memory.
+type TMAPI struct {
+	svc  *tm.Service
+	repo ports.TMRepository
+}
+
+func NewTMAPI(svc *tm.Service, repo ports.TMRepository) *TMAPI { return &TMAPI{svc: svc, repo: repo} }
+
+// Suggest returns fuzzy memory matches for a unit in the target locale, best first.
+func (a *TMAPI) Suggest(unitID int64, locale string) ([]domain.TMMatch, error) {
+	ctx := context.Background()
+	return a.svc.Suggest(ctx, unitID, locale, 0, 0)
+}
+
+type TMLookupRequest struct {
+	SrcLang    string `json:"src_lang"`
+	TgtLang    string `json:"tgt_lang"`
+	Text       string `json:"text"`
+	MinScore   int    `json:"min_score"`
+	MaxResults int    `json:"max_results"`
+}
+
+// Lookup searches the memory for arbitrary text.
+func (a *TMAPI) Lookup(req TMLookupRequest) ([]domain.TMMatch, error) {
+	ctx := context.Background()
+	return a.svc.Lookup(ctx, tm.LookupArgs{
+		SrcLang:    req.SrcLang,
+		TgtLang:    req.TgtLang,
+		Text:       req.Text,
+		MinScore:   req.MinScore,
+		MaxResults: req.MaxResults,
+	})
+}
+
+// Rebuild re-imports all reviewed/approved translations and returns the number of entries written.
+func (a *TMAPI) Rebuild() (int, error) {
+	ctx := context.Background()
+	return a.svc.Rebuild(ctx)
+}
+
+func (a *TMAPI) List(f domain.TMFilter) ([]*domain.TMEntry, error) {
+	ctx := context.Background()
+	return a.repo.List(ctx, f)
+}
+
+func (a *TMAPI) Count() (int, error) {
+	ctx := context.Background()
+	return a.repo.Count(ctx)
+}
+
+func (a *TMAPI) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	if err := a.repo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type TMXExportResponse struct {
+	Filename   string `json:"filename"`
+	Entries    int    `json:"entries"`
+	ContentB64 string `json:"content_b64"`
+}
+
+// ExportTMXBase64 exports memory entries matching the filter (project, locale pair and statuses,
+// approved by default) as TMX 1.4b.
+func (a *TMAPI) ExportTMXBase64(f domain.TMFilter) (TMXExportResponse, error) {
+	ctx := context.Background()
+	b, n, err := a.svc.ExportTMX(ctx, f)
+	if err != nil {
+		return TMXExportResponse{}, err
+	}
+	name := "locail-memory"
+	if f.SrcLang != "" && f.TgtLang != "" {
+		name += "-" + f.SrcLang + "-" + f.TgtLang
+	}
+	name += "-" + time.Now().UTC().Format("20060102") + ".tmx"
+	return TMXExportResponse{Filename: name, Entries: n, ContentB64: base64.StdEncoding.EncodeToString(b)}, nil
+}
+
+// ImportTMXBase64 loads a TMX document into the memory and returns the number of imported pairs.
+func (a *TMAPI) ImportTMXBase64(contentB64 string) (int, error) {
+	ctx := context.Background()
+	b, err := base64.StdEncoding.DecodeString(contentB64)
+	if err != nil {
+		return 0, err
+	}
+	return a.svc.ImportTMX(ctx, b)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"locail/internal/domain"
 	"locail/internal/ports"
+	"locail/internal/usecase/tm"
 )
 
 type TranslationsAPI struct {
-	repo  ports.TranslationRepository
-	units ports.UnitRepository
+	repo   ports.TranslationRepository
+	units  ports.UnitRepository
+	memory *tm.Service
 }
 
 func NewTranslationsAPI(repo ports.TranslationRepository) *TranslationsAPI {
@@ -19,6 +21,11 @@ func NewTranslationsAPIWithUnits(repo ports.TranslationRepository, units ports.U
 	return &TranslationsAPI{repo: repo, units: units}
 }
 
+// NewTranslationsAPIWithMemory also records reviewed/approved edits in the translation memory.
+func NewTranslationsAPIWithMemory(repo ports.TranslationRepository, units ports.UnitRepository, memory *tm.Service) *TranslationsAPI {
+	return &TranslationsAPI{repo: repo, units: units, memory: memory}
+}
+
 type UpsertTranslationRequest struct {
 	UnitID     int64  `json:"unit_id"`
 	Locale     string `json:"locale"`
@@ -36,7 +43,15 @@ func (a *TranslationsAPI) Upsert(req UpsertTranslationRequest) (bool, error) {
 		Status:     req.Status,
 		ProviderID: req.ProviderID,
 	}
-	return true, a.repo.Upsert(ctx, t)
+	if err := a.repo.Upsert(ctx, t); err != nil {
+		return false, err
+	}
+	if a.memory != nil {
+		if err := a.memory.Record(ctx, t.UnitID, t.Locale, t.Text, t.Status); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
 }
 
 type FileLocaleTranslationsRequest struct {
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/usecase/usage"
+	"regexp"
+)
+
+// UsageAPI reports token usage and cost of translations.
+type UsageAPI struct {
+	svc *usage.Service
+}
+
+func NewUsageAPI(svc *usage.Service) *UsageAPI { return &UsageAPI{svc: svc} }
+
+// UsageReport is the rows of a usage report and their total.
+type UsageReport struct {
+	Rows  []*domain.UsageRow `json:"rows"`
+	Total domain.Usage       `json:"total"`
+}
+
+var monthRe = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// Report sums token usage and cost of translated job items, grouped by month, project,
+// provider and model unless the query names fewer groups.
+func (a *UsageAPI) Report(q domain.UsageQuery) (UsageReport, error) {
+	ctx := context.Background()
+	for _, m := range []string{q.FromMonth, q.ToMonth} {
+		if m != "" && !monthRe.MatchString(m) {
+			return UsageReport{}, fmt.Errorf("invalid month %q, want YYYY-MM", m)
+		}
+	}
+	rows, err := a.svc.Report(ctx, q)
+	if err != nil {
+		return UsageReport{}, err
+	}
+	rep := UsageReport{Rows: rows}
+	for _, r := range rows {
+		rep.Total.Items += r.Items
+		rep.Total.InputTokens += r.InputTokens
+		rep.Total.OutputTokens += r.OutputTokens
+		rep.Total.Cost += r.Cost
+		rep.Total.Unpriced += r.Unpriced
+	}
+	if rep.Rows == nil {
+		rep.Rows = []*domain.UsageRow{}
+	}
+	return rep, nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// CacheEntry is a cached machine translation. SourceText and Translation are
+// stored in their masked form (placeholders/tags replaced by tokens).
+type CacheEntry struct {
+	ID              int64      `json:"id"`
+	SourceText      string     `json:"source_text"`
+	SrcLang         string     `json:"src_lang"`
+	TgtLang         string     `json:"tgt_lang"`
+	Provider        string     `json:"provider"`
+	Model           string     `json:"model"`
+	TemplateVersion string     `json:"template_version"`
+	ContextHash     string     `json:"context_hash"`
+	Translation     string     `json:"translation"`
+	ProjectID       *int64     `json:"project_id"`
+	Hits            int        `json:"hits"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+}
+
+// CacheKey identifies a cache entry. Values are expected to be normalized by the caller.
+type CacheKey struct {
+	SourceText      string
+	SrcLang         string
+	TgtLang         string
+	Provider        string
+	Model           string
+	TemplateVersion string
+	ContextHash     string
+}
+
+// CacheFilter narrows cache listing and purging. Zero values match everything.
+type CacheFilter struct {
+	ProjectID *int64 `json:"project_id"`
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	TgtLang   string `json:"tgt_lang"`
+	Query     string `json:"query"`
+	Limit     int    `json:"limit"`
+	Offset    int    `json:"offset"`
+}
+
+type CacheBucket struct {
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	Hits    int    `json:"hits"`
+}
+
+type CacheStats struct {
+	Entries  int           `json:"entries"`
+	Hits     int           `json:"hits"`
+	Oldest   *time.Time    `json:"oldest"`
+	Newest   *time.Time    `json:"newest"`
+	ByModel  []CacheBucket `json:"by_model"`
+	ByLocale []CacheBucket `json:"by_locale"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// GlossaryTerm is a project termbase entry with its translations per locale.
+type GlossaryTerm struct {
+	ID             int64                 `json:"id"`
+	ProjectID      int64                 `json:"project_id"`
+	Term           string                `json:"term"`
+	Description    string                `json:"description"`
+	PartOfSpeech   string                `json:"part_of_speech"`
+	CaseSensitive  bool                  `json:"case_sensitive"`
+	DoNotTranslate bool                  `json:"do_not_translate"`
+	Translations   []GlossaryTranslation `json:"translations"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// GlossaryTranslation is an approved or, when Forbidden is set, a forbidden rendering of a term.
+type GlossaryTranslation struct {
+	Locale    string `json:"locale"`
+	Text      string `json:"text"`
+	Forbidden bool   `json:"forbidden"`
+}
+
+// TermIssue describes a translation that does not follow the glossary.
+type TermIssue struct {
+	Term     string   `json:"term"`
+	Kind     string   `json:"kind"` // missing | forbidden | untranslated
+	Expected []string `json:"expected,omitempty"`
+	Found    string   `json:"found,omitempty"`
+}
@@ -9,6 +9,7 @@ type Job struct {
 	ProjectID  *int64    `json:"project_id"`
 	ProviderID *int64    `json:"provider_id"`
 	ParamsRaw  string    `json:"params_json"`
+	ResultRaw  string    `json:"result_json"`
 	Progress   int       `json:"progress"`
 	Total      int       `json:"total"`
 	CreatedAt  time.Time `json:"created_at"`
@@ -22,6 +23,7 @@ type JobItem struct {
 	Locale    *string   `json:"locale"`
 	Status    string    `json:"status"`
 	Error     string    `json:"error"`
+	MetaRaw   string    `json:"meta_json"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ProtectedTerm is text that must pass through translation untouched (brand names, SKUs,
+// button names, URLs). Pattern is literal unless IsRegex is set.
+type ProtectedTerm struct {
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"project_id"`
+	Pattern   string    `json:"pattern"`
+	IsRegex   bool      `json:"is_regex"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,22 +1,37 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Provider struct {
-	ID         int64     `json:"id"`
-	Type       string    `json:"type"` // e.g., ollama, openrouter, openai
-	Name       string    `json:"name"`
-	BaseURL    string    `json:"base_url"`
-	Model      string    `json:"model"`
-	APIKey     string    `json:"api_key"`
-	OptionsRaw string    `json:"options_json"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         int64  `json:"id"`
+	Type       string `json:"type"` // e.g., ollama, openrouter, openai
+	Name       string `json:"name"`
+	BaseURL    string `json:"base_url"`
+	Model      string `json:"model"`
+	APIKey     string `json:"api_key"`
+	OptionsRaw string `json:"options_json"`
+	// Concurrency is the number of parallel requests translate jobs send to the provider.
+	Concurrency int       `json:"concurrency"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type ProviderModel struct {
-	ID         int64     `json:"id"`
-	ProviderID int64     `json:"provider_id"`
-	Name       string    `json:"name"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID            int64  `json:"id"`
+	ProviderID    int64  `json:"provider_id"`
+	Name          string `json:"name"`
+	ContextTokens int    `json:"context_tokens"`
+	// InputPrice and OutputPrice are USD per million tokens; zero when unknown.
+	InputPrice  float64   `json:"input_price"`
+	OutputPrice float64   `json:"output_price"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// MaskedKeyPrefix starts API keys masked for display. Saving a provider with a masked key
+// keeps the stored one.
+const MaskedKeyPrefix = "****"
+
+func IsMaskedKey(key string) bool { return strings.HasPrefix(key, MaskedKeyPrefix) }
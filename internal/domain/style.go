@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// StyleGuide holds translation style rules for a project. An empty Locale applies to all
+// target locales; a locale-specific guide overrides it field by field.
+type StyleGuide struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	Locale      string    `json:"locale"`
+	Formality   string    `json:"formality"`   // e.g. "informal (du)", "formal (vous)"
+	Tone        string    `json:"tone"`        // e.g. "playful", "neutral"
+	Audience    string    `json:"audience"`    // e.g. "teenage gamers"
+	Punctuation string    `json:"punctuation"` // e.g. "use « » quotes, non-breaking space before : ; ! ?"
+	Notes       string    `json:"notes"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -13,18 +13,15 @@ type Template struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// TemplateVersion is a previous body of a template.
+type TemplateVersion struct {
+	ID         int64     `json:"id"`
+	TemplateID int64     `json:"template_id"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Setting struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
-
-type CacheEntry struct {
-	ID          int64     `json:"id"`
-	SourceText  string    `json:"source_text"`
-	SrcLang     string    `json:"src_lang"`
-	TgtLang     string    `json:"tgt_lang"`
-	Provider    string    `json:"provider"`
-	Model       string    `json:"model"`
-	Translation string    `json:"translation"`
-	CreatedAt   time.Time `json:"created_at"`
-}
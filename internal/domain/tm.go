@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// TMEntry is a translation memory unit: an approved source/target pair.
+type TMEntry struct {
+	ID         int64     `json:"id"`
+	SourceText string    `json:"source_text"`
+	TargetText string    `json:"target_text"`
+	SrcLang    string    `json:"src_lang"`
+	TgtLang    string    `json:"tgt_lang"`
+	ProjectID  *int64    `json:"project_id"`
+	UnitID     *int64    `json:"unit_id"`
+	Origin     string    `json:"origin"` // translation | import
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TMMatch is a memory entry scored against a query text; Score is a 0-100 match percentage.
+type TMMatch struct {
+	Entry *TMEntry `json:"entry"`
+	Score int      `json:"score"`
+}
+
+type TMFilter struct {
+	ProjectID *int64   `json:"project_id"`
+	SrcLang   string   `json:"src_lang"`
+	TgtLang   string   `json:"tgt_lang"`
+	Statuses  []string `json:"statuses"` // empty matches every status
+	Query     string   `json:"query"`
+	Limit     int      `json:"limit"`
+	Offset    int      `json:"offset"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Usage is the tokens and cost of translated items. Cost is in USD and covers only the
+// items whose model price is known; Unpriced counts the others.
+type Usage struct {
+	Items        int     `json:"items"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+	Unpriced     int     `json:"unpriced"`
+}
+
+// UsageQuery filters and groups a usage report. Months are "YYYY-MM" and inclusive; zero
+// values do not filter. GroupBy takes "month", "project", "provider" and "model"; empty
+// groups by all of them.
+type UsageQuery struct {
+	FromMonth  string   `json:"from_month"`
+	ToMonth    string   `json:"to_month"`
+	ProjectID  int64    `json:"project_id"`
+	ProviderID int64    `json:"provider_id"`
+	GroupBy    []string `json:"group_by"`
+}
+
+// UsageRow is one group of a usage report; fields not grouped by are empty.
+type UsageRow struct {
+	Month        string `json:"month,omitempty"`
+	ProjectID    *int64 `json:"project_id,omitempty"`
+	ProjectName  string `json:"project_name,omitempty"`
+	ProviderID   *int64 `json:"provider_id,omitempty"`
+	ProviderName string `json:"provider_name,omitempty"`
+	Model        string `json:"model,omitempty"`
+	Usage
+}
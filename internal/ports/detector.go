@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+type Detection struct {
+	Lang       string
+	Confidence float64
+}
+
+// LanguageDetector guesses the language of a set of text samples.
+type LanguageDetector interface {
+	Detect(ctx context.Context, samples []string) (Detection, error)
+}
@@ -2,6 +2,12 @@ package ports
 
 import "context"
 
+// NeighborUnit is a unit adjacent to the one being translated, given to the model as context.
+type NeighborUnit struct {
+	Key  string
+	Text string
+}
+
 type PromptData struct {
 	SrcLang      string
 	TgtLang      string
@@ -12,8 +18,60 @@ type PromptData struct {
 	Context      string
 	Placeholders []string
 	Tags         []string
+	Neighbors    []NeighborUnit
+	Glossary     []GlossaryEntry
+	Examples     []Example
+	// Units are the segments of a translate_file (batch) prompt.
+	Units []PromptUnit
+	// Style guide of the project for the target locale.
+	Formality   string
+	Tone        string
+	Audience    string
+	Punctuation string
+	StyleNotes  string
+}
+
+// PromptUnit is one segment of a batch prompt; templates usually emit them with {{json .Units}}.
+type PromptUnit struct {
+	Key     string `json:"key"`
+	Text    string `json:"text"`
+	Context string `json:"context,omitempty"`
+}
+
+// Example is an approved translation of a similar string, shown to the model as a few-shot example.
+type Example struct {
+	Source      string
+	Translation string
+	Score       int
+}
+
+// GlossaryEntry is a glossary term found in the source text, with its translations for the target locale.
+type GlossaryEntry struct {
+	Term           string
+	Translations   []string
+	Forbidden      []string
+	DoNotTranslate bool
+	PartOfSpeech   string
+	Description    string
+}
+
+// TemplateScope selects the stored templates a prompt may come from.
+type TemplateScope struct {
+	ProviderID *int64
+	ProjectID  *int64
+}
+
+// RenderedPrompt is a rendered prompt and the template it was rendered from.
+type RenderedPrompt struct {
+	Text string
+	// Source is override, provider, project, global or builtin.
+	Source string
+	// TemplateID is the stored template used (0 for overrides and builtins).
+	TemplateID int64
 }
 
 type PromptRenderer interface {
-	Render(ctx context.Context, scope string, refID *int64, typ, role string, data PromptData) (string, error)
+	// Render renders override when it is not empty, otherwise the effective template of scope:
+	// provider -> project -> global -> builtin.
+	Render(ctx context.Context, scope TemplateScope, typ, role, override string, data PromptData) (RenderedPrompt, error)
 }
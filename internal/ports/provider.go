@@ -2,6 +2,9 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 )
 
 type Segment struct {
@@ -19,22 +22,91 @@ type TranslateParams struct {
 	Temperature  float64
 	SystemPrompt string
 	UserPrompt   string
+	// ResultKey is the JSON field the model is asked to answer in; defaults to "translation".
+	ResultKey string
 }
 
 type TranslateResult struct {
 	Translation string
 	Raw         string
+	Usage       Usage
+}
+
+// BatchResult holds the translations of a batch keyed by segment key.
+type BatchResult struct {
+	Translations map[string]string
+	Raw          string
+	Usage        Usage
+}
+
+// Usage is the token count a provider reported for a request; zero when unknown.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
 }
 
 type ModelInfo struct {
 	Name          string
 	Description   string
 	ContextTokens int
+	// InputPrice and OutputPrice are USD per million tokens; zero when the provider does not
+	// publish prices.
+	InputPrice  float64
+	OutputPrice float64
 }
 
 // Provider represents a single LLM provider implementation.
 type Provider interface {
 	Translate(ctx context.Context, seg Segment, p TranslateParams) (TranslateResult, error)
+	// TranslateBatch translates several segments in one request; the model answers with a
+	// JSON object mapping each segment key to its translation.
+	TranslateBatch(ctx context.Context, segs []Segment, p TranslateParams) (BatchResult, error)
 	ListModels(ctx context.Context) ([]ModelInfo, error)
 	Test(ctx context.Context) error
 }
+
+// PromptlessProvider is implemented by providers that may translate segments without
+// prompts, such as classic machine translation engines. Segment text carries masked
+// placeholders (e.g. __PH_0__) that must come back unchanged.
+type PromptlessProvider interface {
+	Provider
+	// Promptless reports whether the provider ignores prompts.
+	Promptless() bool
+}
+
+// ErrMalformedResponse is wrapped by errors for model answers that cannot be parsed.
+var ErrMalformedResponse = errors.New("malformed model response")
+
+// ErrContentBlocked is wrapped by errors for requests the provider refused for safety
+// reasons. Repeating the request does not help.
+var ErrContentBlocked = errors.New("content blocked by provider")
+
+// ProviderError is an HTTP error returned by a provider API.
+type ProviderError struct {
+	Provider   string
+	Op         string
+	StatusCode int
+	Status     string
+	// RetryAfter is the delay requested by the Retry-After header (0 if absent).
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *ProviderError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s %s: %s", e.Provider, e.Op, e.Status)
+	}
+	return fmt.Sprintf("%s %s: %s; body: %s", e.Provider, e.Op, e.Status, e.Body)
+}
+
+// Retryable reports whether repeating the request may succeed: rate limits, timeouts and
+// server errors.
+func (e *ProviderError) Retryable() bool {
+	switch {
+	case e.StatusCode == 408, e.StatusCode == 425, e.StatusCode == 429:
+		return true
+	case e.StatusCode >= 500:
+		return e.StatusCode != 501
+	}
+	return false
+}
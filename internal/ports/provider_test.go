@@ -0,0 +1,26 @@
+package ports
+
+import "testing"
+
+func TestProviderErrorRetryable(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{400, false},
+		{401, false},
+		{404, false},
+		{408, true},
+		{425, true},
+		{429, true},
+		{500, true},
+		{501, false},
+		{502, true},
+		{503, true},
+	}
+	for _, tt := range tests {
+		if got := (&ProviderError{StatusCode: tt.status}).Retryable(); got != tt.want {
+			t.Errorf("Retryable() for %d = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"locail/internal/domain"
+	"time"
 )
 
 type ProjectRepository interface {
@@ -19,6 +20,7 @@ type FileRepository interface {
 	Create(ctx context.Context, f *domain.File) error
 	Get(ctx context.Context, id int64) (*domain.File, error)
 	ListByProject(ctx context.Context, projectID int64) ([]*domain.File, error)
+	UpdateLocale(ctx context.Context, id int64, locale string) error
 	Delete(ctx context.Context, id int64) error
 }
 
@@ -40,15 +42,22 @@ type ProviderRepository interface {
 	Get(ctx context.Context, id int64) (*domain.Provider, error)
 	List(ctx context.Context) ([]*domain.Provider, error)
 	Delete(ctx context.Context, id int64) error
-	SaveModelCache(ctx context.Context, providerID int64, names []string) error
+	// SaveModelCache replaces the cached model list of a provider.
+	SaveModelCache(ctx context.Context, providerID int64, models []*domain.ProviderModel) error
 	ListModelCache(ctx context.Context, providerID int64) ([]*domain.ProviderModel, error)
 }
 
 type JobRepository interface {
 	Create(ctx context.Context, j *domain.Job) (int64, error)
 	UpdateProgress(ctx context.Context, jobID int64, done, total int, status string) error
+	SetResult(ctx context.Context, jobID int64, resultJSON string) error
 	AddItem(ctx context.Context, ji *domain.JobItem) (int64, error)
 	UpdateItem(ctx context.Context, itemID int64, status, errMsg string) error
+	SetItemMeta(ctx context.Context, itemID int64, metaJSON string) error
+	// SetItemUsage records who translated an item and what it cost; a nil cost is unknown.
+	SetItemUsage(ctx context.Context, itemID, providerID int64, model string, inputTokens, outputTokens int, cost *float64) error
+	// Usage sums the usage of the items of a job.
+	Usage(ctx context.Context, jobID int64) (domain.Usage, error)
 	AddLog(ctx context.Context, jl *domain.JobLog) error
 	Get(ctx context.Context, jobID int64) (*domain.Job, error)
 	List(ctx context.Context, limit int) ([]*domain.Job, error)
@@ -57,17 +66,77 @@ type JobRepository interface {
 	Delete(ctx context.Context, jobID int64) error
 }
 
+// UsageRepository reports token usage and cost of translated job items.
+type UsageRepository interface {
+	Report(ctx context.Context, q domain.UsageQuery) ([]*domain.UsageRow, error)
+	// Throughput returns the items a provider and model translated in its last jobs and the
+	// wall-clock seconds they took; cache hits are left out.
+	Throughput(ctx context.Context, providerID int64, model string, jobs int) (items int, seconds float64, err error)
+}
+
 type TemplateRepository interface {
-	GetEffective(ctx context.Context, scope string, refID *int64, typ, role string) (*domain.Template, error)
+	// GetEffective returns the provider template, then the project one, then the global one
+	// (nil when none is stored).
+	GetEffective(ctx context.Context, scope TemplateScope, typ, role string) (*domain.Template, error)
+	// Upsert saves the template for its (scope, ref_id, type, role), keeping the previous body as a version.
 	Upsert(ctx context.Context, t *domain.Template) error
+	Get(ctx context.Context, id int64) (*domain.Template, error)
+	// List returns templates of a scope (all scopes when empty); refID narrows project/provider scopes.
+	List(ctx context.Context, scope string, refID *int64) ([]*domain.Template, error)
+	Delete(ctx context.Context, id int64) error
+	ListVersions(ctx context.Context, templateID int64) ([]*domain.TemplateVersion, error)
 }
 
 type CacheRepository interface {
-	Get(ctx context.Context, src, srcLang, tgtLang, provider, model string) (*domain.CacheEntry, error)
+	Get(ctx context.Context, key domain.CacheKey) (*domain.CacheEntry, error)
 	Put(ctx context.Context, entry *domain.CacheEntry) error
+	Touch(ctx context.Context, id int64) error
+	List(ctx context.Context, f domain.CacheFilter) ([]*domain.CacheEntry, error)
+	Stats(ctx context.Context) (*domain.CacheStats, error)
+	Delete(ctx context.Context, id int64) error
+	Purge(ctx context.Context, f domain.CacheFilter) (int64, error)
+	PurgeOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+type TMRepository interface {
+	Put(ctx context.Context, e *domain.TMEntry) error
+	DeleteByUnit(ctx context.Context, unitID int64, tgtLang string) error
+	// Candidates returns entries likely to be similar to text for fuzzy scoring.
+	// projectID 0 searches all projects.
+	Candidates(ctx context.Context, projectID int64, srcLang, tgtLang, text string, limit int) ([]*domain.TMEntry, error)
+	// BySource returns entries whose source equals text up to case and whitespace, newest
+	// first. srcLang "" matches any source language.
+	BySource(ctx context.Context, srcLang, tgtLang, text string) ([]*domain.TMEntry, error)
+	// CollectFromTranslations builds entries from translations with the given statuses.
+	CollectFromTranslations(ctx context.Context, statuses []string) ([]*domain.TMEntry, error)
+	List(ctx context.Context, f domain.TMFilter) ([]*domain.TMEntry, error)
+	Count(ctx context.Context) (int, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type SettingsRepository interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key, value string) error
 }
+
+type GlossaryRepository interface {
+	// Save inserts or updates a term (by ID, or by project and term text when ID is 0)
+	// and replaces its translations.
+	Save(ctx context.Context, t *domain.GlossaryTerm) error
+	Get(ctx context.Context, id int64) (*domain.GlossaryTerm, error)
+	ListByProject(ctx context.Context, projectID int64) ([]*domain.GlossaryTerm, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type ProtectedTermRepository interface {
+	Create(ctx context.Context, t *domain.ProtectedTerm) error
+	ListByProject(ctx context.Context, projectID int64) ([]*domain.ProtectedTerm, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type StyleGuideRepository interface {
+	// Upsert stores the guide for (project, locale), replacing an existing one.
+	Upsert(ctx context.Context, g *domain.StyleGuide) error
+	ListByProject(ctx context.Context, projectID int64) ([]*domain.StyleGuide, error)
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,154 @@
+package glossary
+
+import (
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match returns the terms occurring in text as whole words, longest first.
+func Match(terms []*domain.GlossaryTerm, text string) []*domain.GlossaryTerm {
+	var out []*domain.GlossaryTerm
+	for _, t := range terms {
+		if strings.TrimSpace(t.Term) == "" {
+			continue
+		}
+		if contains(text, t.Term, t.CaseSensitive, true) {
+			out = append(out, t)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i].Term) > len(out[j].Term) })
+	return out
+}
+
+// PromptEntries converts matched terms to template data for the target locale.
+func PromptEntries(terms []*domain.GlossaryTerm, locale string) []ports.GlossaryEntry {
+	out := make([]ports.GlossaryEntry, 0, len(terms))
+	for _, t := range terms {
+		approved, forbidden := ForLocale(t, locale)
+		if !t.DoNotTranslate && len(approved) == 0 && len(forbidden) == 0 {
+			continue
+		}
+		out = append(out, ports.GlossaryEntry{
+			Term:           t.Term,
+			Translations:   approved,
+			Forbidden:      forbidden,
+			DoNotTranslate: t.DoNotTranslate,
+			PartOfSpeech:   t.PartOfSpeech,
+			Description:    t.Description,
+		})
+	}
+	return out
+}
+
+// ForLocale returns the approved and forbidden translations of a term for a locale. Base
+// languages and regional variants match each other ("de" and "de-at"), since termbases and
+// projects rarely agree on locale granularity.
+func ForLocale(t *domain.GlossaryTerm, locale string) (approved, forbidden []string) {
+	locale = normalizeLang(locale)
+	for _, tr := range t.Translations {
+		l := normalizeLang(tr.Locale)
+		if l != locale && !strings.HasPrefix(locale, l+"-") && !strings.HasPrefix(l, locale+"-") {
+			continue
+		}
+		if tr.Forbidden {
+			forbidden = append(forbidden, tr.Text)
+		} else {
+			approved = append(approved, tr.Text)
+		}
+	}
+	return approved, forbidden
+}
+
+// Check validates a translation against the terms matched in its source: do-not-translate
+// terms must be kept verbatim, one of the approved translations must be used and no
+// forbidden translation may appear. Target terms are matched on their start only so that
+// inflected forms ("Schwertes" for "Schwert") are accepted.
+func Check(matched []*domain.GlossaryTerm, translated, locale string) []domain.TermIssue {
+	var issues []domain.TermIssue
+	for _, t := range matched {
+		if t.DoNotTranslate {
+			if !strings.Contains(translated, t.Term) {
+				issues = append(issues, domain.TermIssue{Term: t.Term, Kind: "untranslated", Expected: []string{t.Term}})
+			}
+			continue
+		}
+		approved, forbidden := ForLocale(t, locale)
+		for _, f := range forbidden {
+			if contains(translated, f, t.CaseSensitive, true) {
+				issues = append(issues, domain.TermIssue{Term: t.Term, Kind: "forbidden", Found: f, Expected: approved})
+			}
+		}
+		if len(approved) == 0 {
+			continue
+		}
+		found := false
+		for _, a := range approved {
+			if contains(translated, a, t.CaseSensitive, false) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			issues = append(issues, domain.TermIssue{Term: t.Term, Kind: "missing", Expected: approved})
+		}
+	}
+	return issues
+}
+
+// contains reports whether needle occurs in s starting at a word boundary and, when
+// wholeWord is set, also ending at one.
+func contains(s, needle string, caseSensitive, wholeWord bool) bool {
+	if needle == "" {
+		return false
+	}
+	if !caseSensitive {
+		s, needle = strings.ToLower(s), strings.ToLower(needle)
+	}
+	for from := 0; from <= len(s); {
+		i := strings.Index(s[from:], needle)
+		if i < 0 {
+			return false
+		}
+		start := from + i
+		end := start + len(needle)
+		if boundaryBefore(s, start) && (!wholeWord || boundaryAfter(s, end)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		from = start + size
+	}
+	return false
+}
+
+func boundaryBefore(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return !isWordRune(r)
+}
+
+func boundaryAfter(s string, i int) bool {
+	if i >= len(s) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return !isWordRune(r)
+}
+
+// isWordRune reports whether r continues a word. Scripts written without spaces never do,
+// so terms in Chinese or Japanese text match anywhere.
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func normalizeLang(l string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(l)), "_", "-")
+}
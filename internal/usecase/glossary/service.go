@@ -0,0 +1,134 @@
+package glossary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/adapters/termbase"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"slices"
+	"strings"
+)
+
+type Deps struct {
+	Glossary ports.GlossaryRepository
+	Projects ports.ProjectRepository
+	Units    ports.UnitRepository
+	Files    ports.FileRepository
+}
+
+type Service struct{ d Deps }
+
+func New(d Deps) *Service { return &Service{d: d} }
+
+// Save validates and stores a term.
+func (s *Service) Save(ctx context.Context, t *domain.GlossaryTerm) error {
+	t.Term = strings.TrimSpace(t.Term)
+	if t.ProjectID == 0 {
+		return errors.New("project_id is required")
+	}
+	if t.Term == "" {
+		return errors.New("term is required")
+	}
+	trs := t.Translations[:0]
+	for _, tr := range t.Translations {
+		tr.Locale = normalizeLang(tr.Locale)
+		tr.Text = strings.TrimSpace(tr.Text)
+		if tr.Locale != "" && tr.Text != "" {
+			trs = append(trs, tr)
+		}
+	}
+	t.Translations = trs
+	return s.d.Glossary.Save(ctx, t)
+}
+
+// Import loads terms from a TBX or CSV document into a project glossary. Translations of
+// terms that already exist are merged, without repeating a text a locale already has.
+// srcLang selects the source language of TBX entries
+// and defaults to the project's source language.
+func (s *Service) Import(ctx context.Context, projectID int64, format string, data []byte, srcLang string) (int, error) {
+	if srcLang == "" && s.d.Projects != nil {
+		if p, err := s.d.Projects.Get(ctx, projectID); err == nil && p != nil {
+			srcLang = p.SourceLang
+		}
+	}
+	var terms []*domain.GlossaryTerm
+	var err error
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "tbx", "xml":
+		terms, err = termbase.ParseTBX(data, srcLang)
+	case "csv", "tsv":
+		terms, err = termbase.ParseCSV(data)
+	default:
+		return 0, fmt.Errorf("unsupported glossary format: %s", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	existing, err := s.d.Glossary.ListByProject(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	byTerm := make(map[string]*domain.GlossaryTerm, len(existing))
+	for _, t := range existing {
+		byTerm[t.Term] = t
+	}
+	n := 0
+	for _, t := range terms {
+		t.ProjectID = projectID
+		if old := byTerm[strings.TrimSpace(t.Term)]; old != nil {
+			t.ID = old.ID
+			t.Translations = append(slices.Clone(old.Translations), t.Translations...)
+			if t.Description == "" {
+				t.Description = old.Description
+			}
+			if t.PartOfSpeech == "" {
+				t.PartOfSpeech = old.PartOfSpeech
+			}
+			t.CaseSensitive = t.CaseSensitive || old.CaseSensitive
+			t.DoNotTranslate = t.DoNotTranslate || old.DoNotTranslate
+		}
+		t.Translations = dedupeTranslations(t.Translations)
+		if err := s.Save(ctx, t); err != nil {
+			return n, err
+		}
+		byTerm[t.Term] = t
+		n++
+	}
+	return n, nil
+}
+
+// dedupeTranslations drops texts repeated within a locale, ignoring case; the kept entry
+// takes the forbidden flag of the last occurrence.
+func dedupeTranslations(trs []domain.GlossaryTranslation) []domain.GlossaryTranslation {
+	out := make([]domain.GlossaryTranslation, 0, len(trs))
+	seen := map[string]int{}
+	for _, tr := range trs {
+		k := normalizeLang(tr.Locale) + "\x00" + strings.ToLower(strings.TrimSpace(tr.Text))
+		if i, ok := seen[k]; ok {
+			out[i].Forbidden = tr.Forbidden
+			continue
+		}
+		seen[k] = len(out)
+		out = append(out, tr)
+	}
+	return out
+}
+
+// CheckUnit validates a translation of a unit against its project's glossary.
+func (s *Service) CheckUnit(ctx context.Context, unitID int64, locale, text string) ([]domain.TermIssue, error) {
+	u, err := s.d.Units.Get(ctx, unitID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.d.Files.Get(ctx, u.FileID)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := s.d.Glossary.ListByProject(ctx, f.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return Check(Match(terms, u.SourceText), text, locale), nil
+}
@@ -0,0 +1,63 @@
+package glossary
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/domain"
+)
+
+func TestImportMergesTranslations(t *testing.T) {
+	ctx := context.Background()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	projects := dbsqlite.NewProjectRepo(db)
+	p := &domain.Project{Name: "Game", SourceLang: "en"}
+	if err := projects.Create(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	repo := dbsqlite.NewGlossaryRepo(db)
+	svc := New(Deps{Glossary: repo, Projects: projects})
+
+	imports := []string{
+		"term,de,de:forbidden,fr\nSave,Speichern|speichern,Sichern,Enregistrer\n",
+		// again, with other casing, another locale spelling and a new text
+		"term,DE,de,fr:forbidden\nSave,SPEICHERN,Abspeichern,enregistrer\n",
+	}
+	for i, csv := range imports {
+		if n, err := svc.Import(ctx, p.ID, "csv", []byte(csv), ""); err != nil || n != 1 {
+			t.Fatalf("import %d: %d terms, %v", i, n, err)
+		}
+	}
+	terms, err := repo.ListByProject(ctx, p.ID)
+	if err != nil || len(terms) != 1 {
+		t.Fatalf("got %d terms, %v", len(terms), err)
+	}
+	want := map[string]bool{
+		"de/Speichern":   false,
+		"de/Sichern":     true,
+		"fr/Enregistrer": true,
+		"de/Abspeichern": false,
+	}
+	got := map[string]bool{}
+	for _, tr := range terms[0].Translations {
+		k := tr.Locale + "/" + tr.Text
+		if _, dup := got[k]; dup {
+			t.Fatalf("%s stored twice", k)
+		}
+		got[k] = tr.Forbidden
+	}
+	if len(got) != len(want) {
+		t.Fatalf("translations %v, want %v", got, want)
+	}
+	for k, forbidden := range want {
+		if f, ok := got[k]; !ok || f != forbidden {
+			t.Errorf("%s: stored %v (forbidden %v), want forbidden %v", k, ok, f, forbidden)
+		}
+	}
+}
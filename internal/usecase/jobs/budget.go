@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/usecase/translator"
+	"sync"
+)
+
+// jobBudget stops a job from sending requests once its items cost limit USD. Items whose
+// price is unknown count as free. Requests in flight when the limit is reached still finish,
+// so a job can overshoot by up to the provider's concurrency in requests (whole batches in
+// batch mode). A nil budget has no limit.
+type jobBudget struct {
+	limit   float64
+	mu      sync.Mutex
+	spent   float64
+	skipped int
+}
+
+func newJobBudget(limit float64) *jobBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &jobBudget{limit: limit}
+}
+
+// add records the cost of an item and reports whether it used up the budget.
+func (b *jobBudget) add(cost float64) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	was := b.spent >= b.limit
+	b.spent += cost
+	return !was && b.spent >= b.limit
+}
+
+// allow reports whether another request may be sent, counting the work skipped once the
+// budget is used up. Requests already running are let finish.
+func (b *jobBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.spent < b.limit {
+		return true
+	}
+	b.skipped++
+	return false
+}
+
+// stopped reports whether the budget kept work from running.
+func (b *jobBudget) stopped() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.skipped > 0
+}
+
+// spend adds the cost of a finished item to the job's budget, logging when it runs out.
+func (r *Runner) spend(ctx context.Context, jobID int64, t *translateTask, cost float64) {
+	if t.budget.add(cost) {
+		r.log(ctx, jobID, "warn", fmt.Sprintf("budget of $%.2f reached ($%.4f spent), no new requests are sent", t.budget.limit, t.budget.spent))
+	}
+}
+
+// checkBudget rejects a budget that cannot be enforced because the model's price is unknown.
+func (r *Runner) checkBudget(ctx context.Context, providerID int64, model string, budget float64) error {
+	switch {
+	case budget < 0:
+		return fmt.Errorf("budget must not be negative")
+	case budget == 0:
+		return nil
+	case r.d.Usage == nil:
+		return fmt.Errorf("budget needs usage accounting")
+	}
+	if _, ok := r.d.Usage.Price(ctx, providerID, model); !ok {
+		return fmt.Errorf("budget needs the price of model %q: set input_price and output_price in the provider options", model)
+	}
+	return nil
+}
+
+// checkBudgets checks the budget against the job's provider and each fallback, since any of
+// them may spend it.
+func (r *Runner) checkBudgets(ctx context.Context, providerID int64, model string, fallbacks []translator.Fallback, budget float64) error {
+	if err := r.checkBudget(ctx, providerID, model, budget); err != nil {
+		return err
+	}
+	for _, fb := range fallbacks {
+		if err := r.checkBudget(ctx, fb.ProviderID, fb.Model, budget); err != nil {
+			return fmt.Errorf("fallback provider %d: %w", fb.ProviderID, err)
+		}
+	}
+	return nil
+}
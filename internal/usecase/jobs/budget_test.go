@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"locail/internal/usecase/usage"
+)
+
+func TestJobBudget(t *testing.T) {
+	b := newJobBudget(1)
+	// each step adds a cost, then asks for another request
+	tests := []struct {
+		cost    float64
+		reached bool
+		allow   bool
+	}{
+		{0.4, false, true},
+		{0, false, true}, // unknown price counts as free
+		{0.59, false, true},
+		{0.01, true, false},
+		{0.5, false, false}, // requests that were already running only add up
+	}
+	for i, tt := range tests {
+		if got := b.add(tt.cost); got != tt.reached {
+			t.Fatalf("step %d: add reported %v, want %v", i, got, tt.reached)
+		}
+		if got := b.allow(); got != tt.allow {
+			t.Fatalf("step %d: allow %v, want %v", i, got, tt.allow)
+		}
+	}
+	if !b.stopped() || b.skipped != 2 {
+		t.Fatalf("stopped %v with %d skipped", b.stopped(), b.skipped)
+	}
+}
+
+func TestJobBudgetUnlimited(t *testing.T) {
+	for _, limit := range []float64{0, -1} {
+		b := newJobBudget(limit)
+		if b != nil {
+			t.Fatalf("newJobBudget(%v) is limited", limit)
+		}
+		if b.add(1e9) || !b.allow() || b.stopped() {
+			t.Fatalf("nil budget limits work")
+		}
+	}
+}
+
+func TestCheckBudgetWithoutPrices(t *testing.T) {
+	r := &Runner{}
+	tests := []struct {
+		budget  float64
+		wantErr string
+	}{
+		{0, ""},
+		{-1, "must not be negative"},
+		{5, "needs usage accounting"},
+	}
+	for _, tt := range tests {
+		err := r.checkBudget(context.Background(), 1, "m", tt.budget)
+		if (tt.wantErr == "" && err != nil) || (tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr))) {
+			t.Errorf("checkBudget(%v) = %v, want %q", tt.budget, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckBudgetsFallbacks(t *testing.T) {
+	env := newTestEnv(t, 1)
+	ctx := context.Background()
+	env.deps.Usage = usage.New(usage.Deps{
+		Providers:     env.providers,
+		Jobs:          env.jobs,
+		Usage:         dbsqlite.NewUsageRepo(env.db),
+		BuildProvider: func(*domain.Provider) (ports.Provider, error) { return env.fake, nil },
+	})
+	env.build()
+	priced := &domain.Provider{Type: "ollama", Name: "priced", Model: "m", OptionsRaw: `{"input_price":1,"output_price":2}`}
+	if err := env.providers.Create(ctx, priced); err != nil {
+		t.Fatal(err)
+	}
+	unpriced := env.provider
+	tests := []struct {
+		name      string
+		fallbacks []translator.Fallback
+		budget    float64
+		err       string
+	}{
+		{"no fallbacks", nil, 5, ""},
+		{"priced fallback", []translator.Fallback{{ProviderID: priced.ID, Model: "m"}}, 5, ""},
+		{"unpriced fallback", []translator.Fallback{{ProviderID: priced.ID, Model: "m"}, {ProviderID: unpriced.ID, Model: "m"}}, 5, fmt.Sprintf("fallback provider %d: budget needs the price", unpriced.ID)},
+		{"no budget", []translator.Fallback{{ProviderID: unpriced.ID, Model: "m"}}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.r.checkBudgets(ctx, priced.ID, "m", tt.fallbacks, tt.budget)
+			if (tt.err == "" && err != nil) || (tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err))) {
+				t.Fatalf("got %v, want %q", err, tt.err)
+			}
+		})
+	}
+	// the job start checks the fallbacks the runner resolved
+	_, err := env.r.StartTranslateFile(ctx, env.project.ID, priced.ID, TranslateFileParams{FileID: env.file.ID, TargetLocales: []string{"de"}, Budget: 5, Fallbacks: []translator.Fallback{{ProviderID: unpriced.ID}}})
+	if err == nil || !strings.Contains(err.Error(), `price of model "m"`) {
+		t.Fatalf("start with an unpriced fallback: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/usecase/translator"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDetectSamples       = 20
+	defaultDetectMinConfidence = 0.6
+)
+
+// DetectLanguageParams configures a detect_language job.
+type DetectLanguageParams struct {
+	FileID     int64  `json:"file_id"`
+	SampleSize int    `json:"sample_size"`
+	Model      string `json:"model"`
+	// Mode: "auto" (offline detector first, LLM only for ambiguous samples), "offline" or "llm".
+	Mode string `json:"mode"`
+	// MinConfidence is the offline confidence below which a sample is considered ambiguous.
+	MinConfidence float64 `json:"min_confidence"`
+	// ApplyTo optionally stores the result: "file" (File.Locale) or "project" (Project.SourceLang).
+	ApplyTo string `json:"apply_to"`
+}
+
+// DetectLanguageResult is stored as the job result.
+type DetectLanguageResult struct {
+	Lang       string         `json:"lang"`
+	Confidence float64        `json:"confidence"`
+	Votes      map[string]int `json:"votes"`
+	Samples    int            `json:"samples"`
+	LLMCalls   int            `json:"llm_calls"`
+	Applied    string         `json:"applied,omitempty"`
+}
+
+type jobDetectResultPayload struct {
+	JobID  int64                `json:"job_id"`
+	FileID int64                `json:"file_id"`
+	Result DetectLanguageResult `json:"result"`
+}
+
+// StartDetectLanguage samples units from a file and detects their language by majority vote.
+// providerID may be 0 for offline-only detection.
+func (r *Runner) StartDetectLanguage(ctx context.Context, projectID, providerID int64, p DetectLanguageParams) (int64, error) {
+	if p.FileID == 0 {
+		return 0, errors.New("file_id is required")
+	}
+	if p.SampleSize <= 0 {
+		p.SampleSize = defaultDetectSamples
+	}
+	if p.MinConfidence <= 0 {
+		p.MinConfidence = defaultDetectMinConfidence
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = "auto"
+	case "auto", "offline", "llm":
+	default:
+		return 0, fmt.Errorf("unknown detect mode: %s", p.Mode)
+	}
+	if p.Mode == "llm" && providerID == 0 {
+		return 0, errors.New("provider is required for llm detection")
+	}
+	if p.ApplyTo != "" && p.ApplyTo != "file" && p.ApplyTo != "project" {
+		return 0, fmt.Errorf("unknown apply_to: %s", p.ApplyTo)
+	}
+	var provPtr *int64
+	if providerID != 0 {
+		p.Model = r.resolveModel(ctx, providerID, p.Model)
+		provPtr = &providerID
+	}
+	units, err := r.d.Units.ListByFile(ctx, p.FileID)
+	if err != nil {
+		return 0, err
+	}
+	samples := sampleUnits(units, p.SampleSize)
+	paramsJSON, _ := json.Marshal(p)
+	job := &domain.Job{
+		Type:       "detect_language",
+		Status:     "queued",
+		ProjectID:  &projectID,
+		ProviderID: provPtr,
+		ParamsRaw:  string(paramsJSON),
+		Total:      len(samples),
+	}
+	id, err := r.d.Jobs.Create(ctx, job)
+	if err != nil {
+		return 0, err
+	}
+	_ = r.d.Jobs.UpdateProgress(ctx, id, 0, len(samples), "running")
+	r.emitStarted(id, len(samples), p.Model, providerID)
+	r.log(
+		ctx,
+		id,
+		"info",
+		fmt.Sprintf(
+			"detect started: file=%d samples=%d mode=%s",
+			p.FileID,
+			len(samples),
+			p.Mode,
+		),
+	)
+	r.startAsync(id, func(runCtx context.Context) {
+		r.runDetectLanguage(runCtx, id, projectID, providerID, p, samples)
+	})
+	return id, nil
+}
+
+// sampleUnits picks up to n non-empty units spread evenly across the file.
+func sampleUnits(units []*domain.Unit, n int) []*domain.Unit {
+	nonEmpty := make([]*domain.Unit, 0, len(units))
+	for _, u := range units {
+		if strings.TrimSpace(u.SourceText) != "" {
+			nonEmpty = append(nonEmpty, u)
+		}
+	}
+	if len(nonEmpty) <= n {
+		return nonEmpty
+	}
+	out := make([]*domain.Unit, 0, n)
+	step := float64(len(nonEmpty)) / float64(n)
+	for i := 0; i < n; i++ {
+		out = append(out, nonEmpty[int(float64(i)*step)])
+	}
+	return out
+}
+
+func (r *Runner) runDetectLanguage(ctx context.Context, jobID, projectID, providerID int64, p DetectLanguageParams, samples []*domain.Unit) {
+	res := DetectLanguageResult{Votes: map[string]int{}, Samples: len(samples)}
+	total, done := len(samples), 0
+	// job records are written even after a cancel
+	dctx := context.WithoutCancel(ctx)
+	for _, u := range samples {
+		if ctx.Err() != nil {
+			break
+		}
+		item := &domain.JobItem{JobID: jobID, UnitID: &u.ID, Status: "running"}
+		itemID, _ := r.d.Jobs.AddItem(dctx, item)
+		lang, usedLLM, err := r.detectOne(ctx, projectID, providerID, u.SourceText, p)
+		if usedLLM {
+			res.LLMCalls++
+		}
+		if err != nil {
+			_ = r.d.Jobs.UpdateItem(dctx, itemID, "failed", err.Error())
+			r.log(dctx, jobID, "warn", fmt.Sprintf("detect failed: key=%s: %v", u.Key, err))
+		} else {
+			res.Votes[lang]++
+			_ = r.d.Jobs.UpdateItem(dctx, itemID, "done", "")
+		}
+		done++
+		_ = r.d.Jobs.UpdateProgress(dctx, jobID, done, total, "running")
+		r.emitProgress(jobID, done, total, "running", p.Model)
+	}
+	if ctx.Err() != nil {
+		_ = r.d.Jobs.UpdateProgress(dctx, jobID, done, total, "canceled")
+		r.emitProgress(jobID, done, total, "canceled", p.Model)
+		return
+	}
+
+	voted := 0
+	for _, n := range res.Votes {
+		voted += n
+	}
+	if voted == 0 {
+		r.log(dctx, jobID, "error", "no sample could be classified")
+		_ = r.d.Jobs.UpdateProgress(dctx, jobID, done, total, "failed")
+		r.emitProgress(jobID, done, total, "failed", p.Model)
+		return
+	}
+	langs := make([]string, 0, len(res.Votes))
+	for l := range res.Votes {
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if res.Votes[langs[i]] != res.Votes[langs[j]] {
+			return res.Votes[langs[i]] > res.Votes[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	res.Lang = langs[0]
+	res.Confidence = float64(res.Votes[res.Lang]) / float64(voted)
+
+	if p.ApplyTo != "" {
+		if err := r.applyDetectedLanguage(dctx, projectID, p, res.Lang); err != nil {
+			r.log(dctx, jobID, "error", fmt.Sprintf("apply detected language: %v", err))
+		} else {
+			res.Applied = p.ApplyTo
+		}
+	}
+	resultJSON, _ := json.Marshal(res)
+	_ = r.d.Jobs.SetResult(dctx, jobID, string(resultJSON))
+	r.log(
+		dctx,
+		jobID,
+		"info",
+		fmt.Sprintf(
+			"detected language: %s (confidence %.2f, %d/%d votes, llm calls %d)",
+			res.Lang,
+			res.Confidence,
+			res.Votes[res.Lang],
+			voted,
+			res.LLMCalls,
+		),
+	)
+	if r.em != nil {
+		r.em.Emit("job.detect.result", jobDetectResultPayload{JobID: jobID, FileID: p.FileID, Result: res})
+	}
+	_ = r.d.Jobs.UpdateProgress(dctx, jobID, done, total, "done")
+	r.emitProgress(jobID, done, total, "done", p.Model)
+}
+
+// detectOne classifies one sample, consulting the LLM only when the offline detector is
+// unsure (auto mode) or always (llm mode). It reports whether the LLM was called.
+func (r *Runner) detectOne(ctx context.Context, projectID, providerID int64, text string, p DetectLanguageParams) (string, bool, error) {
+	if p.Mode != "llm" && r.d.Detector != nil {
+		det, err := r.d.Detector.Detect(ctx, []string{text})
+		if err == nil && (p.Mode == "offline" || providerID == 0 || det.Confidence >= p.MinConfidence) {
+			return det.Lang, false, nil
+		}
+		if p.Mode == "offline" || providerID == 0 {
+			return "", false, err
+		}
+	}
+	if providerID == 0 {
+		return "", false, errors.New("no offline detector and no provider configured")
+	}
+	ictx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	lang, err := r.trans.DetectLanguage(ictx, translator.DetectArgs{ProviderID: providerID, ProjectID: projectID, Model: p.Model, Text: text})
+	return lang, true, err
+}
+
+func (r *Runner) applyDetectedLanguage(ctx context.Context, projectID int64, p DetectLanguageParams, lang string) error {
+	switch p.ApplyTo {
+	case "file":
+		return r.d.Files.UpdateLocale(ctx, p.FileID, lang)
+	case "project":
+		if r.d.Projects == nil {
+			return errors.New("project repository not configured")
+		}
+		proj, err := r.d.Projects.Get(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		proj.SourceLang = lang
+		return r.d.Projects.Update(ctx, proj)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+// prefixDetector detects "de-a" as de; "amb" is an unsure en and "none" cannot be detected.
+type prefixDetector struct{}
+
+func (prefixDetector) Detect(_ context.Context, samples []string) (ports.Detection, error) {
+	switch s := samples[0]; s {
+	case "amb":
+		return ports.Detection{Lang: "en", Confidence: 0.3}, nil
+	case "none":
+		return ports.Detection{}, errors.New("no letters to detect language from")
+	default:
+		lang, _, _ := strings.Cut(s, "-")
+		return ports.Detection{Lang: lang, Confidence: 0.9}, nil
+	}
+}
+
+// langProvider answers detect_language requests with lang.
+type langProvider struct {
+	fakeProvider
+	lang string
+}
+
+func (p *langProvider) Translate(context.Context, ports.Segment, ports.TranslateParams) (ports.TranslateResult, error) {
+	return ports.TranslateResult{Translation: p.lang}, nil
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		texts    []string
+		mode     string
+		provider bool
+		applyTo  string
+		lang     string
+		conf     float64
+		votes    map[string]int
+		llmCalls int
+	}{
+		{"majority applied to the file", []string{"de-a", "de-b", "de-c", "en-a", "en-b"}, "offline", false, "file", "de", 0.6, map[string]int{"de": 3, "en": 2}, 0},
+		{"applied to the project", []string{"fr-a", "fr-b", "en-a"}, "auto", false, "project", "fr", 2.0 / 3, map[string]int{"fr": 2, "en": 1}, 0},
+		{"unsure samples ask the provider", []string{"de-a", "de-b", "amb"}, "auto", true, "", "de", 1, map[string]int{"de": 3}, 1},
+		{"unsure samples are kept offline", []string{"de-a", "amb", "amb"}, "offline", true, "", "en", 2.0 / 3, map[string]int{"de": 1, "en": 2}, 0},
+		{"undetectable samples get no vote", []string{"de-a", "none"}, "offline", false, "", "de", 1, map[string]int{"de": 1}, 0},
+		{"ties go to the first code", []string{"it-a", "de-a"}, "offline", false, "", "de", 0.5, map[string]int{"de": 1, "it": 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t, 1)
+			env.deps.Detector = prefixDetector{}
+			llm := &langProvider{lang: "de"}
+			env.trans.BuildProvider = func(*domain.Provider) (ports.Provider, error) { return llm, nil }
+			env.build()
+			us := make([]*domain.Unit, len(tt.texts))
+			for i, text := range tt.texts {
+				us[i] = &domain.Unit{FileID: env.file.ID, Key: fmt.Sprintf("k%d", i), SourceText: text}
+			}
+			if err := env.units.UpsertBatch(ctx, us); err != nil {
+				t.Fatal(err)
+			}
+			var providerID int64
+			if tt.provider {
+				providerID = env.provider.ID
+			}
+			id, err := env.r.StartDetectLanguage(ctx, env.project.ID, providerID, DetectLanguageParams{FileID: env.file.ID, Mode: tt.mode, ApplyTo: tt.applyTo})
+			if err != nil {
+				t.Fatal(err)
+			}
+			j := env.wait(t, id)
+			if j.Status != "done" {
+				t.Fatalf("job %s", j.Status)
+			}
+			var res DetectLanguageResult
+			if err := json.Unmarshal([]byte(j.ResultRaw), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Lang != tt.lang || fmt.Sprintf("%.3f", res.Confidence) != fmt.Sprintf("%.3f", tt.conf) || !maps.Equal(res.Votes, tt.votes) || res.LLMCalls != tt.llmCalls {
+				t.Fatalf("got %+v", res)
+			}
+			if res.Samples != len(tt.texts) || res.Applied != tt.applyTo {
+				t.Errorf("samples %d, applied %q", res.Samples, res.Applied)
+			}
+			f, err := env.deps.Files.Get(ctx, env.file.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := env.deps.Projects.Get(ctx, env.project.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantFile, wantProject := "en", "en"
+			switch tt.applyTo {
+			case "file":
+				wantFile = tt.lang
+			case "project":
+				wantProject = tt.lang
+			}
+			if f.Locale != wantFile || p.SourceLang != wantProject {
+				t.Errorf("file locale %q, project source %q; want %q, %q", f.Locale, p.SourceLang, wantFile, wantProject)
+			}
+		})
+	}
+}
+
+func TestStartDetectLanguageChecksParams(t *testing.T) {
+	env := newTestEnv(t, 1)
+	tests := []struct {
+		name       string
+		providerID int64
+		p          DetectLanguageParams
+		err        string
+	}{
+		{"no file", 0, DetectLanguageParams{}, "file_id is required"},
+		{"unknown mode", 0, DetectLanguageParams{FileID: env.file.ID, Mode: "guess"}, "unknown detect mode: guess"},
+		{"llm without provider", 0, DetectLanguageParams{FileID: env.file.ID, Mode: "llm"}, "provider is required for llm detection"},
+		{"unknown target", 0, DetectLanguageParams{FileID: env.file.ID, ApplyTo: "unit"}, "unknown apply_to: unit"},
+	}
+	for _, tt := range tests {
+		if _, err := env.r.StartDetectLanguage(context.Background(), env.project.ID, tt.providerID, tt.p); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"context"
+	"locail/internal/ports"
+	"locail/internal/usecase/translator"
+	"strings"
+	"unicode/utf8"
+)
+
+// Estimate is the expected size, cost and duration of a translate job, computed without
+// calling the provider.
+type Estimate struct {
+	ProviderID int64  `json:"provider_id"`
+	Model      string `json:"model"`
+	// Segments are the unit × locale pairs the job would translate.
+	Segments int `json:"segments"`
+	Chars    int `json:"chars"`
+	Words    int `json:"words"`
+	// Memory counts segments that 100% translation memory matches would fill for free.
+	Memory int `json:"memory"`
+	// Outdated counts segments whose existing translation a forced job replaces.
+	Outdated     int `json:"outdated"`
+	Requests     int `json:"requests"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// Sampled is the number of requests whose prompts were rendered to measure their size.
+	Sampled int `json:"sampled"`
+	// Cost is in USD; nil when the model's price is unknown.
+	Cost *float64 `json:"cost"`
+	// Seconds is the expected duration from recent jobs of the model; nil without history.
+	Seconds *float64 `json:"seconds"`
+	// Fallbacks are the job's fallback providers in the order they are tried.
+	Fallbacks []FallbackEstimate `json:"fallbacks,omitempty"`
+}
+
+// FallbackEstimate is what a fallback provider would cost if it had to translate every
+// segment; Cost is nil when its price is unknown.
+type FallbackEstimate struct {
+	ProviderID int64    `json:"provider_id"`
+	Model      string   `json:"model"`
+	Cost       *float64 `json:"cost"`
+}
+
+// EstimateTranslateFile estimates a StartTranslateFile job with the same parameters.
+func (r *Runner) EstimateTranslateFile(ctx context.Context, projectID, providerID int64, p TranslateFileParams) (Estimate, error) {
+	if err := checkMode(p.Mode, p.SystemPrompt, p.UserPrompt); err != nil {
+		return Estimate{}, err
+	}
+	p.Model = r.resolveModel(ctx, providerID, p.Model)
+	if err := r.checkOptions(ctx, providerID, p.Options); err != nil {
+		return Estimate{}, err
+	}
+	fallbacks, err := r.resolveFallbacks(ctx, p.Fallbacks)
+	if err != nil {
+		return Estimate{}, err
+	}
+	units, err := r.d.Units.ListByFile(ctx, p.FileID)
+	if err != nil {
+		return Estimate{}, err
+	}
+	task := r.newTranslateTask(projectID, providerID, p.Model, false, p.UseTM, p.SystemPrompt, p.UserPrompt, fallbacks, p.Options)
+	return r.estimate(ctx, &task, r.pendingItems(ctx, units, p.TargetLocales, false), p.Mode == "batch")
+}
+
+// EstimateTranslateUnits estimates a StartTranslateUnits job with the same parameters.
+func (r *Runner) EstimateTranslateUnits(ctx context.Context, projectID, providerID int64, p TranslateUnitsParams) (Estimate, error) {
+	if err := checkMode(p.Mode, p.SystemPrompt, p.UserPrompt); err != nil {
+		return Estimate{}, err
+	}
+	p.Model = r.resolveModel(ctx, providerID, p.Model)
+	if err := r.checkOptions(ctx, providerID, p.Options); err != nil {
+		return Estimate{}, err
+	}
+	fallbacks, err := r.resolveFallbacks(ctx, p.Fallbacks)
+	if err != nil {
+		return Estimate{}, err
+	}
+	task := r.newTranslateTask(projectID, providerID, p.Model, p.Force, p.UseTM, p.SystemPrompt, p.UserPrompt, fallbacks, p.Options)
+	return r.estimate(ctx, &task, r.pendingItems(ctx, r.units(ctx, p.UnitIDs), p.Locales, p.Force), p.Mode == "batch")
+}
+
+func (r *Runner) estimate(ctx context.Context, t *translateTask, items []workItem, batch bool) (Estimate, error) {
+	est := Estimate{ProviderID: t.providerID, Model: t.model, Segments: len(items)}
+	var args []translator.TranslateArgs
+	for _, it := range items {
+		est.Chars += utf8.RuneCountInString(it.u.SourceText)
+		est.Words += len(strings.Fields(it.u.SourceText))
+		if t.bypass {
+			if tr, _ := r.d.Translations.Get(ctx, it.u.ID, it.locale); tr != nil && strings.TrimSpace(tr.Text) != "" {
+				est.Outdated++
+			}
+		}
+		pc, err := t.res.Resolve(ctx, it.u)
+		if err != nil {
+			continue
+		}
+		if t.useTM {
+			if _, err := r.d.Memory.Exact(ctx, pc.SourceLang, it.locale, it.u.SourceText); err == nil {
+				est.Memory++
+				continue
+			}
+		}
+		args = append(args, t.args(it.u, it.locale, &pc))
+	}
+	tokens, err := r.trans.EstimateTokens(ctx, args, batch)
+	if err != nil {
+		return est, err
+	}
+	est.Requests, est.InputTokens, est.OutputTokens, est.Sampled = tokens.Requests, tokens.InputTokens, tokens.OutputTokens, tokens.Sampled
+	for _, fb := range t.fallbacks {
+		est.Fallbacks = append(est.Fallbacks, FallbackEstimate{ProviderID: fb.ProviderID, Model: fb.Model, Cost: r.estimateCost(ctx, fb.ProviderID, fb.Model, est)})
+	}
+	if r.d.Usage == nil {
+		return est, nil
+	}
+	est.Cost = r.estimateCost(ctx, t.providerID, t.model, est)
+	if d, ok := r.d.Usage.ItemDuration(ctx, t.providerID, t.model); ok {
+		secs := d.Seconds() * float64(len(args))
+		est.Seconds = &secs
+	}
+	return est, nil
+}
+
+// estimateCost prices the estimated tokens with a provider's model; nil when unknown.
+func (r *Runner) estimateCost(ctx context.Context, providerID int64, model string, est Estimate) *float64 {
+	if r.d.Usage == nil {
+		return nil
+	}
+	price, ok := r.d.Usage.Price(ctx, providerID, model)
+	if !ok {
+		return nil
+	}
+	cost := price.Cost(ports.Usage{InputTokens: est.InputTokens, OutputTokens: est.OutputTokens})
+	return &cost
+}
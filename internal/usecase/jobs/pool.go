@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is used for providers without a configured worker count.
+const DefaultConcurrency = 2
+
+// slots returns the semaphore limiting in-flight requests to a provider across all jobs.
+// It is recreated when the provider's concurrency changes; requests holding a slot of the
+// previous semaphore release it there.
+func (r *Runner) slots(providerID int64, n int) chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sem == nil {
+		r.sem = map[int64]chan struct{}{}
+	}
+	if ch, ok := r.sem[providerID]; ok && cap(ch) == n {
+		return ch
+	}
+	ch := make(chan struct{}, n)
+	r.sem[providerID] = ch
+	return ch
+}
+
+// concurrency returns the configured worker count of a provider.
+func (r *Runner) concurrency(ctx context.Context, providerID int64) int {
+	if prov, err := r.d.Providers.Get(ctx, providerID); err == nil && prov != nil && prov.Concurrency > 0 {
+		return prov.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+// runPool calls work for indexes 0..n-1 on up to the provider's concurrency. Work runs
+// concurrently and returns a commit function; commits run one at a time on the calling
+// goroutine in completion order, so results have a single SQLite writer per job and progress
+// never goes backwards. Workers wait for g before taking a provider slot, so a paused job
+// does not hold slots other jobs could use. It returns false when ctx was canceled before
+// all work was done.
+func (r *Runner) runPool(ctx context.Context, providerID int64, n int, g *breakerGuard, work func(ctx context.Context, i int) func()) bool {
+	workers := r.concurrency(ctx, providerID)
+	sem := r.slots(providerID, workers)
+	workers = min(workers, n)
+	next := make(chan int)
+	commits := make(chan func())
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if !g.acquire(ctx, sem) {
+					continue
+				}
+				commit := work(ctx, i)
+				<-sem
+				if commit != nil {
+					commits <- commit
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(next)
+		for i := 0; i < n; i++ {
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(commits)
+	}()
+	for commit := range commits {
+		commit()
+	}
+	return ctx.Err() == nil
+}
+
+// jobProgress counts finished items of a job and tracks its status. It is safe for
+// concurrent use.
+type jobProgress struct {
+	r      *Runner
+	jobID  int64
+	total  int
+	model  string
+	mu     sync.Mutex
+	done   int
+	status string
+}
+
+func (p *jobProgress) step(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.save(ctx)
+}
+
+// setStatus switches between running and paused.
+func (p *jobProgress) setStatus(ctx context.Context, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.status == status {
+		return
+	}
+	p.status = status
+	p.save(ctx)
+}
+
+// finish records the final status.
+func (p *jobProgress) finish(ctx context.Context, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+	p.save(ctx)
+}
+
+func (p *jobProgress) save(ctx context.Context) {
+	if p.status == "" {
+		p.status = "running"
+	}
+	_ = p.r.d.Jobs.UpdateProgress(context.WithoutCancel(ctx), p.jobID, p.done, p.total, p.status)
+	p.r.emitProgress(p.jobID, p.done, p.total, p.status, p.model)
+}
@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"locail/internal/usecase/throttle"
+)
+
+func newGuard(r *Runner) *breakerGuard {
+	return &breakerGuard{r: r, b: throttle.NewBreaker(), prog: &jobProgress{r: r}, abort: func() {}}
+}
+
+func TestRunPool(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		n           int
+		peak        int32
+	}{
+		{"capped by the provider", 3, 20, 3},
+		{"fewer items than workers", 5, 2, 2},
+		{"single worker", 1, 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, tt.concurrency)
+			var inCommit, overlaps atomic.Int32
+			var committed []int
+			ok := env.r.runPool(context.Background(), env.provider.ID, tt.n, newGuard(env.r), func(ctx context.Context, i int) func() {
+				if err := env.fake.begin(ctx); err != nil {
+					t.Error(err)
+				}
+				return func() {
+					if inCommit.Add(1) > 1 {
+						overlaps.Add(1)
+					}
+					committed = append(committed, i)
+					time.Sleep(time.Millisecond)
+					inCommit.Add(-1)
+				}
+			})
+			if !ok {
+				t.Fatal("runPool reported a cancel")
+			}
+			if len(committed) != tt.n {
+				t.Fatalf("%d commits, want %d", len(committed), tt.n)
+			}
+			if overlaps.Load() != 0 {
+				t.Fatalf("%d commits overlapped", overlaps.Load())
+			}
+			if p := env.fake.peak.Load(); p != tt.peak {
+				t.Fatalf("peak concurrency %d, want %d", p, tt.peak)
+			}
+		})
+	}
+}
+
+func TestRunPoolSharesProviderSlots(t *testing.T) {
+	env := newTestEnv(t, 2)
+	var wg sync.WaitGroup
+	for job := 0; job < 3; job++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			env.r.runPool(context.Background(), env.provider.ID, 6, newGuard(env.r), func(ctx context.Context, i int) func() {
+				_ = env.fake.begin(ctx)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if p := env.fake.peak.Load(); p != 2 {
+		t.Fatalf("peak concurrency across jobs %d, want 2", p)
+	}
+	if s := env.fake.started.Load(); s != 18 {
+		t.Fatalf("%d requests, want 18", s)
+	}
+}
+
+func TestRunPoolCancel(t *testing.T) {
+	env := newTestEnv(t, 2)
+	env.fake.release = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	var afterCancel atomic.Int32
+	done := make(chan bool)
+	go func() {
+		done <- env.r.runPool(ctx, env.provider.ID, 50, newGuard(env.r), func(ctx context.Context, i int) func() {
+			if ctx.Err() != nil {
+				afterCancel.Add(1)
+			}
+			_ = env.fake.begin(ctx)
+			return func() {}
+		})
+	}()
+	for env.fake.inFlight.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if <-done {
+		t.Fatal("runPool reported success after a cancel")
+	}
+	if s := env.fake.started.Load(); s != 2 {
+		t.Fatalf("%d requests started, want only the 2 in flight", s)
+	}
+	if n := afterCancel.Load(); n != 0 {
+		t.Fatalf("%d work items started after the cancel", n)
+	}
+}
+
+func TestTranslateFileJobOnPool(t *testing.T) {
+	for _, mode := range []string{"per_key", "batch"} {
+		t.Run(mode, func(t *testing.T) {
+			env := newTestEnv(t, 3)
+			env.addUnits(t, 12)
+			ctx := context.Background()
+			id, err := env.r.StartTranslateFile(ctx, env.project.ID, env.provider.ID, TranslateFileParams{FileID: env.file.ID, TargetLocales: []string{"de", "fr"}, Mode: mode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			j := env.wait(t, id)
+			if j.Status != "done" || j.Progress != 24 || j.Total != 24 {
+				t.Fatalf("job %s %d/%d, want done 24/24", j.Status, j.Progress, j.Total)
+			}
+			if p := env.fake.peak.Load(); p > 3 {
+				t.Fatalf("peak concurrency %d over the provider's 3", p)
+			}
+			trs, _ := env.translations.ListByFileLocale(ctx, env.file.ID, "fr")
+			if len(trs) != 12 {
+				t.Fatalf("%d fr translations stored, want 12", len(trs))
+			}
+			for _, tr := range trs {
+				if !strings.HasPrefix(tr.Text, "T text ") {
+					t.Fatalf("stored %q", tr.Text)
+				}
+			}
+			assertProgressEvents(t, env.events.list(), id, 24, "done")
+		})
+	}
+}
+
+func TestTranslateFileJobCancel(t *testing.T) {
+	env := newTestEnv(t, 2)
+	env.fake.release = make(chan struct{})
+	env.addUnits(t, 10)
+	id, err := env.r.StartTranslateFile(context.Background(), env.project.ID, env.provider.ID, TranslateFileParams{FileID: env.file.ID, TargetLocales: []string{"de"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for env.fake.inFlight.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if !env.r.Cancel(id) {
+		t.Fatal("job not running")
+	}
+	j := env.wait(t, id)
+	if j.Status != "canceled" {
+		t.Fatalf("status %s, want canceled", j.Status)
+	}
+	if s := env.fake.started.Load(); s != 2 {
+		t.Fatalf("%d requests, want no new ones after the cancel", s)
+	}
+	items, _ := env.jobs.ListItems(context.Background(), id)
+	if j.Progress != len(items) || j.Total != 10 {
+		t.Fatalf("progress %d/%d with %d items", j.Progress, j.Total, len(items))
+	}
+	assertProgressEvents(t, env.events.list(), id, j.Progress, "canceled")
+}
+
+// assertProgressEvents checks that a job's progress events never go backwards and end with
+// done items in status.
+func assertProgressEvents(t *testing.T, events []jobProgressPayload, jobID int64, done int, status string) {
+	t.Helper()
+	last := -1
+	var final jobProgressPayload
+	for _, e := range events {
+		if e.JobID != jobID {
+			continue
+		}
+		if e.Done < last {
+			t.Fatalf("progress went back from %d to %d", last, e.Done)
+		}
+		last, final = e.Done, e
+	}
+	if final.Done != done || final.Status != status {
+		t.Fatalf("last progress event %+v, want %d %s", final, done, status)
+	}
+}
@@ -3,11 +3,15 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"locail/internal/adapters/llm/factory"
 	"locail/internal/domain"
 	"locail/internal/ports"
+	"locail/internal/usecase/throttle"
+	"locail/internal/usecase/tm"
 	"locail/internal/usecase/translator"
+	"locail/internal/usecase/usage"
 	"strings"
 	"sync"
 	"time"
@@ -15,12 +19,18 @@ import (
 
 type Deps struct {
 	Jobs         ports.JobRepository
+	Projects     ports.ProjectRepository
 	Files        ports.FileRepository
 	Units        ports.UnitRepository
 	Providers    ports.ProviderRepository
 	Translations ports.TranslationRepository
 	Prompt       ports.PromptRenderer
 	Cache        ports.CacheRepository
+	Detector     ports.LanguageDetector
+	Memory       *tm.Service
+	Usage        *usage.Service
+	// ValidateOptions checks options_json for a provider type; nil skips the check.
+	ValidateOptions func(providerType, options string) error
 }
 
 type Runner struct {
@@ -29,6 +39,8 @@ type Runner struct {
 	mu     sync.Mutex
 	active map[int64]context.CancelFunc
 	em     EventEmitter
+	// sem limits concurrent requests per provider; see slots.
+	sem map[int64]chan struct{}
 }
 
 func NewRunner(d Deps, trans *translator.Service) *Runner {
@@ -73,12 +85,31 @@ type jobItemDonePayload struct {
 	Text   string `json:"text,omitempty"`
 	Error  string `json:"error,omitempty"`
 	Model  string `json:"model"`
+	Status string `json:"status,omitempty"`
+	// TermIssues lists glossary violations of the stored translation.
+	TermIssues []domain.TermIssue `json:"term_issues,omitempty"`
 }
 
 type TranslateFileParams struct {
 	FileID        int64    `json:"file_id"`
 	TargetLocales []string `json:"target_locales"`
 	Model         string   `json:"model"`
+	// UseTM applies 100% translation memory matches instead of calling the provider.
+	UseTM bool `json:"use_tm"`
+	// Mode is "per_key" (default, one request per unit) or "batch" (many units per request,
+	// without prompt overrides).
+	Mode string `json:"mode,omitempty"`
+	// SystemPrompt and UserPrompt override the stored templates for this job.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	UserPrompt   string `json:"user_prompt,omitempty"`
+	// Fallbacks are providers and models tried in order for items the job's provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks,omitempty"`
+	// Options override the provider's options_json for this job, e.g. temperature or
+	// timeout_seconds.
+	Options json.RawMessage `json:"options,omitempty"`
+	// Budget caps the job's cost in USD; once reached no new requests are sent and the job
+	// ends canceled. 0 means no cap.
+	Budget float64 `json:"budget,omitempty"`
 }
 
 type TranslateUnitParams struct {
@@ -86,6 +117,15 @@ type TranslateUnitParams struct {
 	Locales []string `json:"locales"`
 	Model   string   `json:"model"`
 	Force   bool     `json:"force"`
+	UseTM   bool     `json:"use_tm"`
+	// SystemPrompt and UserPrompt override the stored templates for this job.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	UserPrompt   string `json:"user_prompt,omitempty"`
+	// Fallbacks are providers and models tried in order for items the job's provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks,omitempty"`
+	// Options override the provider's options_json for this job, e.g. temperature or
+	// timeout_seconds.
+	Options json.RawMessage `json:"options,omitempty"`
 }
 
 // TranslateUnitsParams describes a batch of specific units to translate sequentially.
@@ -94,10 +134,39 @@ type TranslateUnitsParams struct {
 	Locales []string `json:"locales"`
 	Model   string   `json:"model"`
 	Force   bool     `json:"force"`
+	UseTM   bool     `json:"use_tm"`
+	// Mode is "per_key" (default, one request per unit) or "batch" (many units per request,
+	// without prompt overrides).
+	Mode string `json:"mode,omitempty"`
+	// SystemPrompt and UserPrompt override the stored templates for this job.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	UserPrompt   string `json:"user_prompt,omitempty"`
+	// Fallbacks are providers and models tried in order for items the job's provider fails.
+	Fallbacks []translator.Fallback `json:"fallbacks,omitempty"`
+	// Options override the provider's options_json for this job, e.g. temperature or
+	// timeout_seconds.
+	Options json.RawMessage `json:"options,omitempty"`
+	// Budget caps the job's cost in USD; once reached no new requests are sent and the job
+	// ends canceled. 0 means no cap.
+	Budget float64 `json:"budget,omitempty"`
 }
 
 func (r *Runner) StartTranslateFile(ctx context.Context, projectID, providerID int64, params TranslateFileParams) (int64, error) {
+	if err := checkMode(params.Mode, params.SystemPrompt, params.UserPrompt); err != nil {
+		return 0, err
+	}
 	params.Model = r.resolveModel(ctx, providerID, params.Model)
+	if err := r.checkOptions(ctx, providerID, params.Options); err != nil {
+		return 0, err
+	}
+	fallbacks, err := r.resolveFallbacks(ctx, params.Fallbacks)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.checkBudgets(ctx, providerID, params.Model, fallbacks, params.Budget); err != nil {
+		return 0, err
+	}
+	params.Fallbacks = fallbacks
 	paramsJSON, _ := json.Marshal(params)
 	job := &domain.Job{Type: "translate_file", Status: "queued", ProjectID: &projectID, ProviderID: &providerID, ParamsRaw: string(paramsJSON), Progress: 0, Total: 0}
 	jobID, err := r.d.Jobs.Create(ctx, job)
@@ -105,19 +174,7 @@ func (r *Runner) StartTranslateFile(ctx context.Context, projectID, providerID i
 		return 0, err
 	}
 	units, _ := r.d.Units.ListByFile(ctx, params.FileID)
-	total := 0
-	for _, tgt := range params.TargetLocales {
-		trs, _ := r.d.Translations.ListByFileLocale(ctx, params.FileID, tgt)
-		have := make(map[int64]struct{}, len(trs))
-		for _, t := range trs {
-			have[t.UnitID] = struct{}{}
-		}
-		for _, u := range units {
-			if _, ok := have[u.ID]; !ok {
-				total++
-			}
-		}
-	}
+	total := len(r.pendingItems(ctx, units, params.TargetLocales, false))
 	_ = r.d.Jobs.UpdateProgress(ctx, jobID, 0, total, "running")
 	r.emitStarted(jobID, total, params.Model, providerID)
 	r.log(
@@ -133,7 +190,7 @@ func (r *Runner) StartTranslateFile(ctx context.Context, projectID, providerID i
 		),
 	)
 	r.startAsync(jobID, func(runCtx context.Context) {
-		r.runTranslateFile(runCtx, jobID, providerID, params)
+		r.runTranslateFile(runCtx, jobID, projectID, providerID, params)
 	})
 	return jobID, nil
 }
@@ -186,6 +243,48 @@ func (r *Runner) resolveModel(ctx context.Context, providerID int64, model strin
 	return out
 }
 
+// checkMode checks a job's mode. Batch requests render the translate_file templates, which
+// the job's translate_single overrides do not replace, so batch jobs take none.
+func checkMode(mode, systemPrompt, userPrompt string) error {
+	switch mode {
+	case "", "per_key":
+		return nil
+	case "batch":
+		if systemPrompt != "" || userPrompt != "" {
+			return errors.New("prompt overrides are not supported in batch mode")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown translate mode %q", mode)
+}
+
+// resolveFallbacks checks that the fallback providers exist and fills in their models.
+func (r *Runner) resolveFallbacks(ctx context.Context, fallbacks []translator.Fallback) ([]translator.Fallback, error) {
+	if len(fallbacks) == 0 {
+		return nil, nil
+	}
+	out := make([]translator.Fallback, 0, len(fallbacks))
+	for _, fb := range fallbacks {
+		if err := r.checkOptions(ctx, fb.ProviderID, fb.Options); err != nil {
+			return nil, fmt.Errorf("fallback provider %d: %w", fb.ProviderID, err)
+		}
+		out = append(out, translator.Fallback{ProviderID: fb.ProviderID, Model: r.resolveModel(ctx, fb.ProviderID, fb.Model), Options: fb.Options})
+	}
+	return out, nil
+}
+
+// checkOptions checks a job's provider options override against the provider type.
+func (r *Runner) checkOptions(ctx context.Context, providerID int64, options json.RawMessage) error {
+	prov, err := r.d.Providers.Get(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 || r.d.ValidateOptions == nil {
+		return nil
+	}
+	return r.d.ValidateOptions(prov.Type, string(options))
+}
+
 // startAsync stores a cancel func and runs fn in a new goroutine with a cancellable context.
 func (r *Runner) startAsync(jobID int64, fn func(ctx context.Context)) {
 	cctx, cancel := context.WithCancel(context.Background())
@@ -247,12 +346,64 @@ func (r *Runner) beginJobItem(ctx context.Context, jobID int64, u *domain.Unit,
 	return itemID
 }
 
-func (r *Runner) endJobItemSuccess(ctx context.Context, jobID, itemID int64, u *domain.Unit, locale, model, text string) {
-	tr := &domain.Translation{UnitID: u.ID, Locale: locale, Text: text, Status: "machine"}
+// endJobItemSuccess stores a translated item and returns its cost in USD; 0 when unknown.
+func (r *Runner) endJobItemSuccess(ctx context.Context, jobID, itemID int64, u *domain.Unit, locale, model string, out translateOutcome) float64 {
+	text, status := out.text, out.status
+	tr := &domain.Translation{UnitID: u.ID, Locale: locale, Text: text, Status: status}
 	_ = r.d.Translations.Upsert(ctx, tr)
 	_ = r.d.Jobs.UpdateItem(ctx, itemID, "done", "")
+	if meta, ok := out.meta(); ok {
+		_ = r.d.Jobs.SetItemMeta(ctx, itemID, meta)
+	}
+	var cost float64
+	if out.providerID != 0 && r.d.Usage != nil {
+		if c, err := r.d.Usage.Record(ctx, itemID, out.providerID, out.model, out.usage); err == nil && c != nil {
+			cost = *c
+		}
+	}
+	if len(out.fallbacks) > 0 {
+		model = out.model
+		r.log(
+			ctx,
+			jobID,
+			"warn",
+			fmt.Sprintf(
+				"fallback: key=%s locale=%s provider=%d model=%s after %d failed",
+				u.Key,
+				locale,
+				out.providerID,
+				out.model,
+				len(out.fallbacks),
+			),
+		)
+	}
+	for _, is := range out.issues {
+		r.log(
+			ctx,
+			jobID,
+			"warn",
+			fmt.Sprintf(
+				"glossary %s: key=%s locale=%s term=%q expected=%q found=%q",
+				is.Kind,
+				u.Key,
+				locale,
+				is.Term,
+				is.Expected,
+				is.Found,
+			),
+		)
+	}
 	if r.em != nil {
-		payload := jobItemDonePayload{JobID: jobID, UnitID: u.ID, Key: u.Key, Locale: locale, Text: text, Model: model}
+		payload := jobItemDonePayload{
+			JobID:      jobID,
+			UnitID:     u.ID,
+			Key:        u.Key,
+			Locale:     locale,
+			Text:       text,
+			Model:      model,
+			Status:     status,
+			TermIssues: out.issues,
+		}
 		r.em.Emit(
 			"job.item.done",
 			payload,
@@ -263,12 +414,14 @@ func (r *Runner) endJobItemSuccess(ctx context.Context, jobID, itemID int64, u *
 		jobID,
 		"info",
 		fmt.Sprintf(
-			"translate done: key=%s locale=%s len=%d",
+			"translate done: key=%s locale=%s len=%d status=%s",
 			u.Key,
 			locale,
 			len(text),
+			status,
 		),
 	)
+	return cost
 }
 
 func (r *Runner) endJobItemError(ctx context.Context, jobID, itemID int64, u *domain.Unit, locale, model string, err error) {
@@ -297,23 +450,402 @@ func (r *Runner) endJobItemError(ctx context.Context, jobID, itemID int64, u *do
 	}
 }
 
-func (r *Runner) translateWithTimeout(ctx context.Context, providerID int64, u *domain.Unit, locale, model string, bypass bool) (string, error) {
-	ictx, cancel := context.WithTimeout(ctx, 60*time.Second)
+// translateTask carries the per-job settings shared by all items of a translate job.
+type translateTask struct {
+	projectID  int64
+	providerID int64
+	model      string
+	bypass     bool
+	useTM      bool
+	system     string
+	user       string
+	// contextTokens is the model's context window, looked up on the first batch.
+	contextTokens int
+	res           *translator.ContextResolver
+	// breaker pauses the job while the provider keeps failing.
+	breaker   *throttle.Breaker
+	fallbacks []translator.Fallback
+	options   json.RawMessage
+	// budget stops the job once its items cost too much; nil without a cap.
+	budget *jobBudget
+}
+
+func (r *Runner) newTranslateTask(projectID, providerID int64, model string, bypass, useTM bool, system, user string, fallbacks []translator.Fallback, options json.RawMessage) translateTask {
+	return translateTask{
+		projectID:  projectID,
+		providerID: providerID,
+		model:      model,
+		bypass:     bypass,
+		useTM:      useTM && r.d.Memory != nil,
+		system:     system,
+		user:       user,
+		res:        r.trans.NewContextResolver(),
+		breaker:    throttle.NewBreaker(),
+		fallbacks:  fallbacks,
+		options:    options,
+	}
+}
+
+// translateOutcome is a translated unit ready to be stored.
+type translateOutcome struct {
+	text     string
+	status   string
+	issues   []domain.TermIssue
+	examples []domain.TMMatch
+	prompts  []ports.RenderedPrompt
+	// providerID and model produced the text; 0 for memory matches.
+	providerID int64
+	model      string
+	usage      ports.Usage
+	fallbacks  []translator.Attempt
+}
+
+// jobItemMeta is stored with a job item to audit how its translation was produced.
+type jobItemMeta struct {
+	ProviderID int64             `json:"provider_id,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	Fallbacks  []jobItemAttempt  `json:"fallbacks,omitempty"`
+	Examples   []jobItemExample  `json:"examples,omitempty"`
+	Templates  []jobItemTemplate `json:"templates,omitempty"`
+}
+
+// jobItemAttempt is a provider of the fallback chain that did not produce the translation.
+type jobItemAttempt struct {
+	ProviderID int64  `json:"provider_id"`
+	Model      string `json:"model,omitempty"`
+	Error      string `json:"error"`
+}
+
+// jobItemTemplate records where a prompt came from; TemplateID is 0 for overrides and builtins.
+type jobItemTemplate struct {
+	Role       string `json:"role"`
+	Source     string `json:"source"`
+	TemplateID int64  `json:"template_id,omitempty"`
+}
+
+type jobItemExample struct {
+	TMID        int64  `json:"tm_id"`
+	UnitID      *int64 `json:"unit_id,omitempty"`
+	Score       int    `json:"score"`
+	Source      string `json:"source"`
+	Translation string `json:"translation"`
+}
+
+func (o translateOutcome) meta() (string, bool) {
+	if len(o.examples) == 0 && len(o.prompts) == 0 && o.providerID == 0 {
+		return "", false
+	}
+	m := jobItemMeta{ProviderID: o.providerID, Model: o.model}
+	for _, f := range o.fallbacks {
+		m.Fallbacks = append(m.Fallbacks, jobItemAttempt{ProviderID: f.ProviderID, Model: f.Model, Error: f.Err})
+	}
+	for i, p := range o.prompts {
+		if p.Source == "" {
+			// promptless providers render no templates
+			continue
+		}
+		m.Templates = append(m.Templates, jobItemTemplate{Role: promptRoles[i], Source: p.Source, TemplateID: p.TemplateID})
+	}
+	for _, e := range o.examples {
+		m.Examples = append(m.Examples, jobItemExample{
+			TMID:        e.Entry.ID,
+			UnitID:      e.Entry.UnitID,
+			Score:       e.Score,
+			Source:      e.Entry.SourceText,
+			Translation: e.Entry.TargetText,
+		})
+	}
+	b, _ := json.Marshal(m)
+	return string(b), true
+}
+
+// promptRoles names the entries of translateOutcome.prompts.
+var promptRoles = []string{"system", "user"}
+
+// itemTimeout bounds the translation of one unit by one provider, unless the provider's
+// timeout_seconds is longer.
+const itemTimeout = 60 * time.Second
+
+// translateWithTimeout translates one unit. The outcome status is "tm" when a 100% memory
+// match was applied, "needs_review" when the output violates the glossary, otherwise "machine".
+func (r *Runner) translateWithTimeout(ctx context.Context, t translateTask, u *domain.Unit, locale string) (translateOutcome, error) {
+	ictx, cancel := context.WithTimeout(ctx, r.trans.ChainTimeout(ctx, t.args(u, locale, nil), itemTimeout))
 	defer cancel()
-	return r.trans.TranslateOne(
-		ictx,
-		translator.TranslateArgs{
-			ProviderID:  providerID,
-			Unit:        u,
-			SourceLang:  "",
-			TargetLang:  locale,
-			Model:       model,
-			BypassCache: bypass,
-		},
-	)
+	pc, err := t.res.Resolve(ictx, u)
+	if err != nil {
+		return translateOutcome{}, err
+	}
+	if t.useTM {
+		if e, err := r.d.Memory.Exact(ictx, pc.SourceLang, locale, u.SourceText); err == nil {
+			return translateOutcome{text: e.TargetText, status: "tm"}, nil
+		}
+	}
+	res, err := r.trans.Translate(ictx, t.args(u, locale, &pc))
+	if err != nil {
+		return translateOutcome{}, err
+	}
+	return newOutcome(res), nil
+}
+
+// args builds the translator arguments of a unit; pc may be nil when only the provider chain
+// matters, e.g. for timeouts.
+func (t translateTask) args(u *domain.Unit, locale string, pc *translator.PromptContext) translator.TranslateArgs {
+	var sourceLang string
+	if pc != nil {
+		sourceLang = pc.SourceLang
+	}
+	return translator.TranslateArgs{
+		ProviderID:     t.providerID,
+		ProjectID:      t.projectID,
+		Unit:           u,
+		SourceLang:     sourceLang,
+		TargetLang:     locale,
+		Model:          t.model,
+		SystemOverride: t.system,
+		UserOverride:   t.user,
+		BypassCache:    t.bypass,
+		Context:        pc,
+		Fallbacks:      t.fallbacks,
+		Timeout:        itemTimeout,
+		Options:        t.options,
+	}
+}
+
+func newOutcome(res translator.Result) translateOutcome {
+	out := translateOutcome{
+		text:       res.Text,
+		status:     "machine",
+		issues:     res.TermIssues,
+		examples:   res.Examples,
+		prompts:    []ports.RenderedPrompt{res.System, res.User},
+		providerID: res.ProviderID,
+		model:      res.Model,
+		usage:      res.Usage,
+		fallbacks:  res.Fallbacks,
+	}
+	if len(out.issues) > 0 {
+		out.status = "needs_review"
+	}
+	return out
+}
+
+// workItem is one unit to translate into one locale.
+type workItem struct {
+	u      *domain.Unit
+	locale string
+}
+
+// translateItems translates items on the provider's worker pool, per key or in batches,
+// storing results and advancing progress. It returns the final job status: done, canceled,
+// or failed when the circuit breaker gave up on the provider.
+func (r *Runner) translateItems(ctx context.Context, jobID int64, t *translateTask, items []workItem, batch bool, prog *jobProgress) string {
+	pctx, abort := context.WithCancel(ctx)
+	defer abort()
+	g := &breakerGuard{r: r, jobID: jobID, b: t.breaker, prog: prog, abort: abort}
+	var ok bool
+	if batch {
+		ok = r.translateBatches(pctx, jobID, t, items, prog, g)
+	} else {
+		ok = r.runPool(pctx, t.providerID, len(items), g, func(ctx context.Context, i int) func() {
+			if !t.budget.allow() {
+				return nil
+			}
+			it := items[i]
+			itemID := r.beginJobItem(ctx, jobID, it.u, it.locale, t.model)
+			out, err := r.translateWithTimeout(ctx, *t, it.u, it.locale)
+			g.record(ctx, err)
+			return func() {
+				dctx := context.WithoutCancel(ctx)
+				if err != nil {
+					r.endJobItemError(dctx, jobID, itemID, it.u, it.locale, t.model, err)
+				} else {
+					r.spend(dctx, jobID, t, r.endJobItemSuccess(dctx, jobID, itemID, it.u, it.locale, t.model, out))
+				}
+				prog.step(ctx)
+			}
+		})
+	}
+	switch {
+	case t.breaker.Exhausted():
+		return "failed"
+	case !ok, t.budget.stopped():
+		return "canceled"
+	}
+	return "done"
 }
 
-func (r *Runner) runTranslateFile(ctx context.Context, jobID, providerID int64, p TranslateFileParams) {
+// breakerGuard applies a job's circuit breaker to its workers.
+type breakerGuard struct {
+	r     *Runner
+	jobID int64
+	b     *throttle.Breaker
+	prog  *jobProgress
+	abort context.CancelFunc
+}
+
+// wait blocks while the breaker is open, marking the job paused. It returns false when the
+// job should stop.
+func (g *breakerGuard) wait(ctx context.Context) bool {
+	if d := g.b.Open(); d > 0 {
+		g.prog.setStatus(ctx, "paused")
+		if throttle.Sleep(ctx, d) != nil {
+			return false
+		}
+		g.prog.setStatus(ctx, "running")
+	}
+	return ctx.Err() == nil
+}
+
+// acquire takes a slot of sem once the breaker is closed. A slot taken while the breaker
+// opened again, or after the job was canceled, is given back.
+func (g *breakerGuard) acquire(ctx context.Context, sem chan struct{}) bool {
+	for {
+		if !g.wait(ctx) {
+			return false
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+		if ctx.Err() != nil {
+			<-sem
+			return false
+		}
+		if g.b.Open() == 0 {
+			return true
+		}
+		<-sem
+	}
+}
+
+// record feeds the outcome of a request to the breaker. Errors of single units, such as
+// failed placeholder checks, do not count against the provider.
+func (g *breakerGuard) record(ctx context.Context, err error) {
+	if !translator.IsProviderFailure(err) || ctx.Err() != nil {
+		if err == nil {
+			g.b.Success()
+		}
+		return
+	}
+	d := g.b.Failure()
+	if d == 0 {
+		return
+	}
+	lctx := context.WithoutCancel(ctx)
+	if g.b.Exhausted() {
+		g.r.log(lctx, g.jobID, "error", fmt.Sprintf("provider keeps failing, stopping job: %v", err))
+		g.abort()
+		return
+	}
+	g.r.log(lctx, g.jobID, "warn", fmt.Sprintf("provider keeps failing, pausing job for %s: %v", d.Round(time.Second), err))
+	g.prog.setStatus(ctx, "paused")
+}
+
+// translateBatches groups items per source and target language into batches sized to the
+// model's context window and translates the batches on the worker pool.
+func (r *Runner) translateBatches(ctx context.Context, jobID int64, t *translateTask, items []workItem, prog *jobProgress, g *breakerGuard) bool {
+	if t.contextTokens == 0 {
+		t.contextTokens = r.trans.ContextTokens(ctx, t.providerID, t.model, t.options)
+	}
+	type batch struct {
+		locale string
+		units  []*domain.Unit
+	}
+	// units whose context fails to resolve form their own group and fail in the worker
+	type group struct{ srcLang, locale string }
+	var groups []group
+	byGroup := map[group][]*domain.Unit{}
+	for _, it := range items {
+		k := group{locale: it.locale}
+		if pc, err := t.res.Resolve(ctx, it.u); err == nil {
+			k.srcLang = pc.SourceLang
+		}
+		if _, ok := byGroup[k]; !ok {
+			groups = append(groups, k)
+		}
+		byGroup[k] = append(byGroup[k], it.u)
+	}
+	var batches []batch
+	for _, k := range groups {
+		for _, units := range translator.SplitBatches(byGroup[k], t.contextTokens) {
+			batches = append(batches, batch{locale: k.locale, units: units})
+		}
+	}
+	return r.runPool(ctx, t.providerID, len(batches), g, func(ctx context.Context, i int) func() {
+		if !t.budget.allow() {
+			return nil
+		}
+		b := batches[i]
+		type result struct {
+			u      *domain.Unit
+			itemID int64
+			out    translateOutcome
+			err    error
+		}
+		var results []result
+		var args []translator.TranslateArgs
+		var pending []int
+		for _, u := range b.units {
+			res := result{u: u, itemID: r.beginJobItem(ctx, jobID, u, b.locale, t.model)}
+			pc, err := t.res.Resolve(ctx, u)
+			switch {
+			case err != nil:
+				res.err = err
+			case t.useTM:
+				if e, err := r.d.Memory.Exact(ctx, pc.SourceLang, b.locale, u.SourceText); err == nil {
+					res.out = translateOutcome{text: e.TargetText, status: "tm"}
+					break
+				}
+				fallthrough
+			default:
+				args = append(args, t.args(u, b.locale, &pc))
+				pending = append(pending, len(results))
+			}
+			results = append(results, res)
+		}
+		if len(args) > 0 {
+			r.log(ctx, jobID, "info", fmt.Sprintf("batch: locale=%s units=%d", b.locale, len(args)))
+			outcomes := r.trans.TranslateBatch(ctx, args)
+			g.record(ctx, batchError(outcomes))
+			for j, o := range outcomes {
+				if o.Err != nil {
+					results[pending[j]].err = o.Err
+				} else {
+					results[pending[j]].out = newOutcome(o.Result)
+				}
+			}
+		}
+		return func() {
+			dctx := context.WithoutCancel(ctx)
+			for _, res := range results {
+				if res.err != nil {
+					r.endJobItemError(dctx, jobID, res.itemID, res.u, b.locale, t.model, res.err)
+				} else {
+					r.spend(dctx, jobID, t, r.endJobItemSuccess(dctx, jobID, res.itemID, res.u, b.locale, t.model, res.out))
+				}
+				prog.step(ctx)
+			}
+		}
+	})
+}
+
+// batchError returns the error of a batch that failed as a whole, or nil when any unit of it
+// was translated.
+func batchError(outcomes []translator.BatchOutcome) error {
+	var err error
+	for _, o := range outcomes {
+		if o.Err == nil {
+			return nil
+		}
+		err = o.Err
+	}
+	return err
+}
+
+func (r *Runner) runTranslateFile(ctx context.Context, jobID, projectID, providerID int64, p TranslateFileParams) {
+	task := r.newTranslateTask(projectID, providerID, p.Model, false, p.UseTM, p.SystemPrompt, p.UserPrompt, p.Fallbacks, p.Options)
+	task.budget = newJobBudget(p.Budget)
 	units, err := r.d.Units.ListByFile(ctx, p.FileID)
 	if err != nil {
 		_ = r.d.Jobs.AddLog(
@@ -337,40 +869,11 @@ func (r *Runner) runTranslateFile(ctx context.Context, jobID, providerID int64,
 			Message: fmt.Sprintf("units=%d, locales=%d", len(units), len(p.TargetLocales)),
 		},
 	)
-	total, done := len(units)*len(p.TargetLocales), 0
-	_ = r.d.Jobs.UpdateProgress(
-		ctx,
-		jobID,
-		done,
-		total,
-		"running",
-	)
-	for _, u := range units {
-		select {
-		case <-ctx.Done():
-			_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "canceled")
-			return
-		default:
-		}
-		for _, locale := range p.TargetLocales {
-			t, _ := r.d.Translations.Get(ctx, u.ID, locale)
-			if t != nil && strings.TrimSpace(t.Text) != "" {
-				continue
-			}
-			itemID := r.beginJobItem(ctx, jobID, u, locale, p.Model)
-			txt, trErr := r.translateWithTimeout(ctx, providerID, u, locale, p.Model, false)
-			if trErr != nil {
-				r.endJobItemError(ctx, jobID, itemID, u, locale, p.Model, trErr)
-			} else {
-				r.endJobItemSuccess(ctx, jobID, itemID, u, locale, p.Model, txt)
-			}
-			done++
-			_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "running")
-			r.emitProgress(jobID, done, total, "running", p.Model)
-		}
-	}
-	_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "done")
-	r.emitProgress(jobID, done, total, "done", p.Model)
+	items := r.pendingItems(ctx, units, p.TargetLocales, false)
+	prog := &jobProgress{r: r, jobID: jobID, total: len(items), model: p.Model}
+	_ = r.d.Jobs.UpdateProgress(ctx, jobID, 0, prog.total, "running")
+	status := r.translateItems(ctx, jobID, &task, items, p.Mode == "batch", prog)
+	prog.finish(ctx, status)
 }
 
 // StartTranslateUnit creates a job to translate a single unit for given locales, skipping those that already have a translation.
@@ -384,6 +887,14 @@ func (r *Runner) StartTranslateUnit(ctx context.Context, projectID, providerID i
 	if norm, err := r.normalizeModel(ctx, providerID, p.Model); err == nil && norm != "" {
 		p.Model = norm
 	}
+	if err := r.checkOptions(ctx, providerID, p.Options); err != nil {
+		return 0, err
+	}
+	fallbacks, err := r.resolveFallbacks(ctx, p.Fallbacks)
+	if err != nil {
+		return 0, err
+	}
+	p.Fallbacks = fallbacks
 	// Compute locales to process: all if Force, else only missing
 	miss := make([]string, 0, len(p.Locales))
 	if p.Force {
@@ -428,57 +939,45 @@ func (r *Runner) StartTranslateUnit(ctx context.Context, projectID, providerID i
 	r.mu.Lock()
 	r.active[id] = cancel
 	r.mu.Unlock()
-	go r.runTranslateUnit(cctx, id, providerID, p, miss)
+	go r.runTranslateUnit(cctx, id, projectID, providerID, p, miss)
 	return id, nil
 }
 
-func (r *Runner) runTranslateUnit(ctx context.Context, jobID, providerID int64, p TranslateUnitParams, locales []string) {
+func (r *Runner) runTranslateUnit(ctx context.Context, jobID, projectID, providerID int64, p TranslateUnitParams, locales []string) {
+	task := r.newTranslateTask(projectID, providerID, p.Model, p.Force, p.UseTM, p.SystemPrompt, p.UserPrompt, p.Fallbacks, p.Options)
 	u, err := r.d.Units.Get(ctx, p.UnitID)
 	if err != nil || u == nil {
 		_ = r.d.Jobs.UpdateProgress(ctx, jobID, 0, 0, "failed")
 		return
 	}
-	total, done := len(locales), 0
+	items := make([]workItem, 0, len(locales))
 	for _, locale := range locales {
-		select {
-		case <-ctx.Done():
-			_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "canceled")
-			return
-		default:
-		}
-		itemID := r.beginJobItem(ctx, jobID, u, locale, p.Model)
-		txt, trErr := r.translateWithTimeout(ctx, providerID, u, locale, p.Model, p.Force)
-		if trErr != nil {
-			r.endJobItemError(ctx, jobID, itemID, u, locale, p.Model, trErr)
-		} else {
-			r.endJobItemSuccess(ctx, jobID, itemID, u, locale, p.Model, txt)
-		}
-		done++
-		_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "running")
-		r.emitProgress(jobID, done, total, "running", p.Model)
+		items = append(items, workItem{u: u, locale: locale})
 	}
-	_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "done")
-	r.emitProgress(jobID, done, total, "done", p.Model)
+	prog := &jobProgress{r: r, jobID: jobID, total: len(items), model: p.Model}
+	status := r.translateItems(ctx, jobID, &task, items, false, prog)
+	prog.finish(ctx, status)
 }
 
 // StartTranslateUnits creates a single job to translate multiple specific units sequentially for given locales.
 func (r *Runner) StartTranslateUnits(ctx context.Context, projectID, providerID int64, p TranslateUnitsParams) (int64, error) {
+	if err := checkMode(p.Mode, p.SystemPrompt, p.UserPrompt); err != nil {
+		return 0, err
+	}
 	// Resolve/normalize model
 	p.Model = r.resolveModel(ctx, providerID, p.Model)
-	// Compute total items: all if Force, else only missing
-	total := 0
-	if p.Force {
-		total = len(p.UnitIDs) * len(p.Locales)
-	} else {
-		for _, uid := range p.UnitIDs {
-			for _, tgt := range p.Locales {
-				t, _ := r.d.Translations.Get(ctx, uid, tgt)
-				if t == nil || strings.TrimSpace(t.Text) == "" {
-					total++
-				}
-			}
-		}
+	if err := r.checkOptions(ctx, providerID, p.Options); err != nil {
+		return 0, err
 	}
+	fallbacks, err := r.resolveFallbacks(ctx, p.Fallbacks)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.checkBudgets(ctx, providerID, p.Model, fallbacks, p.Budget); err != nil {
+		return 0, err
+	}
+	p.Fallbacks = fallbacks
+	total := len(r.pendingItems(ctx, r.units(ctx, p.UnitIDs), p.Locales, p.Force))
 	paramsJSON, _ := json.Marshal(p)
 	job := &domain.Job{
 		Type:       "translate_units",
@@ -501,6 +1000,7 @@ func (r *Runner) StartTranslateUnits(ctx context.Context, projectID, providerID
 			r.runTranslateUnits(
 				runCtx,
 				id,
+				projectID,
 				providerID,
 				p,
 			)
@@ -509,45 +1009,42 @@ func (r *Runner) StartTranslateUnits(ctx context.Context, projectID, providerID
 	return id, nil
 }
 
-func (r *Runner) runTranslateUnits(ctx context.Context, jobID, providerID int64, p TranslateUnitsParams) {
-	done, total := 0, 0
-	for range p.UnitIDs {
-		for range p.Locales {
-			total++
+func (r *Runner) runTranslateUnits(ctx context.Context, jobID, projectID, providerID int64, p TranslateUnitsParams) {
+	task := r.newTranslateTask(projectID, providerID, p.Model, p.Force, p.UseTM, p.SystemPrompt, p.UserPrompt, p.Fallbacks, p.Options)
+	task.budget = newJobBudget(p.Budget)
+	items := r.pendingItems(ctx, r.units(ctx, p.UnitIDs), p.Locales, p.Force)
+	prog := &jobProgress{r: r, jobID: jobID, total: len(items), model: p.Model}
+	_ = r.d.Jobs.UpdateProgress(ctx, jobID, 0, prog.total, "running")
+	status := r.translateItems(ctx, jobID, &task, items, p.Mode == "batch", prog)
+	prog.finish(ctx, status)
+}
+
+// units loads units by ID, skipping missing ones.
+func (r *Runner) units(ctx context.Context, ids []int64) []*domain.Unit {
+	out := make([]*domain.Unit, 0, len(ids))
+	for _, id := range ids {
+		if u, err := r.d.Units.Get(ctx, id); err == nil && u != nil {
+			out = append(out, u)
 		}
 	}
-	for _, uid := range p.UnitIDs {
-		select {
-		case <-ctx.Done():
-			_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "canceled")
-			return
-		default:
-		}
-		u, err := r.d.Units.Get(ctx, uid)
-		if err != nil || u == nil {
-			continue
-		}
-		for _, locale := range p.Locales {
-			if !p.Force {
+	return out
+}
+
+// pendingItems returns the units × locales without a translation, or all of them with force.
+func (r *Runner) pendingItems(ctx context.Context, units []*domain.Unit, locales []string, force bool) []workItem {
+	var items []workItem
+	for _, u := range units {
+		for _, locale := range locales {
+			if !force {
 				t, _ := r.d.Translations.Get(ctx, u.ID, locale)
 				if t != nil && strings.TrimSpace(t.Text) != "" {
 					continue
 				}
 			}
-			itemID := r.beginJobItem(ctx, jobID, u, locale, p.Model)
-			txt, trErr := r.translateWithTimeout(ctx, providerID, u, locale, p.Model, p.Force)
-			if trErr != nil {
-				r.endJobItemError(ctx, jobID, itemID, u, locale, p.Model, trErr)
-			} else {
-				r.endJobItemSuccess(ctx, jobID, itemID, u, locale, p.Model, txt)
-			}
-			done++
-			_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "running")
-			r.emitProgress(jobID, done, total, "running", p.Model)
+			items = append(items, workItem{u: u, locale: locale})
 		}
 	}
-	_ = r.d.Jobs.UpdateProgress(ctx, jobID, done, total, "done")
-	r.emitProgress(jobID, done, total, "done", p.Model)
+	return items
 }
 
 func (r *Runner) log(ctx context.Context, jobID int64, level, message string) {
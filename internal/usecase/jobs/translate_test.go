@@ -0,0 +1,354 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/adapters/prompt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/tm"
+	"locail/internal/usecase/translator"
+)
+
+// fakeProvider translates to "T <text>" after delay, or once release is closed, and records
+// how many requests run at once.
+type fakeProvider struct {
+	delay   time.Duration
+	release chan struct{}
+
+	started  atomic.Int32
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (f *fakeProvider) begin(ctx context.Context) error {
+	f.started.Add(1)
+	n := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+	for {
+		p := f.peak.Load()
+		if n <= p || f.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	wait := f.release
+	if wait == nil {
+		t := time.NewTimer(f.delay)
+		defer t.Stop()
+		wait = make(chan struct{})
+		go func() { <-t.C; close(wait) }()
+	}
+	select {
+	case <-wait:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *fakeProvider) Translate(ctx context.Context, seg ports.Segment, _ ports.TranslateParams) (ports.TranslateResult, error) {
+	if err := f.begin(ctx); err != nil {
+		return ports.TranslateResult{}, err
+	}
+	return ports.TranslateResult{Translation: "T " + seg.Text}, nil
+}
+
+func (f *fakeProvider) TranslateBatch(ctx context.Context, segs []ports.Segment, _ ports.TranslateParams) (ports.BatchResult, error) {
+	if err := f.begin(ctx); err != nil {
+		return ports.BatchResult{}, err
+	}
+	out := map[string]string{}
+	for _, s := range segs {
+		out[s.Key] = "T " + s.Text
+	}
+	return ports.BatchResult{Translations: out}, nil
+}
+
+func (f *fakeProvider) ListModels(context.Context) ([]ports.ModelInfo, error) { return nil, nil }
+func (f *fakeProvider) Test(context.Context) error                            { return nil }
+
+// progressRecorder keeps the job.started and job.progress events of a runner.
+type progressRecorder struct {
+	mu      sync.Mutex
+	started []jobStartedPayload
+	events  []jobProgressPayload
+}
+
+func (e *progressRecorder) Emit(name string, payload any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch p := payload.(type) {
+	case jobStartedPayload:
+		e.started = append(e.started, p)
+	case jobProgressPayload:
+		e.events = append(e.events, p)
+	}
+}
+
+func (e *progressRecorder) list() []jobProgressPayload {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]jobProgressPayload(nil), e.events...)
+}
+
+// startedTotal returns the total a job was announced with.
+func (e *progressRecorder) startedTotal(jobID int64) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, p := range e.started {
+		if p.JobID == jobID {
+			return p.Total
+		}
+	}
+	return -1
+}
+
+// testEnv is a runner over a fresh database with one project, one file and a provider served
+// by fake.
+type testEnv struct {
+	db           *sql.DB
+	r            *Runner
+	fake         *fakeProvider
+	events       *progressRecorder
+	project      *domain.Project
+	file         *domain.File
+	provider     *domain.Provider
+	jobs         *dbsqlite.JobRepo
+	units        *dbsqlite.UnitRepo
+	translations *dbsqlite.TranslationRepo
+	providers    *dbsqlite.ProviderRepo
+	deps         Deps
+	trans        translator.Deps
+}
+
+func newTestEnv(t *testing.T, concurrency int) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	env := &testEnv{
+		db:           db,
+		fake:         &fakeProvider{delay: 5 * time.Millisecond},
+		events:       &progressRecorder{},
+		jobs:         dbsqlite.NewJobRepo(db),
+		units:        dbsqlite.NewUnitRepo(db),
+		translations: dbsqlite.NewTranslationRepo(db),
+		providers:    dbsqlite.NewProviderRepo(db),
+	}
+	projects := dbsqlite.NewProjectRepo(db)
+	files := dbsqlite.NewFileRepo(db)
+	providers := env.providers
+	templates := dbsqlite.NewTemplateRepo(db)
+	if err := prompt.SeedDefaults(ctx, templates); err != nil {
+		t.Fatal(err)
+	}
+	env.project = &domain.Project{Name: "Game", SourceLang: "en"}
+	if err := projects.Create(ctx, env.project); err != nil {
+		t.Fatal(err)
+	}
+	env.file = &domain.File{ProjectID: env.project.ID, Path: "ui.json", Format: "json", Locale: "en"}
+	if err := files.Create(ctx, env.file); err != nil {
+		t.Fatal(err)
+	}
+	env.provider = &domain.Provider{Type: "ollama", Name: "fake", Model: "m", Concurrency: concurrency}
+	if err := providers.Create(ctx, env.provider); err != nil {
+		t.Fatal(err)
+	}
+	cache := dbsqlite.NewCacheRepo(db)
+	pr := prompt.New(templates)
+	env.trans = translator.Deps{
+		Projects:      projects,
+		Files:         files,
+		Units:         env.units,
+		Providers:     providers,
+		Templates:     templates,
+		Cache:         cache,
+		Translations:  env.translations,
+		Prompt:        pr,
+		Settings:      dbsqlite.NewSettingsRepo(db),
+		BuildProvider: func(*domain.Provider) (ports.Provider, error) { return env.fake, nil },
+	}
+	env.deps = Deps{
+		Jobs:         env.jobs,
+		Projects:     projects,
+		Files:        files,
+		Units:        env.units,
+		Providers:    providers,
+		Translations: env.translations,
+		Prompt:       pr,
+		Cache:        cache,
+	}
+	env.build()
+	return env
+}
+
+// build (re)creates the runner from env.deps and env.trans.
+func (env *testEnv) build() {
+	env.r = NewRunner(env.deps, translator.New(env.trans))
+	env.r.SetEmitter(env.events)
+}
+
+// addUnits stores n units "text 0".."text n-1" in the env's file.
+func (env *testEnv) addUnits(t *testing.T, n int) []*domain.Unit {
+	t.Helper()
+	ctx := context.Background()
+	us := make([]*domain.Unit, n)
+	for i := range us {
+		us[i] = &domain.Unit{FileID: env.file.ID, Key: fmt.Sprintf("k%02d", i), SourceText: fmt.Sprintf("text %d", i)}
+	}
+	if err := env.units.UpsertBatch(ctx, us); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := env.units.ListByFile(ctx, env.file.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+// wait polls a job until it reaches a final status.
+func (env *testEnv) wait(t *testing.T, jobID int64) *domain.Job {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		j, err := env.jobs.Get(context.Background(), jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch j.Status {
+		case "done", "failed", "canceled":
+			return j
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not finish", jobID)
+	return nil
+}
+
+func TestStartTranslateFileChecksMode(t *testing.T) {
+	env := newTestEnv(t, 1)
+	tests := []struct {
+		name   string
+		params TranslateFileParams
+		err    string
+	}{
+		{"unknown mode", TranslateFileParams{Mode: "bulk"}, `unknown translate mode "bulk"`},
+		{"batch with a system override", TranslateFileParams{Mode: "batch", SystemPrompt: "Translate."}, "not supported in batch mode"},
+		{"batch with a user override", TranslateFileParams{Mode: "batch", UserPrompt: "{{.Text}}"}, "not supported in batch mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.FileID = env.file.ID
+			tt.params.TargetLocales = []string{"de"}
+			_, err := env.r.StartTranslateFile(context.Background(), env.project.ID, env.provider.ID, tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got %v, want %q", err, tt.err)
+			}
+			if _, err := env.r.EstimateTranslateFile(context.Background(), env.project.ID, env.provider.ID, tt.params); err == nil {
+				t.Fatal("estimate accepted the mode")
+			}
+		})
+	}
+	jobs, _ := env.jobs.List(context.Background(), 10)
+	if len(jobs) != 0 {
+		t.Fatalf("%d jobs created", len(jobs))
+	}
+	for _, mode := range []string{"", "per_key"} {
+		if err := checkMode(mode, "Translate.", "{{.Text}}"); err != nil {
+			t.Fatalf("mode %q with overrides: %v", mode, err)
+		}
+	}
+}
+
+func TestTranslateFileJobUsesTM(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		useTM bool
+		calls int32
+	}{
+		{"per key", "per_key", true, 2},
+		{"batch", "batch", true, 1},
+		{"memory off", "per_key", false, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t, 1)
+			mem := dbsqlite.NewTMRepo(env.db)
+			env.deps.Memory = tm.New(tm.Deps{Memory: mem, Units: env.units, Files: env.deps.Files, Projects: env.deps.Projects})
+			env.build()
+			us := env.addUnits(t, 3)
+			for _, e := range []*domain.TMEntry{
+				{SourceText: "text 0", TargetText: "Text null", SrcLang: "en", TgtLang: "de", Origin: "import", Status: "approved"},
+				// only identical sources are applied
+				{SourceText: "Text 1", TargetText: "Text eins", SrcLang: "en", TgtLang: "de", Origin: "import", Status: "approved"},
+			} {
+				if err := mem.Put(ctx, e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			id, err := env.r.StartTranslateFile(ctx, env.project.ID, env.provider.ID, TranslateFileParams{FileID: env.file.ID, TargetLocales: []string{"de"}, Mode: tt.mode, UseTM: tt.useTM})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if j := env.wait(t, id); j.Status != "done" {
+				t.Fatalf("job %s", j.Status)
+			}
+			if got := env.fake.started.Load(); got != tt.calls {
+				t.Errorf("%d provider requests, want %d", got, tt.calls)
+			}
+			want := map[int64][2]string{us[0].ID: {"Text null", "tm"}, us[1].ID: {"T text 1", "machine"}, us[2].ID: {"T text 2", "machine"}}
+			if !tt.useTM {
+				want[us[0].ID] = [2]string{"T text 0", "machine"}
+			}
+			for unitID, w := range want {
+				tr, err := env.translations.Get(ctx, unitID, "de")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tr.Text != w[0] || tr.Status != w[1] {
+					t.Errorf("unit %d: %q (%s), want %q (%s)", unitID, tr.Text, tr.Status, w[0], w[1])
+				}
+			}
+		})
+	}
+}
+
+func TestStartTranslateFileTotal(t *testing.T) {
+	env := newTestEnv(t, 2)
+	us := env.addUnits(t, 4)
+	ctx := context.Background()
+	for _, tr := range []*domain.Translation{
+		{UnitID: us[0].ID, Locale: "de", Text: "Hallo", Status: "draft"},
+		// blank translations are pending, as the run treats them
+		{UnitID: us[1].ID, Locale: "de", Text: "  ", Status: "draft"},
+	} {
+		if err := env.translations.Upsert(ctx, tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := env.r.StartTranslateFile(ctx, env.project.ID, env.provider.ID, TranslateFileParams{FileID: env.file.ID, TargetLocales: []string{"de", "fr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := env.events.startedTotal(id); got != 7 {
+		t.Fatalf("started with total %d, want 7", got)
+	}
+	j := env.wait(t, id)
+	if j.Status != "done" || j.Progress != 7 || j.Total != 7 {
+		t.Fatalf("job %s %d/%d, want done 7/7", j.Status, j.Progress, j.Total)
+	}
+}
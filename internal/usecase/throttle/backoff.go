@@ -0,0 +1,33 @@
+package throttle
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	backoffBase = time.Second
+	backoffMax  = 30 * time.Second
+)
+
+// Backoff returns the delay before retry attempt n (1-based): exponential from one second,
+// capped at 30 seconds, with the upper half jittered so that parallel workers spread out.
+func Backoff(n int) time.Duration {
+	d := backoffBase << min(max(n-1, 0), 10)
+	d = min(d, backoffMax)
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// Sleep waits for d or until ctx is done.
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker is a circuit breaker: after Threshold consecutive failures it opens for a cooldown
+// that doubles with every trip without a success in between. After MaxTrips such trips it
+// gives up for good. Breaker is safe for concurrent use.
+type Breaker struct {
+	Threshold   int
+	Cooldown    time.Duration
+	MaxCooldown time.Duration
+	MaxTrips    int
+
+	mu        sync.Mutex
+	failures  int
+	trips     int
+	openUntil time.Time
+}
+
+// NewBreaker returns a breaker opening after 5 consecutive failures for 30s, up to 5 minutes,
+// and giving up after 4 trips.
+func NewBreaker() *Breaker {
+	return &Breaker{Threshold: 5, Cooldown: 30 * time.Second, MaxCooldown: 5 * time.Minute, MaxTrips: 4}
+}
+
+// Success closes the breaker and resets its counters.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures, b.trips = 0, 0
+	b.openUntil = time.Time{}
+}
+
+// Failure records a failure and returns the cooldown when it opened the breaker (0 otherwise).
+func (b *Breaker) Failure() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < b.Threshold || time.Now().Before(b.openUntil) {
+		return 0
+	}
+	b.failures = 0
+	b.trips++
+	d := min(b.Cooldown<<min(b.trips-1, 10), b.MaxCooldown)
+	b.openUntil = time.Now().Add(d)
+	return d
+}
+
+// Open returns how long the breaker stays open (0 when closed).
+func (b *Breaker) Open() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return max(time.Until(b.openUntil), 0)
+}
+
+// Exhausted reports whether the breaker tripped MaxTrips times without a success.
+func (b *Breaker) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.MaxTrips > 0 && b.trips >= b.MaxTrips
+}
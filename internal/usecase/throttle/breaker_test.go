@@ -0,0 +1,74 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+)
+
+// expire ends the current cooldown without sleeping.
+func (b *Breaker) expire() {
+	b.mu.Lock()
+	b.openUntil = time.Now().Add(-time.Millisecond)
+	b.mu.Unlock()
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b := &Breaker{Threshold: 3, Cooldown: time.Minute, MaxCooldown: 3 * time.Minute, MaxTrips: 3}
+	// each step: "f" failure, "s" success, "e" cooldown expires
+	tests := []struct {
+		step      string
+		trip      time.Duration // cooldown returned by Failure
+		open      bool
+		exhausted bool
+	}{
+		{"f", 0, false, false},
+		{"f", 0, false, false},
+		{"f", time.Minute, true, false},
+		{"f", 0, true, false}, // failures while open do not trip again
+		{"e", 0, false, false},
+		{"f", 0, false, false},
+		{"f", 2 * time.Minute, true, false}, // doubled, counting the failure seen while open
+		{"e", 0, false, false},
+		{"f", 0, false, false},
+		{"f", 0, false, false},
+		{"f", 3 * time.Minute, true, true}, // capped at MaxCooldown, third trip gives up
+		{"s", 0, false, false},
+		{"f", 0, false, false},
+		{"f", 0, false, false},
+		{"f", time.Minute, true, false}, // success reset the doubling
+	}
+	for i, tt := range tests {
+		var trip time.Duration
+		switch tt.step {
+		case "f":
+			trip = b.Failure()
+		case "s":
+			b.Success()
+		case "e":
+			b.expire()
+		}
+		if trip != tt.trip {
+			t.Fatalf("step %d (%s): trip %v, want %v", i, tt.step, trip, tt.trip)
+		}
+		if open := b.Open() > 0; open != tt.open {
+			t.Fatalf("step %d (%s): open %v, want %v", i, tt.step, open, tt.open)
+		}
+		if ex := b.Exhausted(); ex != tt.exhausted {
+			t.Fatalf("step %d (%s): exhausted %v, want %v", i, tt.step, ex, tt.exhausted)
+		}
+	}
+}
+
+func TestBreakerUnlimitedTrips(t *testing.T) {
+	b := &Breaker{Threshold: 1, Cooldown: time.Second, MaxCooldown: 10 * time.Minute}
+	for i := 0; i < 20; i++ {
+		b.Failure()
+		b.expire()
+	}
+	if b.Exhausted() {
+		t.Fatal("breaker without MaxTrips gave up")
+	}
+	if d := b.Failure(); d != 10*time.Minute {
+		t.Fatalf("cooldown %v, want MaxCooldown", d)
+	}
+}
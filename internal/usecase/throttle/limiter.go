@@ -0,0 +1,92 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket limiting requests and model tokens per minute. A zero limit
+// disables that bucket. Limiter is safe for concurrent use.
+type Limiter struct {
+	mu       sync.Mutex
+	rpm, tpm int
+	requests float64
+	tokens   float64
+	last     time.Time
+	// blocked holds all requests back until then, e.g. after a 429 with Retry-After.
+	blocked time.Time
+}
+
+// NewLimiter returns a limiter with full buckets.
+func NewLimiter(rpm, tpm int) *Limiter {
+	return &Limiter{rpm: rpm, tpm: tpm, requests: float64(rpm), tokens: float64(tpm), last: time.Now()}
+}
+
+// Limits returns the configured requests and tokens per minute.
+func (l *Limiter) Limits() (rpm, tpm int) {
+	return l.rpm, l.tpm
+}
+
+// Wait blocks until one request using the given number of tokens may be sent.
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	for {
+		d := l.reserve(tokens)
+		if d <= 0 {
+			return nil
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Block holds back all requests for d.
+func (l *Limiter) Block(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.blocked) {
+		l.blocked = until
+	}
+}
+
+// reserve takes capacity for a request and returns 0, or returns how long to wait.
+func (l *Limiter) reserve(tokens int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Before(l.blocked) {
+		return l.blocked.Sub(now)
+	}
+	elapsed := now.Sub(l.last).Minutes()
+	l.last = now
+	l.requests = min(float64(l.rpm), l.requests+elapsed*float64(l.rpm))
+	l.tokens = min(float64(l.tpm), l.tokens+elapsed*float64(l.tpm))
+	// a request larger than the whole bucket waits for a full bucket instead of forever
+	need := float64(min(tokens, l.tpm))
+	var wait time.Duration
+	if l.rpm > 0 && l.requests < 1 {
+		wait = max(wait, minutes((1-l.requests)/float64(l.rpm)))
+	}
+	if l.tpm > 0 && l.tokens < need {
+		wait = max(wait, minutes((need-l.tokens)/float64(l.tpm)))
+	}
+	if wait > 0 {
+		return wait
+	}
+	if l.rpm > 0 {
+		l.requests--
+	}
+	if l.tpm > 0 {
+		l.tokens -= need
+	}
+	return 0
+}
+
+func minutes(m float64) time.Duration {
+	return max(time.Duration(m*float64(time.Minute)), time.Millisecond)
+}
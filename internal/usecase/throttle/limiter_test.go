@@ -0,0 +1,52 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name     string
+		rpm, tpm int
+		tokens   []int // successive requests; all but the last must pass
+		wait     time.Duration
+	}{
+		{"unlimited", 0, 0, []int{1000, 1000, 1000}, 0},
+		{"request bucket empty", 2, 0, []int{1, 1, 1}, 30 * time.Second},
+		{"token bucket empty", 0, 600, []int{500, 200}, 10 * time.Second},
+		{"token request larger than bucket", 0, 100, []int{50, 1000}, 30 * time.Second},
+		{"both buckets, tokens dominate", 60, 120, []int{100, 80}, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.rpm, tt.tpm)
+			for i, n := range tt.tokens[:len(tt.tokens)-1] {
+				if d := l.reserve(n); d != 0 {
+					t.Fatalf("request %d waits %v", i, d)
+				}
+			}
+			d := l.reserve(tt.tokens[len(tt.tokens)-1])
+			// the bucket refills a little between calls
+			if d > tt.wait || d < tt.wait-time.Second {
+				t.Fatalf("wait %v, want about %v", d, tt.wait)
+			}
+		})
+	}
+}
+
+func TestLimiterBlock(t *testing.T) {
+	l := NewLimiter(0, 0)
+	l.Block(time.Minute)
+	l.Block(time.Second) // a shorter block does not shorten the first
+	if d := l.reserve(1); d < 59*time.Second {
+		t.Fatalf("blocked wait %v", d)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait: %v", err)
+	}
+}
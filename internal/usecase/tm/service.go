@@ -0,0 +1,236 @@
+package tm
+
+import (
+	"context"
+	"errors"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"math"
+	"sort"
+	"strings"
+)
+
+// EligibleStatuses are translation statuses that feed the memory: human-touched text only.
+var EligibleStatuses = []string{"edited", "reviewed", "approved"}
+
+const (
+	defaultMinScore   = 50
+	defaultMaxResults = 5
+	candidateLimit    = 50
+)
+
+type Deps struct {
+	Memory   ports.TMRepository
+	Units    ports.UnitRepository
+	Files    ports.FileRepository
+	Projects ports.ProjectRepository
+}
+
+type Service struct{ d Deps }
+
+func New(d Deps) *Service { return &Service{d: d} }
+
+// IsEligible reports whether a translation status should be recorded in memory.
+func IsEligible(status string) bool {
+	for _, s := range EligibleStatuses {
+		if strings.EqualFold(status, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rebuild re-imports all eligible translations into the memory and returns the number of entries written.
+func (s *Service) Rebuild(ctx context.Context) (int, error) {
+	entries, err := s.d.Memory.CollectFromTranslations(ctx, EligibleStatuses)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		e.SrcLang = normalizeLang(e.SrcLang)
+		e.TgtLang = normalizeLang(e.TgtLang)
+		if err := s.d.Memory.Put(ctx, e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Record updates the memory after a translation was saved. Eligible translations replace any
+// previous entry of the same unit/locale; others remove it.
+func (s *Service) Record(ctx context.Context, unitID int64, locale, text, status string) error {
+	locale = normalizeLang(locale)
+	if err := s.d.Memory.DeleteByUnit(ctx, unitID, locale); err != nil {
+		return err
+	}
+	if !IsEligible(status) || strings.TrimSpace(text) == "" {
+		return nil
+	}
+	u, err := s.d.Units.Get(ctx, unitID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(u.SourceText) == "" {
+		return nil
+	}
+	srcLang, projectID := s.unitOrigin(ctx, u)
+	return s.d.Memory.Put(ctx, &domain.TMEntry{
+		SourceText: u.SourceText,
+		TargetText: text,
+		SrcLang:    srcLang,
+		TgtLang:    locale,
+		ProjectID:  projectID,
+		UnitID:     &u.ID,
+		Origin:     "translation",
+		Status:     status,
+	})
+}
+
+// unitOrigin returns the unit's source language (file locale, then project language) and project.
+func (s *Service) unitOrigin(ctx context.Context, u *domain.Unit) (string, *int64) {
+	if s.d.Files == nil {
+		return "", nil
+	}
+	f, err := s.d.Files.Get(ctx, u.FileID)
+	if err != nil || f == nil {
+		return "", nil
+	}
+	pid := f.ProjectID
+	lang := f.Locale
+	if strings.TrimSpace(lang) == "" && s.d.Projects != nil {
+		if p, err := s.d.Projects.Get(ctx, f.ProjectID); err == nil && p != nil {
+			lang = p.SourceLang
+		}
+	}
+	return normalizeLang(lang), &pid
+}
+
+type LookupArgs struct {
+	SrcLang    string
+	TgtLang    string
+	Text       string
+	MinScore   int
+	MaxResults int
+	// ExcludeUnitID skips entries recorded from this unit (a unit should not match itself).
+	ExcludeUnitID int64
+	// ProjectID limits matches to one project; 0 searches all projects.
+	ProjectID int64
+}
+
+// Lookup returns memory matches for text ordered by score (0-100).
+func (s *Service) Lookup(ctx context.Context, a LookupArgs) ([]domain.TMMatch, error) {
+	if strings.TrimSpace(a.Text) == "" {
+		return nil, nil
+	}
+	if a.MinScore <= 0 {
+		a.MinScore = defaultMinScore
+	}
+	if a.MaxResults <= 0 {
+		a.MaxResults = defaultMaxResults
+	}
+	cands, err := s.d.Memory.Candidates(ctx, a.ProjectID, normalizeLang(a.SrcLang), normalizeLang(a.TgtLang), a.Text, candidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.TMMatch, 0, len(cands))
+	for _, c := range cands {
+		if a.ExcludeUnitID != 0 && c.UnitID != nil && *c.UnitID == a.ExcludeUnitID {
+			continue
+		}
+		if score := Score(a.Text, c.SourceText); score >= a.MinScore {
+			out = append(out, domain.TMMatch{Entry: c, Score: score})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Entry.UpdatedAt.After(out[j].Entry.UpdatedAt)
+	})
+	if len(out) > a.MaxResults {
+		out = out[:a.MaxResults]
+	}
+	return out, nil
+}
+
+// Suggest returns memory matches for a unit and target locale.
+func (s *Service) Suggest(ctx context.Context, unitID int64, locale string, minScore, maxResults int) ([]domain.TMMatch, error) {
+	u, err := s.d.Units.Get(ctx, unitID)
+	if err != nil {
+		return nil, err
+	}
+	srcLang, _ := s.unitOrigin(ctx, u)
+	return s.Lookup(ctx, LookupArgs{
+		SrcLang:       srcLang,
+		TgtLang:       locale,
+		Text:          u.SourceText,
+		MinScore:      minScore,
+		MaxResults:    maxResults,
+		ExcludeUnitID: u.ID,
+	})
+}
+
+// ErrNoExactMatch is returned by Exact when the memory has no 100% match.
+var ErrNoExactMatch = errors.New("no exact translation memory match")
+
+// Exact returns the most recent 100% match for text, or ErrNoExactMatch.
+func (s *Service) Exact(ctx context.Context, srcLang, tgtLang, text string) (*domain.TMEntry, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrNoExactMatch
+	}
+	entries, err := s.d.Memory.BySource(ctx, normalizeLang(srcLang), normalizeLang(tgtLang), text)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if Score(text, e.SourceText) == 100 {
+			return e, nil
+		}
+	}
+	return nil, ErrNoExactMatch
+}
+
+// Score returns a 0-100 similarity between two source strings. Only identical strings
+// score 100; case/whitespace-only differences score 99.
+func Score(a, b string) int {
+	if a == b {
+		return 100
+	}
+	na, nb := strings.Join(strings.Fields(strings.ToLower(a)), " "), strings.Join(strings.Fields(strings.ToLower(b)), " ")
+	if na == nb {
+		return 99
+	}
+	ra, rb := []rune(na), []rune(nb)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	sim := 1 - float64(levenshtein(ra, rb))/float64(longest)
+	return min(99, int(math.Round(sim*100)))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func normalizeLang(l string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(l)), "_", "-")
+}
@@ -0,0 +1,97 @@
+package tm
+
+import (
+	"context"
+	"errors"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"Save file", "Save file", 100},
+		{"Save file", "save  FILE ", 99},
+		{"Save file", "Save files", 90},
+		{"kitten", "sitting", 57},
+		{"Grüße", "Grüsse", 67}, // compared by rune, not byte
+		{"abc", "xyz", 0},
+		{"", "", 100},
+		{" ", "", 99},
+	}
+	for _, tt := range tests {
+		if got := Score(tt.a, tt.b); got != tt.want {
+			t.Errorf("Score(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Score(tt.b, tt.a); got != tt.want {
+			t.Errorf("Score(%q, %q) = %d, want %d (not symmetric)", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"flaw", "lawn", 2},
+		{"kitten", "sitting", 3},
+		{"日本語", "日本", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// sourceMemory serves BySource from a fixed list; other methods are not used.
+type sourceMemory struct {
+	ports.TMRepository
+	entries []*domain.TMEntry
+	srcLang string
+	tgtLang string
+}
+
+func (m *sourceMemory) BySource(_ context.Context, srcLang, tgtLang, _ string) ([]*domain.TMEntry, error) {
+	m.srcLang, m.tgtLang = srcLang, tgtLang
+	return m.entries, nil
+}
+
+func TestExact(t *testing.T) {
+	mem := &sourceMemory{entries: []*domain.TMEntry{
+		{ID: 1, SourceText: "save the FILE", TargetText: "case"},
+		{ID: 2, SourceText: "Save the file", TargetText: "Datei speichern"},
+		{ID: 3, SourceText: "Save the file", TargetText: "older"},
+	}}
+	s := New(Deps{Memory: mem})
+	tests := []struct {
+		text   string
+		wantID int64
+	}{
+		{"Save the file", 2},
+		{"save the FILE", 1},
+		{"Save  the file", 0},
+		{"  ", 0},
+	}
+	for _, tt := range tests {
+		e, err := s.Exact(context.Background(), "EN_us", "de", tt.text)
+		if tt.wantID == 0 {
+			if !errors.Is(err, ErrNoExactMatch) {
+				t.Errorf("Exact(%q): got %v, want ErrNoExactMatch", tt.text, err)
+			}
+			continue
+		}
+		if err != nil || e.ID != tt.wantID {
+			t.Errorf("Exact(%q) = %+v, %v, want entry %d", tt.text, e, err, tt.wantID)
+		}
+	}
+	if mem.srcLang != "en-us" || mem.tgtLang != "de" {
+		t.Errorf("languages not normalized: %q, %q", mem.srcLang, mem.tgtLang)
+	}
+}
@@ -0,0 +1,157 @@
+package tm
+
+import (
+	"context"
+	"locail/internal/adapters/tmx"
+	"locail/internal/domain"
+	"strings"
+)
+
+// TMX property types written on export and read back on import.
+const (
+	propProject = "x-project"
+	propOrigin  = "x-origin"
+	propStatus  = "x-status"
+)
+
+// ImportTMX loads source/target pairs from a TMX document into the memory and returns
+// the number of entries written.
+func (s *Service) ImportTMX(ctx context.Context, data []byte) (int, error) {
+	units, err := tmx.Decode(data)
+	if err != nil {
+		return 0, err
+	}
+	projectIDs := map[string]*int64{}
+	n := 0
+	for _, u := range units {
+		status := u.Props[propStatus]
+		if status == "" {
+			status = "approved"
+		}
+		e := &domain.TMEntry{
+			SourceText: u.Source,
+			TargetText: u.Target,
+			SrcLang:    normalizeLang(u.SrcLang),
+			TgtLang:    normalizeLang(u.TgtLang),
+			Origin:     "import",
+			Status:     status,
+			CreatedAt:  u.CreatedAt,
+		}
+		if name := u.Props[propProject]; name != "" {
+			e.ProjectID = s.projectID(ctx, name, projectIDs)
+		}
+		if e.TgtLang == "" {
+			continue
+		}
+		if err := s.d.Memory.Put(ctx, e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// ExportTMX writes memory entries matching f as a TMX 1.4b document and returns it with
+// the number of exported units. Project and provenance are recorded as <prop> elements.
+// Without a status filter only approved entries are exported.
+func (s *Service) ExportTMX(ctx context.Context, f domain.TMFilter) ([]byte, int, error) {
+	if len(f.Statuses) == 0 {
+		f.Statuses = []string{"approved"}
+	}
+	f.SrcLang = normalizeLang(f.SrcLang)
+	f.TgtLang = normalizeLang(f.TgtLang)
+	f.Limit, f.Offset = 1000, 0
+	var entries []*domain.TMEntry
+	for {
+		page, err := s.d.Memory.List(ctx, f)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, page...)
+		if len(page) < f.Limit {
+			break
+		}
+		f.Offset += len(page)
+	}
+	srcLang := ""
+	for _, e := range entries {
+		switch {
+		case e.SrcLang == "":
+		case srcLang == "":
+			srcLang = e.SrcLang
+		case !strings.EqualFold(srcLang, e.SrcLang):
+			srcLang = "*all*"
+		}
+	}
+	projectNames := map[int64]string{}
+	units := make([]tmx.Unit, 0, len(entries))
+	for _, e := range entries {
+		// entries without a source language take the document's one; with mixed or no
+		// languages they cannot be written and are left out of the count
+		entrySrc := e.SrcLang
+		if entrySrc == "" {
+			if srcLang == "" || srcLang == "*all*" {
+				continue
+			}
+			entrySrc = srcLang
+		}
+		props := map[string]string{propOrigin: e.Origin, propStatus: e.Status}
+		if e.ProjectID != nil {
+			props[propProject] = s.projectName(ctx, *e.ProjectID, projectNames)
+		}
+		units = append(units, tmx.Unit{
+			SrcLang:   entrySrc,
+			TgtLang:   e.TgtLang,
+			Source:    e.SourceText,
+			Target:    e.TargetText,
+			CreatedAt: e.CreatedAt,
+			ChangedAt: e.UpdatedAt,
+			Props:     props,
+		})
+	}
+	out, err := tmx.Encode(units, srcLang)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(units), nil
+}
+
+func (s *Service) projectName(ctx context.Context, id int64, cache map[int64]string) string {
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	name := ""
+	if s.d.Projects != nil {
+		if p, err := s.d.Projects.Get(ctx, id); err == nil && p != nil {
+			name = p.Name
+		}
+	}
+	cache[id] = name
+	return name
+}
+
+// projectID maps an exported project name back to a local project; names that match no
+// project (or more than one) import as global entries.
+func (s *Service) projectID(ctx context.Context, name string, cache map[string]*int64) *int64 {
+	if id, ok := cache[name]; ok {
+		return id
+	}
+	var id *int64
+	if s.d.Projects != nil {
+		if ps, err := s.d.Projects.List(ctx); err == nil {
+			for _, p := range ps {
+				if p.Name != name {
+					continue
+				}
+				if id != nil {
+					id = nil
+					break
+				}
+				pid := p.ID
+				id = &pid
+			}
+		}
+	}
+	cache[name] = id
+	return id
+}
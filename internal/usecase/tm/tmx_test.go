@@ -0,0 +1,67 @@
+package tm
+
+import (
+	"bytes"
+	"context"
+	"locail/internal/adapters/tmx"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"testing"
+)
+
+// listMemory serves List from a fixed list; other methods are not used.
+type listMemory struct {
+	ports.TMRepository
+	entries []*domain.TMEntry
+}
+
+func (m *listMemory) List(_ context.Context, f domain.TMFilter) ([]*domain.TMEntry, error) {
+	if f.Offset >= len(m.entries) {
+		return nil, nil
+	}
+	return m.entries[f.Offset:min(len(m.entries), f.Offset+f.Limit)], nil
+}
+
+func TestExportTMXSourceLanguages(t *testing.T) {
+	tests := []struct {
+		name    string
+		langs   []string
+		want    []string
+		wantHdr string
+	}{
+		{"single", []string{"en", "en"}, []string{"en", "en"}, "en"},
+		{"missing takes the document language", []string{"", "en", ""}, []string{"en", "en", "en"}, "en"},
+		{"missing with mixed languages is skipped", []string{"en", "", "fr"}, []string{"en", "fr"}, "*all*"},
+		{"all missing", []string{"", ""}, nil, "*all*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &listMemory{}
+			for i, l := range tt.langs {
+				mem.entries = append(mem.entries, &domain.TMEntry{ID: int64(i + 1), SrcLang: l, TgtLang: "de", SourceText: "Save", TargetText: "Speichern", Status: "approved"})
+			}
+			out, n, err := New(Deps{Memory: mem}).ExportTMX(context.Background(), domain.TMFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(tt.want) {
+				t.Fatalf("exported %d, want %d", n, len(tt.want))
+			}
+			units, err := tmx.Decode(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(units) != len(tt.want) {
+				t.Fatalf("decoded %d units, want %d", len(units), len(tt.want))
+			}
+			for i, u := range units {
+				if u.SrcLang != tt.want[i] || u.TgtLang != "de" {
+					t.Errorf("unit %d: %s -> %s, want %s -> de", i, u.SrcLang, u.TgtLang, tt.want[i])
+				}
+			}
+			if hdr := `srclang="` + tt.wantHdr + `"`; !bytes.Contains(out, []byte(hdr)) {
+				t.Errorf("header without %s:\n%s", hdr, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/glossary"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultContextTokens is assumed when a provider does not report the model's context window.
+	defaultContextTokens = 8192
+	// maxBatchItems caps a batch regardless of the context window, since long JSON answers are
+	// more likely to be cut off or malformed.
+	maxBatchItems = 100
+	// maxBatchExamples caps the few-shot examples merged from all units of a batch.
+	maxBatchExamples = 10
+	// batchPromptTokens reserves room for the system prompt, glossary and examples.
+	batchPromptTokens = 1500
+)
+
+// BatchOutcome is the result of one item of a batch.
+type BatchOutcome struct {
+	Result Result
+	Err    error
+}
+
+// ContextTokens returns the context window of the model with the provider's options and
+// overrides, or a conservative default when the provider does not report it.
+func (s *Service) ContextTokens(ctx context.Context, providerID int64, model string, overrides json.RawMessage) int {
+	prov, err := s.provider(ctx, providerID, overrides)
+	if err != nil || s.d.BuildProvider == nil {
+		return defaultContextTokens
+	}
+	if model == "" {
+		model = prov.Model
+	}
+	adapter, err := s.d.BuildProvider(prov)
+	if err != nil {
+		return defaultContextTokens
+	}
+	models, err := adapter.ListModels(ctx)
+	if err != nil {
+		return defaultContextTokens
+	}
+	for _, m := range models {
+		if m.Name == model && m.ContextTokens > 0 {
+			return m.ContextTokens
+		}
+	}
+	return defaultContextTokens
+}
+
+// SplitBatches groups units so that the sources and the expected translations of each batch
+// fit into the model's context window.
+func SplitBatches(units []*domain.Unit, contextTokens int) [][]*domain.Unit {
+	budget := contextTokens - batchPromptTokens
+	if budget < 512 {
+		budget = 512
+	}
+	var out [][]*domain.Unit
+	var cur []*domain.Unit
+	used := 0
+	for _, u := range units {
+		// source and translation, plus key and JSON syntax
+		cost := 2*estimateTokens(u.SourceText) + estimateTokens(u.Key+u.Context) + 8
+		if len(cur) > 0 && (used+cost > budget || len(cur) >= maxBatchItems) {
+			out = append(out, cur)
+			cur, used = nil, 0
+		}
+		cur = append(cur, u)
+		used += cost
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// estimateTokens approximates the token count of s; about three bytes per token keeps
+// estimates on the safe side for non-Latin scripts.
+func estimateTokens(s string) int {
+	return len(s)/3 + 1
+}
+
+type batchItem struct {
+	idx int
+	a   TranslateArgs
+	pu  *preparedUnit
+	key domain.CacheKey
+	id  string
+}
+
+// TranslateBatch translates several units in one provider request using the translate_file
+// templates. Items must share provider, options, source and target language and model. Cached
+// units are served from the cache; units missing from or invalid in the batch answer, or all
+// of them when the request fails, are translated one by one. The overrides of the items apply
+// only to those single-unit fallbacks. When the provider itself fails, units go straight to
+// their fallback providers. The batch request is bounded by BatchTimeout; each single-unit
+// translation gets its own chain timeout from the item's Timeout, within ctx.
+func (s *Service) TranslateBatch(ctx context.Context, items []TranslateArgs) []BatchOutcome {
+	out := make([]BatchOutcome, len(items))
+	if len(items) == 0 {
+		return out
+	}
+	prov, err := s.provider(ctx, items[0].ProviderID, items[0].Options)
+	if err != nil {
+		for i := range out {
+			out[i].Err = err
+		}
+		return out
+	}
+	adapter, promptless, err := s.adapter(prov)
+	if err != nil {
+		for i := range out {
+			out[i].Err = err
+		}
+		return out
+	}
+	var todo []batchItem
+	ids := map[string]bool{}
+	for i := range items {
+		a := items[i]
+		if a.Unit == nil {
+			out[i].Err = errors.New("unit is required")
+			continue
+		}
+		pu, err := s.prepare(ctx, &a)
+		if err != nil {
+			out[i].Err = err
+			continue
+		}
+		key := s.cacheKey(ctx, prov, pu.masked, a, pu.data, promptless)
+		if !a.BypassCache {
+			if text, ok := s.cached(ctx, key, pu); ok {
+				out[i].Result = Result{Text: text, TermIssues: glossary.Check(pu.terms, text, a.TargetLang), Examples: pu.examples, ProviderID: prov.ID, Model: key.Model}
+				continue
+			}
+		}
+		todo = append(todo, batchItem{idx: i, a: a, pu: pu, key: key, id: batchID(a.Unit.Key, ids)})
+	}
+	if len(todo) == 0 {
+		return out
+	}
+
+	fallback := func(items []batchItem) {
+		for _, it := range items {
+			uctx, cancel := s.unitContext(ctx, it.a)
+			res, err := s.Translate(uctx, it.a)
+			cancel()
+			out[it.idx] = BatchOutcome{Result: res, Err: err}
+		}
+	}
+	first := todo[0].a
+	system, user, err := s.batchPrompts(ctx, prov, todo, promptless)
+	if err != nil {
+		fallback(todo)
+		return out
+	}
+	segs := make([]ports.Segment, 0, len(todo))
+	for _, it := range todo {
+		segs = append(segs, ports.Segment{
+			Key:          it.id,
+			Text:         it.pu.masked,
+			Context:      it.a.Unit.Context,
+			Placeholders: it.pu.placeholders,
+			Tags:         it.pu.tags,
+		})
+	}
+	var res ports.BatchResult
+	tokens := estimateRequestTokens(system.Text, user.Text)
+	if promptless {
+		tokens = 0
+		for _, seg := range segs {
+			tokens += estimateRequestTokens("", seg.Text)
+		}
+	}
+	bctx, cancel := context.WithTimeout(ctx, attemptTimeout(BatchTimeout(len(todo)), prov))
+	defer cancel()
+	err = s.call(bctx, prov, tokens, func() error {
+		var err error
+		res, err = adapter.TranslateBatch(bctx, segs, ports.TranslateParams{
+			SourceLang:   first.SourceLang,
+			TargetLang:   first.TargetLang,
+			Model:        first.Model,
+			Temperature:  0.0,
+			SystemPrompt: system.Text,
+			UserPrompt:   user.Text,
+		})
+		return err
+	})
+	if err != nil {
+		if bctx.Err() != nil || IsProviderFailure(err) {
+			// the provider is down: skip straight to the fallbacks instead of retrying each unit
+			for _, it := range todo {
+				if len(it.a.Fallbacks) == 0 || ctx.Err() != nil {
+					out[it.idx].Err = err
+					continue
+				}
+				uctx, cancel := s.unitContext(ctx, it.a)
+				res, err := s.fallback(uctx, it.a, Result{}, err)
+				cancel()
+				out[it.idx] = BatchOutcome{Result: res, Err: err}
+			}
+			return out
+		}
+		fallback(todo)
+		return out
+	}
+	var retry []batchItem
+	usage := splitUsage(res.Usage, len(todo))
+	for i, it := range todo {
+		maskedOut := strings.TrimSpace(res.Translations[it.id])
+		if maskedOut == "" {
+			retry = append(retry, it)
+			continue
+		}
+		translated := it.pu.unmask(maskedOut)
+		if err := validateTokens(translated, it.pu.placeholders, it.pu.tags, it.pu.protected); err != nil {
+			retry = append(retry, it)
+			continue
+		}
+		s.storeCache(ctx, it.key, maskedOut, it.a.ProjectID)
+		r := Result{
+			Text:       translated,
+			TermIssues: glossary.Check(it.pu.terms, translated, it.a.TargetLang),
+			Examples:   it.pu.examples,
+			System:     system,
+			User:       user,
+			ProviderID: prov.ID,
+			Model:      it.key.Model,
+			Usage:      usage[i],
+		}
+		if len(r.TermIssues) > 0 && len(it.a.Fallbacks) > 0 {
+			uctx, cancel := s.unitContext(ctx, it.a)
+			r, _ = s.fallback(uctx, it.a, r, nil)
+			cancel()
+		}
+		out[it.idx].Result = r
+	}
+	fallback(retry)
+	return out
+}
+
+// unitContext bounds the single-unit translation of a batch item by its own chain timeout, so
+// the retries after a batch do not share what is left of the batch request's time.
+func (s *Service) unitContext(ctx context.Context, a TranslateArgs) (context.Context, context.CancelFunc) {
+	if a.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.ChainTimeout(ctx, a, a.Timeout))
+}
+
+// batchPrompts renders the translate_file prompts of a batch; promptless providers get none.
+func (s *Service) batchPrompts(ctx context.Context, prov *domain.Provider, items []batchItem, promptless bool) (system, user ports.RenderedPrompt, err error) {
+	if promptless {
+		return system, user, nil
+	}
+	data := batchPromptData(items)
+	scope := templateScope(prov.ID, items[0].a.ProjectID)
+	system, err = s.d.Prompt.Render(ctx, scope, "translate_file", "system", "", data)
+	if err != nil {
+		return system, user, err
+	}
+	user, err = s.d.Prompt.Render(ctx, scope, "translate_file", "user", "", data)
+	return system, user, err
+}
+
+// batchID returns the id of a unit in a batch request: its key, or "key#n" when the key is
+// empty or already taken by another unit of the batch.
+func batchID(key string, taken map[string]bool) string {
+	id := key
+	for n := 2; taken[id] || id == ""; n++ {
+		id = fmt.Sprintf("%s#%d", key, n)
+	}
+	taken[id] = true
+	return id
+}
+
+// splitUsage spreads the usage of a batch request evenly over its n items.
+func splitUsage(u ports.Usage, n int) []ports.Usage {
+	out := make([]ports.Usage, n)
+	for i := range out {
+		out[i] = ports.Usage{InputTokens: u.InputTokens / n, OutputTokens: u.OutputTokens / n}
+		if i < u.InputTokens%n {
+			out[i].InputTokens++
+		}
+		if i < u.OutputTokens%n {
+			out[i].OutputTokens++
+		}
+	}
+	return out
+}
+
+// batchPromptData merges the prompt data of the items: shared fields come from the first
+// item, glossary entries and examples are deduplicated across items.
+func batchPromptData(items []batchItem) ports.PromptData {
+	data := items[0].pu.data
+	data.Key, data.Text, data.Context = "", "", ""
+	data.Neighbors = nil
+	data.Placeholders, data.Tags = nil, nil
+	data.Glossary, data.Examples = nil, nil
+	seenTerm := map[string]bool{}
+	seenExample := map[string]bool{}
+	seenToken := map[string]bool{}
+	for _, it := range items {
+		d := it.pu.data
+		data.Units = append(data.Units, ports.PromptUnit{Key: it.id, Text: d.Text, Context: d.Context})
+		for _, g := range d.Glossary {
+			if !seenTerm[g.Term] {
+				seenTerm[g.Term] = true
+				data.Glossary = append(data.Glossary, g)
+			}
+		}
+		for _, e := range d.Examples {
+			if !seenExample[e.Source] && len(data.Examples) < maxBatchExamples {
+				seenExample[e.Source] = true
+				data.Examples = append(data.Examples, e)
+			}
+		}
+		for _, p := range d.Placeholders {
+			if !seenToken[p] {
+				seenToken[p] = true
+				data.Placeholders = append(data.Placeholders, p)
+			}
+		}
+		for _, t := range d.Tags {
+			if !seenToken[t] {
+				seenToken[t] = true
+				data.Tags = append(data.Tags, t)
+			}
+		}
+	}
+	return data
+}
+
+// BatchTimeout scales the per-request timeout with the batch size.
+func BatchTimeout(n int) time.Duration {
+	return 60*time.Second + time.Duration(n)*3*time.Second
+}
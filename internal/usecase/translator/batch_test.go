@@ -0,0 +1,80 @@
+package translator
+
+import (
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchID(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{"distinct keys", []string{"a", "b"}, []string{"a", "b"}},
+		{"duplicate keys", []string{"a", "a", "a"}, []string{"a", "a#2", "a#3"}},
+		{"empty keys", []string{"", ""}, []string{"#2", "#3"}},
+		{"suffix already used", []string{"a#2", "a", "a"}, []string{"a#2", "a", "a#3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taken := map[string]bool{}
+			got := make([]string, len(tt.keys))
+			for i, k := range tt.keys {
+				got[i] = batchID(k, taken)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	unit := func(n int) *domain.Unit { return &domain.Unit{Key: "k", SourceText: strings.Repeat("x", n)} }
+	many := make([]*domain.Unit, maxBatchItems+1)
+	for i := range many {
+		many[i] = unit(3)
+	}
+	tests := []struct {
+		name    string
+		units   []*domain.Unit
+		context int
+		sizes   []int
+	}{
+		{"empty", nil, 8000, nil},
+		{"all fit", []*domain.Unit{unit(10), unit(10)}, 8000, []int{2}},
+		{"item limit", many, 1 << 20, []int{maxBatchItems, 1}},
+		{"token budget", []*domain.Unit{unit(900), unit(900), unit(900)}, batchPromptTokens + 1300, []int{2, 1}},
+		{"oversized unit gets its own batch", []*domain.Unit{unit(10), unit(10000), unit(10)}, 0, []int{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sizes []int
+			for _, b := range SplitBatches(tt.units, tt.context) {
+				sizes = append(sizes, len(b))
+			}
+			if !reflect.DeepEqual(sizes, tt.sizes) {
+				t.Fatalf("batch sizes %v, want %v", sizes, tt.sizes)
+			}
+		})
+	}
+}
+
+func TestSplitUsage(t *testing.T) {
+	got := splitUsage(ports.Usage{InputTokens: 10, OutputTokens: 5}, 3)
+	want := []ports.Usage{{InputTokens: 4, OutputTokens: 2}, {InputTokens: 3, OutputTokens: 2}, {InputTokens: 3, OutputTokens: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitUsage = %+v, want %+v", got, want)
+	}
+}
+
+func TestBatchTimeout(t *testing.T) {
+	if got := BatchTimeout(10); got != 90*time.Second {
+		t.Fatalf("BatchTimeout(10) = %v", got)
+	}
+}
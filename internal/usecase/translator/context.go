@@ -0,0 +1,176 @@
+package translator
+
+import (
+	"context"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// neighborCount is the number of units on each side of a unit passed to the prompt.
+const neighborCount = 2
+
+// detectSampleSize limits how many units are sent to the language detector.
+const detectSampleSize = 50
+
+// sharedContextTTL is how long the service's own resolver keeps a file's context. Jobs use
+// their own resolver for their whole run.
+const sharedContextTTL = 10 * time.Second
+
+// PromptContext holds everything about a unit's surroundings that goes into its prompt.
+type PromptContext struct {
+	SourceLang string
+	ProjectID  int64
+	Project    string
+	FilePath   string
+	Neighbors  []ports.NeighborUnit
+	// Glossary holds all terms of the unit's project; matching happens per unit.
+	Glossary []*domain.GlossaryTerm
+	// Protected matches the project's do-not-translate terms.
+	Protected []*regexp.Regexp
+	FewShot   FewShotConfig
+	// StyleGuides are all guides of the project; see ResolveStyle.
+	StyleGuides []*domain.StyleGuide
+}
+
+type fileContext struct {
+	projectID  int64
+	project    string
+	path       string
+	sourceLang string
+	units      []*domain.Unit
+	index      map[int64]int
+	glossary   []*domain.GlossaryTerm
+	protected  []*regexp.Regexp
+	fewShot    FewShotConfig
+	styles     []*domain.StyleGuide
+	loadedAt   time.Time
+}
+
+// ContextResolver derives prompt context for units. It memoizes per-file lookups and is
+// meant to live for the duration of a single job.
+type ContextResolver struct {
+	d Deps
+	// ttl bounds how long a file's context is reused; 0 keeps it for the resolver's life.
+	ttl   time.Duration
+	mu    sync.Mutex
+	files map[int64]*fileContext
+}
+
+// NewContextResolver returns a resolver bound to the service's repositories.
+func (s *Service) NewContextResolver() *ContextResolver {
+	return &ContextResolver{d: s.d, files: map[int64]*fileContext{}}
+}
+
+// Resolve returns the prompt context for a unit. Source language comes from the file's
+// locale, then the project's source language, then language detection over the file.
+func (r *ContextResolver) Resolve(ctx context.Context, u *domain.Unit) (PromptContext, error) {
+	fc, err := r.file(ctx, u.FileID)
+	if err != nil {
+		return PromptContext{}, err
+	}
+	pc := PromptContext{
+		SourceLang:  fc.sourceLang,
+		ProjectID:   fc.projectID,
+		Project:     fc.project,
+		FilePath:    fc.path,
+		Glossary:    fc.glossary,
+		Protected:   fc.protected,
+		FewShot:     fc.fewShot,
+		StyleGuides: fc.styles,
+	}
+	if i, ok := fc.index[u.ID]; ok {
+		lo, hi := max(0, i-neighborCount), min(len(fc.units), i+neighborCount+1)
+		for j := lo; j < hi; j++ {
+			if j == i || strings.TrimSpace(fc.units[j].SourceText) == "" {
+				continue
+			}
+			pc.Neighbors = append(pc.Neighbors, ports.NeighborUnit{Key: fc.units[j].Key, Text: fc.units[j].SourceText})
+		}
+	}
+	return pc, nil
+}
+
+func (r *ContextResolver) file(ctx context.Context, fileID int64) (*fileContext, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ttl > 0 {
+		for id, fc := range r.files {
+			if time.Since(fc.loadedAt) >= r.ttl {
+				delete(r.files, id)
+			}
+		}
+	}
+	if fc, ok := r.files[fileID]; ok {
+		return fc, nil
+	}
+	fc := &fileContext{index: map[int64]int{}, loadedAt: time.Now()}
+	if r.d.Files != nil {
+		f, err := r.d.Files.Get(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		fc.path = f.Path
+		fc.projectID = f.ProjectID
+		fc.fewShot = LoadFewShotConfig(ctx, r.d.Settings, f.ProjectID)
+		fc.sourceLang = strings.TrimSpace(f.Locale)
+		if r.d.Projects != nil {
+			if p, err := r.d.Projects.Get(ctx, f.ProjectID); err == nil && p != nil {
+				fc.project = p.Name
+				if fc.sourceLang == "" {
+					fc.sourceLang = strings.TrimSpace(p.SourceLang)
+				}
+			}
+		}
+		if r.d.Glossary != nil {
+			terms, err := r.d.Glossary.ListByProject(ctx, f.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			fc.glossary = terms
+		}
+		if r.d.Protected != nil {
+			terms, err := r.d.Protected.ListByProject(ctx, f.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			fc.protected = CompileProtected(terms)
+		}
+		if r.d.Styles != nil {
+			guides, err := r.d.Styles.ListByProject(ctx, f.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			fc.styles = guides
+		}
+	}
+	if r.d.Units != nil {
+		units, err := r.d.Units.ListByFile(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		fc.units = units
+		for i, u := range units {
+			fc.index[u.ID] = i
+		}
+	}
+	if fc.sourceLang == "" && r.d.Detector != nil && len(fc.units) > 0 {
+		samples := make([]string, 0, detectSampleSize)
+		for _, u := range fc.units {
+			if len(samples) == detectSampleSize {
+				break
+			}
+			if strings.TrimSpace(u.SourceText) != "" {
+				samples = append(samples, u.SourceText)
+			}
+		}
+		if det, err := r.d.Detector.Detect(ctx, samples); err == nil {
+			fc.sourceLang = det.Lang
+		}
+	}
+	r.files[fileID] = fc
+	return fc, nil
+}
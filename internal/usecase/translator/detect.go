@@ -0,0 +1,69 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"locail/internal/ports"
+	"regexp"
+	"strings"
+)
+
+var langCodeRE = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+type DetectArgs struct {
+	ProviderID int64
+	// ProjectID selects project templates; 0 uses provider and global ones only.
+	ProjectID int64
+	Model     string
+	Text      string
+}
+
+// DetectLanguage asks the provider for the language of a single text using the
+// detect_language templates and returns a normalized language code.
+func (s *Service) DetectLanguage(ctx context.Context, a DetectArgs) (string, error) {
+	prov, err := s.d.Providers.Get(ctx, a.ProviderID)
+	if err != nil {
+		return "", err
+	}
+	data := ports.PromptData{Text: a.Text}
+	scope := templateScope(prov.ID, a.ProjectID)
+	system, err := s.d.Prompt.Render(ctx, scope, "detect_language", "system", "", data)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.d.Prompt.Render(ctx, scope, "detect_language", "user", "", data)
+	if err != nil {
+		return "", err
+	}
+	adapter, promptless, err := s.adapter(prov)
+	if err != nil {
+		return "", err
+	}
+	if promptless {
+		return "", fmt.Errorf("provider %s cannot detect languages", prov.Type)
+	}
+	model := a.Model
+	if model == "" {
+		model = prov.Model
+	}
+	var res ports.TranslateResult
+	err = s.call(ctx, prov, estimateRequestTokens(system.Text, user.Text), func() error {
+		var err error
+		res, err = adapter.Translate(ctx, ports.Segment{Key: "detect", Text: a.Text}, ports.TranslateParams{
+			Model:        model,
+			Temperature:  0.0,
+			SystemPrompt: system.Text,
+			UserPrompt:   user.Text,
+			ResultKey:    "language",
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	code := normalizeLang(strings.Trim(res.Translation, "\"'` ."))
+	if !langCodeRE.MatchString(code) {
+		return "", fmt.Errorf("provider returned invalid language code: %q", res.Translation)
+	}
+	return code, nil
+}
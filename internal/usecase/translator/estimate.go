@@ -0,0 +1,154 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"locail/internal/domain"
+)
+
+// estimateSample bounds the requests whose prompts are rendered for an estimate.
+const estimateSample = 50
+
+// TokenEstimate is the expected token usage of translating a set of units.
+type TokenEstimate struct {
+	Requests     int
+	InputTokens  int
+	OutputTokens int
+	// Sampled is the number of requests whose prompts were rendered; the others are
+	// extrapolated from them.
+	Sampled int
+}
+
+// EstimateTokens estimates the tokens of translating items one per request, or in batches,
+// without calling the provider. The prompts of up to estimateSample requests are rendered and
+// their overhead is applied to the rest. Cache hits are not predicted, so the estimate is an
+// upper bound. Items must share provider, options and model.
+func (s *Service) EstimateTokens(ctx context.Context, items []TranslateArgs, batch bool) (TokenEstimate, error) {
+	var est TokenEstimate
+	if len(items) == 0 {
+		return est, nil
+	}
+	prov, err := s.provider(ctx, items[0].ProviderID, items[0].Options)
+	if err != nil {
+		return est, err
+	}
+	_, promptless, err := s.adapter(prov)
+	if err != nil {
+		return est, err
+	}
+	for _, a := range items {
+		if a.Unit == nil {
+			return est, errors.New("unit is required")
+		}
+		// the answer repeats the text in a small JSON object
+		est.OutputTokens += estimateTokens(a.Unit.SourceText) + 8
+	}
+	if batch {
+		return s.estimateBatches(ctx, prov, items, promptless, est)
+	}
+	est.Requests = len(items)
+	for _, a := range items {
+		est.InputTokens += estimateTokens(a.Unit.SourceText)
+	}
+	if promptless {
+		return est, nil
+	}
+	overhead, n := 0, 0
+	for _, i := range sampleIndexes(len(items), estimateSample) {
+		a := items[i]
+		pu, err := s.prepare(ctx, &a)
+		if err != nil {
+			continue
+		}
+		system, user, err := s.singlePrompts(ctx, prov, a, pu.data, false)
+		if err != nil {
+			return est, err
+		}
+		overhead += estimateTokens(system.Text) + estimateTokens(user.Text) - estimateTokens(a.Unit.SourceText)
+		n++
+	}
+	est.Sampled = n
+	if n > 0 {
+		est.InputTokens += overhead / n * len(items)
+	}
+	return est, nil
+}
+
+// estimateBatches adds the requests and input tokens of translating items in batches to est.
+func (s *Service) estimateBatches(ctx context.Context, prov *domain.Provider, items []TranslateArgs, promptless bool, est TokenEstimate) (TokenEstimate, error) {
+	contextTokens := s.ContextTokens(ctx, prov.ID, items[0].Model, items[0].Options)
+	// jobs batch per source and target language
+	type group struct{ src, tgt string }
+	var groups []group
+	byGroup := map[group][]TranslateArgs{}
+	for _, a := range items {
+		k := group{a.SourceLang, a.TargetLang}
+		if _, ok := byGroup[k]; !ok {
+			groups = append(groups, k)
+		}
+		byGroup[k] = append(byGroup[k], a)
+	}
+	// batches keeps the items of each batch; SplitBatches preserves order
+	var batches [][]TranslateArgs
+	for _, k := range groups {
+		args := byGroup[k]
+		units := make([]*domain.Unit, len(args))
+		for i, a := range args {
+			units[i] = a.Unit
+		}
+		off := 0
+		for _, b := range SplitBatches(units, contextTokens) {
+			batches = append(batches, args[off:off+len(b)])
+			off += len(b)
+		}
+	}
+	est.Requests = len(batches)
+	itemTokens := func(a TranslateArgs) int {
+		return estimateTokens(a.Unit.SourceText) + estimateTokens(a.Unit.Key+a.Unit.Context) + 8
+	}
+	for _, a := range items {
+		est.InputTokens += itemTokens(a)
+	}
+	if promptless {
+		return est, nil
+	}
+	overhead, n := 0, 0
+	for _, i := range sampleIndexes(len(batches), max(1, estimateSample/10)) {
+		var todo []batchItem
+		inItems := 0
+		for _, a := range batches[i] {
+			pu, err := s.prepare(ctx, &a)
+			if err != nil {
+				continue
+			}
+			todo = append(todo, batchItem{a: a, pu: pu, id: a.Unit.Key})
+			inItems += itemTokens(a)
+		}
+		if len(todo) == 0 {
+			continue
+		}
+		system, user, err := s.batchPrompts(ctx, prov, todo, false)
+		if err != nil {
+			return est, err
+		}
+		overhead += estimateTokens(system.Text) + estimateTokens(user.Text) - inItems
+		n++
+	}
+	est.Sampled = n
+	if n > 0 {
+		est.InputTokens += overhead / n * len(batches)
+	}
+	return est, nil
+}
+
+// sampleIndexes returns up to k indexes spread evenly over 0..n-1.
+func sampleIndexes(n, k int) []int {
+	if n <= k {
+		k = n
+	}
+	out := make([]int, k)
+	for i := range out {
+		out[i] = i * n / k
+	}
+	return out
+}
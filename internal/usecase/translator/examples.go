@@ -0,0 +1,88 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/tm"
+	"strings"
+)
+
+// FewShotConfig controls few-shot examples taken from approved translations of similar units.
+type FewShotConfig struct {
+	Disabled bool `json:"disabled"`
+	// K is the maximum number of examples per prompt.
+	K int `json:"k"`
+	// MinScore is the minimum similarity (0-100) of an example's source to the unit's source.
+	MinScore int `json:"min_score"`
+}
+
+const (
+	defaultFewShotK        = 3
+	defaultFewShotMinScore = 60
+)
+
+// FewShotSettingKey returns the settings key holding a project's few-shot configuration.
+func FewShotSettingKey(projectID int64) string {
+	return fmt.Sprintf("project.%d.few_shot", projectID)
+}
+
+// LoadFewShotConfig reads a project's few-shot configuration, filling in defaults.
+func LoadFewShotConfig(ctx context.Context, settings ports.SettingsRepository, projectID int64) FewShotConfig {
+	var c FewShotConfig
+	if settings != nil {
+		if v, err := settings.Get(ctx, FewShotSettingKey(projectID)); err == nil && strings.TrimSpace(v) != "" {
+			_ = json.Unmarshal([]byte(v), &c)
+		}
+	}
+	if c.K <= 0 {
+		c.K = defaultFewShotK
+	}
+	if c.MinScore <= 0 {
+		c.MinScore = defaultFewShotMinScore
+	}
+	return c
+}
+
+// SaveFewShotConfig stores a project's few-shot configuration.
+func SaveFewShotConfig(ctx context.Context, settings ports.SettingsRepository, projectID int64, c FewShotConfig) error {
+	if c.K < 0 || c.K > 20 {
+		return fmt.Errorf("k must be between 0 and 20")
+	}
+	if c.MinScore < 0 || c.MinScore > 100 {
+		return fmt.Errorf("min_score must be between 0 and 100")
+	}
+	b, _ := json.Marshal(c)
+	return settings.Set(ctx, FewShotSettingKey(projectID), string(b))
+}
+
+// examples returns approved translations of units similar to u in the same project and
+// target locale. Exact matches of the unit itself are excluded.
+func (s *Service) examples(ctx context.Context, pc *PromptContext, u *domain.Unit, locale string) []domain.TMMatch {
+	if s.d.Memory == nil || pc.ProjectID == 0 || pc.FewShot.Disabled {
+		return nil
+	}
+	matches, err := s.d.Memory.Lookup(ctx, tm.LookupArgs{
+		SrcLang:       pc.SourceLang,
+		TgtLang:       locale,
+		Text:          u.SourceText,
+		MinScore:      pc.FewShot.MinScore,
+		MaxResults:    pc.FewShot.K,
+		ExcludeUnitID: u.ID,
+		ProjectID:     pc.ProjectID,
+	})
+	if err != nil {
+		return nil
+	}
+	return matches
+}
+
+func promptExamples(matches []domain.TMMatch) []ports.Example {
+	out := make([]ports.Example, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, ports.Example{Source: m.Entry.SourceText, Translation: m.Entry.TargetText, Score: m.Score})
+	}
+	return out
+}
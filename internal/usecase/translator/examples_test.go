@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/domain"
+	"locail/internal/usecase/tm"
+)
+
+func TestFewShotExamples(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *FewShotConfig
+		want []string
+	}{
+		{"defaults", nil, []string{"Spiele speichern", "Jetzt speichern"}},
+		{"k", &FewShotConfig{K: 1}, []string{"Spiele speichern"}},
+		{"min score", &FewShotConfig{MinScore: 90}, []string{"Spiele speichern"}},
+		{"disabled", &FewShotConfig{Disabled: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t)
+			mem := dbsqlite.NewTMRepo(env.db)
+			env.deps.Memory = tm.New(tm.Deps{Memory: mem, Units: env.units, Files: env.deps.Files, Projects: env.deps.Projects})
+			env.svc = New(env.deps)
+			us := env.addUnits(t, "menu.save", "Save the game")
+			other := &domain.Project{Name: "Shop", SourceLang: "en"}
+			if err := env.deps.Projects.Create(ctx, other); err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range []*domain.TMEntry{
+				{SourceText: "Save the games", TargetText: "Spiele speichern", ProjectID: &env.project.ID},
+				{SourceText: "Save the game now", TargetText: "Jetzt speichern", ProjectID: &env.project.ID},
+				{SourceText: "Quit to the menu", TargetText: "Zum Menü", ProjectID: &env.project.ID},
+				// other projects and the unit's own translation are never examples
+				{SourceText: "Save the game", TargetText: "Shop speichern", ProjectID: &other.ID},
+				{SourceText: "Save the game", TargetText: "Spiel speichern", ProjectID: &env.project.ID, UnitID: &us[0].ID},
+			} {
+				e.SrcLang, e.TgtLang, e.Origin, e.Status = "en", "de", "translation", "approved"
+				if err := mem.Put(ctx, e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.cfg != nil {
+				if err := SaveFewShotConfig(ctx, env.deps.Settings, env.project.ID, *tt.cfg); err != nil {
+					t.Fatal(err)
+				}
+			}
+			res := env.translate(t, us[0], "de")
+			var got []string
+			for _, m := range res.Examples {
+				got = append(got, m.Entry.TargetText)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("examples %v, want %v", got, tt.want)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(res.User.Text, "=> "+w) {
+					t.Errorf("prompt without %q:\n%s", w, res.User.Text)
+				}
+			}
+			if len(tt.want) == 0 && strings.Contains(res.User.Text, "approved translations") {
+				t.Errorf("prompt lists examples:\n%s", res.User.Text)
+			}
+		})
+	}
+}
+
+func TestSaveFewShotConfigBounds(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	for _, c := range []FewShotConfig{{K: -1}, {K: 21}, {MinScore: -1}, {MinScore: 101}} {
+		if err := SaveFewShotConfig(ctx, env.deps.Settings, env.project.ID, c); err == nil {
+			t.Errorf("saved %+v", c)
+		}
+	}
+	if err := SaveFewShotConfig(ctx, env.deps.Settings, env.project.ID, FewShotConfig{K: 5, MinScore: 80}); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadFewShotConfig(ctx, env.deps.Settings, env.project.ID); got != (FewShotConfig{K: 5, MinScore: 80}) {
+		t.Fatalf("loaded %+v", got)
+	}
+	if got := LoadFewShotConfig(ctx, env.deps.Settings, env.project.ID+1); got != (FewShotConfig{K: defaultFewShotK, MinScore: defaultFewShotMinScore}) {
+		t.Fatalf("defaults %+v", got)
+	}
+}
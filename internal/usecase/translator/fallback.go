@@ -0,0 +1,103 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"locail/internal/ports"
+	"time"
+)
+
+// ErrInvalidOutput marks a translation that lost placeholders, tags or protected terms.
+var ErrInvalidOutput = errors.New("invalid translation")
+
+// Fallback is a provider and model tried when the ones before it in a chain fail. An empty
+// Model uses the provider's default; Options override the provider's options_json.
+type Fallback struct {
+	ProviderID int64           `json:"provider_id"`
+	Model      string          `json:"model,omitempty"`
+	Options    json.RawMessage `json:"options,omitempty"`
+}
+
+// Attempt is a chain entry that did not produce the translation.
+type Attempt struct {
+	ProviderID int64
+	Model      string
+	Err        string
+}
+
+// translateChain translates with a.ProviderID, then with each of a.Fallbacks until one
+// produces a valid translation. A translation with glossary issues is only kept when no
+// fallback does better.
+func (s *Service) translateChain(ctx context.Context, a TranslateArgs) (Result, error) {
+	res, err := s.translate(ctx, a)
+	if len(a.Fallbacks) == 0 || (err == nil && len(res.TermIssues) == 0) {
+		return res, err
+	}
+	return s.fallback(ctx, a, res, err)
+}
+
+// fallback continues a chain after the primary provider returned res, err.
+func (s *Service) fallback(ctx context.Context, a TranslateArgs, res Result, err error) (Result, error) {
+	var attempts []Attempt
+	best := -1
+	var bestRes Result
+	record := func(providerID int64, model string, res Result, err error) {
+		if err != nil {
+			attempts = append(attempts, Attempt{ProviderID: providerID, Model: model, Err: err.Error()})
+			return
+		}
+		if best < 0 {
+			best, bestRes = len(attempts), res
+		}
+		attempts = append(attempts, Attempt{ProviderID: providerID, Model: model, Err: "glossary issues"})
+	}
+	record(a.ProviderID, a.Model, res, err)
+	for _, fb := range a.Fallbacks {
+		if !shouldFallback(ctx, err) {
+			break
+		}
+		b := a
+		b.ProviderID, b.Model, b.Options, b.Fallbacks = fb.ProviderID, fb.Model, fb.Options, nil
+		res, err = s.translate(ctx, b)
+		if err == nil && len(res.TermIssues) == 0 {
+			res.Fallbacks = attempts
+			return res, nil
+		}
+		record(b.ProviderID, b.Model, res, err)
+	}
+	if best >= 0 {
+		bestRes.Fallbacks = append(attempts[:best:best], attempts[best+1:]...)
+		return bestRes, nil
+	}
+	return Result{}, err
+}
+
+// shouldFallback reports whether a chain should move on after err: the provider failed, timed
+// out or refused the content, or its output was invalid. A nil error means the output had
+// glossary issues. Nothing is tried once the caller gave up.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return err == nil || IsProviderFailure(err) || errors.Is(err, ErrInvalidOutput) || errors.Is(err, ports.ErrContentBlocked) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// ChainTimeout scales a per-attempt timeout d to the chain of a: each entry gets d, or its
+// provider's request timeout when that is longer.
+func (s *Service) ChainTimeout(ctx context.Context, a TranslateArgs, d time.Duration) time.Duration {
+	total := s.entryTimeout(ctx, a.ProviderID, a.Options, d)
+	for _, fb := range a.Fallbacks {
+		total += s.entryTimeout(ctx, fb.ProviderID, fb.Options, d)
+	}
+	return total
+}
+
+func (s *Service) entryTimeout(ctx context.Context, providerID int64, overrides json.RawMessage, d time.Duration) time.Duration {
+	prov, err := s.provider(ctx, providerID, overrides)
+	if err != nil {
+		return d
+	}
+	return attemptTimeout(d, prov)
+}
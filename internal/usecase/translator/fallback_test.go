@@ -0,0 +1,63 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"locail/internal/ports"
+	"testing"
+)
+
+func TestShouldFallback(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"glossary issues", context.Background(), nil, true},
+		{"provider error", context.Background(), &ports.ProviderError{StatusCode: 401}, true},
+		{"wrapped provider error", context.Background(), fmt.Errorf("translate: %w", &ports.ProviderError{StatusCode: 503}), true},
+		{"invalid output", context.Background(), fmt.Errorf("%w: missing placeholder", ErrInvalidOutput), true},
+		{"content blocked", context.Background(), ports.ErrContentBlocked, true},
+		{"attempt timed out", context.Background(), context.DeadlineExceeded, true},
+		{"other error", context.Background(), errors.New("unit not found"), false},
+		{"caller canceled", canceled, &ports.ProviderError{StatusCode: 503}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldFallback(tt.ctx, tt.err); got != tt.want {
+				t.Fatalf("shouldFallback(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableTranslateError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		failure   bool
+	}{
+		{"nil", nil, false, false},
+		{"rate limited", &ports.ProviderError{StatusCode: 429}, true, true},
+		{"unauthorized", &ports.ProviderError{StatusCode: 401}, false, true},
+		{"malformed response", ports.ErrMalformedResponse, true, false},
+		{"deadline", context.DeadlineExceeded, false, true},
+		{"canceled", context.Canceled, false, false},
+		{"invalid output", ErrInvalidOutput, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableTranslateError(tt.err); got != tt.retryable {
+				t.Errorf("isRetryableTranslateError = %v, want %v", got, tt.retryable)
+			}
+			if got := IsProviderFailure(tt.err); got != tt.failure {
+				t.Errorf("IsProviderFailure = %v, want %v", got, tt.failure)
+			}
+		})
+	}
+}
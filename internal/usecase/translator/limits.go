@@ -0,0 +1,115 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"locail/internal/usecase/throttle"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// maxAttempts bounds the requests made for one call, retries included.
+	maxAttempts = 4
+	// maxRetryAfter caps the delay a provider may ask for before the call gives up.
+	maxRetryAfter = 2 * time.Minute
+)
+
+// rateLimits are the provider options controlling request rates.
+type rateLimits struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	TokensPerMinute   int `json:"tokens_per_minute"`
+}
+
+// limiter returns the shared rate limiter of a provider, recreated when its limits change.
+func (s *Service) limiter(prov *domain.Provider) *throttle.Limiter {
+	var rl rateLimits
+	if strings.TrimSpace(prov.OptionsRaw) != "" {
+		_ = json.Unmarshal([]byte(prov.OptionsRaw), &rl)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limiters == nil {
+		s.limiters = map[int64]*throttle.Limiter{}
+	}
+	if l, ok := s.limiters[prov.ID]; ok {
+		if rpm, tpm := l.Limits(); rpm == rl.RequestsPerMinute && tpm == rl.TokensPerMinute {
+			return l
+		}
+	}
+	l := throttle.NewLimiter(rl.RequestsPerMinute, rl.TokensPerMinute)
+	s.limiters[prov.ID] = l
+	return l
+}
+
+// call runs fn within the provider's rate limits, retrying rate limits, server errors,
+// timeouts and malformed answers with exponential backoff. A Retry-After delay holds back
+// every request to the provider, not only this one.
+func (s *Service) call(ctx context.Context, prov *domain.Provider, tokens int, fn func() error) error {
+	lim := s.limiter(prov)
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if werr := lim.Wait(ctx, tokens); werr != nil {
+			return werr
+		}
+		err = fn()
+		if err == nil || !isRetryableTranslateError(err) || attempt == maxAttempts {
+			return err
+		}
+		delay := throttle.Backoff(attempt)
+		var pe *ports.ProviderError
+		if errors.As(err, &pe) && pe.RetryAfter > 0 {
+			if pe.RetryAfter > maxRetryAfter {
+				return err
+			}
+			delay = pe.RetryAfter
+			lim.Block(delay)
+		}
+		if serr := throttle.Sleep(ctx, delay); serr != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// estimateRequestTokens approximates the tokens of a request: prompts plus an answer about
+// as long as the source.
+func estimateRequestTokens(system, user string) int {
+	return estimateTokens(system) + 2*estimateTokens(user)
+}
+
+func isRetryableTranslateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pe *ports.ProviderError
+	if errors.As(err, &pe) {
+		return pe.Retryable()
+	}
+	if errors.Is(err, ports.ErrMalformedResponse) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// IsProviderFailure reports whether err means the provider could not serve the request, as
+// opposed to a problem with a single unit such as a missing placeholder.
+func IsProviderFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pe *ports.ProviderError
+	if errors.As(err, &pe) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,68 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"locail/internal/domain"
+	"strings"
+	"time"
+)
+
+// sharedOptions are provider options that belong to the provider rather than to a request;
+// job overrides cannot change them.
+var sharedOptions = []string{
+	"requests_per_minute", "tokens_per_minute", "input_price", "output_price",
+	"proxy_url", "no_proxy", "ca_file", "client_cert_file", "client_key_file", "insecure_skip_verify",
+}
+
+// WithOptions returns a copy of prov whose options_json has the keys of overrides (a JSON
+// object, e.g. a job's options) replacing its own.
+func WithOptions(prov *domain.Provider, overrides json.RawMessage) (*domain.Provider, error) {
+	if len(strings.TrimSpace(string(overrides))) == 0 || string(overrides) == "null" {
+		return prov, nil
+	}
+	var over map[string]json.RawMessage
+	if err := json.Unmarshal(overrides, &over); err != nil {
+		return nil, fmt.Errorf("invalid provider options override: %w", err)
+	}
+	for _, k := range sharedOptions {
+		delete(over, k)
+	}
+	if len(over) == 0 {
+		return prov, nil
+	}
+	opts := map[string]json.RawMessage{}
+	if strings.TrimSpace(prov.OptionsRaw) != "" {
+		// invalid stored options are ignored, as adapters do
+		_ = json.Unmarshal([]byte(prov.OptionsRaw), &opts)
+	}
+	for k, v := range over {
+		opts[k] = v
+	}
+	raw, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	out := *prov
+	out.OptionsRaw = string(raw)
+	return &out, nil
+}
+
+// requestTimeout returns the timeout_seconds option of a provider; 0 when unset.
+func requestTimeout(prov *domain.Provider) time.Duration {
+	var o struct {
+		TimeoutSeconds float64 `json:"timeout_seconds"`
+	}
+	if strings.TrimSpace(prov.OptionsRaw) != "" {
+		_ = json.Unmarshal([]byte(prov.OptionsRaw), &o)
+	}
+	return time.Duration(o.TimeoutSeconds * float64(time.Second))
+}
+
+// attemptTimeout stretches d to the provider's request timeout so that one slow request fits.
+func attemptTimeout(d time.Duration, prov *domain.Provider) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return max(d, requestTimeout(prov))
+}
@@ -0,0 +1,84 @@
+package translator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"locail/internal/domain"
+)
+
+func TestWithOptions(t *testing.T) {
+	stored := `{"temperature":0.7,"top_p":0.9,"requests_per_minute":60,"proxy_url":"http://proxy:3128"}`
+	tests := []struct {
+		name      string
+		stored    string
+		overrides string
+		want      map[string]any
+		same      bool
+		err       bool
+	}{
+		{name: "no overrides", stored: stored, same: true},
+		{name: "null", stored: stored, overrides: "null", same: true},
+		{name: "merged", stored: stored, overrides: `{"temperature":0,"seed":7}`, want: map[string]any{"temperature": 0.0, "top_p": 0.9, "seed": 7.0, "requests_per_minute": 60.0, "proxy_url": "http://proxy:3128"}},
+		{name: "shared options stay", stored: stored, overrides: `{"requests_per_minute":1000,"proxy_url":"","ca_file":"/tmp/ca.pem","input_price":0}`, same: true},
+		{name: "shared options are dropped from a mix", stored: stored, overrides: `{"max_tokens":50,"insecure_skip_verify":true}`, want: map[string]any{"temperature": 0.7, "top_p": 0.9, "max_tokens": 50.0, "requests_per_minute": 60.0, "proxy_url": "http://proxy:3128"}},
+		{name: "no stored options", overrides: `{"timeout_seconds":300}`, want: map[string]any{"timeout_seconds": 300.0}},
+		{name: "invalid overrides", stored: stored, overrides: `[1]`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prov := &domain.Provider{ID: 1, Type: "openai", OptionsRaw: tt.stored}
+			got, err := WithOptions(prov, json.RawMessage(tt.overrides))
+			if tt.err {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.same {
+				if got != prov {
+					t.Fatalf("options %s, want the provider unchanged", got.OptionsRaw)
+				}
+				return
+			}
+			if got == prov || prov.OptionsRaw != tt.stored {
+				t.Fatal("the stored provider was modified")
+			}
+			var opts map[string]any
+			if err := json.Unmarshal([]byte(got.OptionsRaw), &opts); err != nil {
+				t.Fatal(err)
+			}
+			if len(opts) != len(tt.want) {
+				t.Fatalf("options %v, want %v", opts, tt.want)
+			}
+			for k, v := range tt.want {
+				if opts[k] != v {
+					t.Errorf("%s = %v, want %v", k, opts[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestAttemptTimeout(t *testing.T) {
+	tests := []struct {
+		options string
+		d       time.Duration
+		want    time.Duration
+	}{
+		{"", time.Minute, time.Minute},
+		{`{"timeout_seconds":300}`, time.Minute, 5 * time.Minute},
+		{`{"timeout_seconds":10}`, time.Minute, time.Minute},
+		{`{"timeout_seconds":300}`, 0, 0},
+		{`not json`, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := attemptTimeout(tt.d, &domain.Provider{OptionsRaw: tt.options}); got != tt.want {
+			t.Errorf("attemptTimeout(%v, %s) = %v, want %v", tt.d, tt.options, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package translator
+
+import (
+	"locail/internal/domain"
+	"regexp"
+	"sort"
+)
+
+// CompileProtected turns protected terms into matchers. Literal terms are matched verbatim;
+// invalid regular expressions are skipped (they are rejected when saved).
+func CompileProtected(terms []*domain.ProtectedTerm) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(terms))
+	for _, t := range terms {
+		if t.Pattern == "" {
+			continue
+		}
+		pattern := regexp.QuoteMeta(t.Pattern)
+		if t.IsRegex {
+			pattern = t.Pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		out = append(out, re)
+	}
+	return out
+}
+
+// extractProtected returns the distinct protected strings found in s, longest first so that
+// masking "Steam Workshop" wins over a shorter "Steam".
+func extractProtected(s string, rules []*regexp.Regexp) []string {
+	uniq := map[string]struct{}{}
+	for _, re := range rules {
+		for _, m := range re.FindAllString(s, -1) {
+			if m != "" {
+				uniq[m] = struct{}{}
+			}
+		}
+	}
+	if len(uniq) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(uniq))
+	for v := range uniq {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i]) != len(out[j]) {
+			return len(out[i]) > len(out[j])
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
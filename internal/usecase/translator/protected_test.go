@@ -0,0 +1,132 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"locail/internal/domain"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractProtected(t *testing.T) {
+	rules := CompileProtected([]*domain.ProtectedTerm{
+		{Pattern: "Steam"},
+		{Pattern: "Steam Workshop"},
+		{Pattern: "C++"}, // literal, not a regex
+		{Pattern: `SKU-\d+`, IsRegex: true},
+		{Pattern: `(unclosed`, IsRegex: true},
+		{Pattern: ""},
+	})
+	if len(rules) != 4 {
+		t.Fatalf("compiled %d rules, want 4", len(rules))
+	}
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Open the Steam Workshop in Steam", []string{"Steam Workshop", "Steam"}},
+		{"Written in C++ and C", []string{"C++"}},
+		{"Order SKU-12 and SKU-7, SKU-12 again", []string{"SKU-12", "SKU-7"}},
+		{"Nothing here", nil},
+	}
+	for _, tt := range tests {
+		if got := extractProtected(tt.text, rules); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("extractProtected(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMaskRoundTrip(t *testing.T) {
+	rules := CompileProtected([]*domain.ProtectedTerm{
+		{Pattern: "Steam"},
+		{Pattern: "Steam Workshop"},
+		{Pattern: `https://\S+`, IsRegex: true},
+	})
+	many := make([]string, 12)
+	for i := range many {
+		many[i] = fmt.Sprintf("{p%d}", i)
+	}
+	tests := []struct {
+		name string
+		text string
+		// translate stands in for the model: it rewrites the masked text
+		translate func(string) string
+		want      string
+	}{
+		{
+			name:      "identity",
+			text:      "Hello {name}, open <b>Steam Workshop</b> or Steam.",
+			translate: func(s string) string { return s },
+			want:      "Hello {name}, open <b>Steam Workshop</b> or Steam.",
+		},
+		{
+			name: "reordered tokens",
+			text: "{count} items in <i>Steam</i>",
+			translate: func(s string) string {
+				words := strings.Fields(s)
+				slices.Reverse(words)
+				return strings.Join(words, " ")
+			},
+			want: "<i>Steam</i> in items {count}",
+		},
+		{
+			name:      "protected term containing a placeholder",
+			text:      "See https://example.com/{id} for {id}",
+			translate: func(s string) string { return "Siehe " + strings.TrimPrefix(s, "See ") },
+			want:      "Siehe https://example.com/{id} for {id}",
+		},
+		{
+			name:      "more than ten tokens",
+			text:      strings.Join(many, " "),
+			translate: func(s string) string { return s },
+			want:      strings.Join(many, " "),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phs, tags, prot := extractPlaceholders(tt.text), extractValveTags(tt.text), extractProtected(tt.text, rules)
+			masked, unmask := maskTokens(tt.text, phs, tags, prot)
+			for _, tok := range append(append(phs, tags...), prot...) {
+				if strings.Contains(masked, tok) {
+					t.Fatalf("masked %q still contains %q", masked, tok)
+				}
+			}
+			got := unmask(tt.translate(masked))
+			if got != tt.want {
+				t.Fatalf("unmask = %q, want %q", got, tt.want)
+			}
+			if err := validateTokens(got, phs, tags, prot); err != nil {
+				t.Fatalf("validateTokens: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		translated string
+		wantErr    string
+	}{
+		{"all kept", "Hallo {name}, <b>Steam</b>", ""},
+		{"placeholder lost", "Hallo name, <b>Steam</b>", "placeholder missing: {name}"},
+		{"tag lost", "Hallo {name}, Steam", "tag missing: <b>"},
+		{"protected term translated", "Hallo {name}, <b>Dampf</b>", "protected term missing: Steam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTokens(tt.translated, []string{"{name}"}, []string{"<b>", "</b>"}, []string{"Steam"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOutput) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
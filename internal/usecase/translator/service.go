@@ -2,139 +2,439 @@ package translator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"locail/internal/domain"
 	"locail/internal/ports"
+	"locail/internal/usecase/glossary"
+	"locail/internal/usecase/throttle"
+	"locail/internal/usecase/tm"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// CacheTTLSettingKey is the settings key holding the cache time-to-live in hours (0 = no expiry).
+const CacheTTLSettingKey = "cache.ttl_hours"
+
 type Deps struct {
+	Projects     ports.ProjectRepository
+	Files        ports.FileRepository
+	Units        ports.UnitRepository
 	Providers    ports.ProviderRepository
 	Templates    ports.TemplateRepository
 	Cache        ports.CacheRepository
 	Translations ports.TranslationRepository
 	Prompt       ports.PromptRenderer
+	Settings     ports.SettingsRepository
+	Detector     ports.LanguageDetector
+	Glossary     ports.GlossaryRepository
+	Protected    ports.ProtectedTermRepository
+	Styles       ports.StyleGuideRepository
+	// Memory supplies few-shot examples from approved translations.
+	Memory *tm.Service
 	// BuildProvider should return a concrete ports.Provider for a given provider record
 	BuildProvider func(*domain.Provider) (ports.Provider, error)
 }
 
-type Service struct{ d Deps }
+type Service struct {
+	d  Deps
+	mu sync.Mutex
+	// limiters holds the rate limiter of each provider.
+	limiters map[int64]*throttle.Limiter
+	// res resolves the context of calls that bring none, such as previews.
+	res *ContextResolver
+}
 
-func New(d Deps) *Service { return &Service{d: d} }
+func New(d Deps) *Service {
+	return &Service{d: d, res: &ContextResolver{d: d, ttl: sharedContextTTL, files: map[int64]*fileContext{}}}
+}
 
 type TranslateArgs struct {
-	ProviderID     int64
-	Unit           *domain.Unit
-	SourceLang     string
-	TargetLang     string
-	Model          string
+	ProviderID int64
+	ProjectID  int64
+	Unit       *domain.Unit
+	SourceLang string
+	TargetLang string
+	Model      string
+	// SystemOverride and UserOverride are per-job template bodies that take precedence over
+	// stored templates.
 	SystemOverride string
 	UserOverride   string
 	BypassCache    bool
+	// Context is the resolved prompt context; when nil the service resolves it, reusing a
+	// file's context for a few seconds.
+	// A non-empty SourceLang takes precedence over Context.SourceLang.
+	Context *PromptContext
+	// Fallbacks are tried in order when the provider fails, times out or returns an invalid
+	// translation.
+	Fallbacks []Fallback
+	// Timeout bounds each provider of the chain, stretched to a provider's longer request
+	// timeout; 0 leaves it to ctx.
+	Timeout time.Duration
+	// Options override the provider's options_json for this translation (sampling, timeouts,
+	// extra body); rate limits cannot be overridden.
+	Options json.RawMessage
+}
+
+// Result is the outcome of a single translation.
+type Result struct {
+	Text string
+	// TermIssues lists glossary violations; the translation is returned but should be reviewed.
+	TermIssues []domain.TermIssue
+	// Examples are the few-shot examples given to the model.
+	Examples []domain.TMMatch
+	// System and User describe the templates the prompts were rendered from.
+	System ports.RenderedPrompt
+	User   ports.RenderedPrompt
+	// ProviderID and Model identify who produced the translation.
+	ProviderID int64
+	Model      string
+	// Usage is the tokens the request that produced Text consumed; zero for cache hits.
+	Usage ports.Usage
+	// Fallbacks lists the chain entries that were tried and not used.
+	Fallbacks []Attempt
 }
 
 func (s *Service) TranslateOne(ctx context.Context, a TranslateArgs) (string, error) {
+	res, err := s.Translate(ctx, a)
+	return res.Text, err
+}
+
+// Translate translates one unit and checks the output against the project glossary, moving
+// on to the fallbacks of a when the provider does not deliver.
+func (s *Service) Translate(ctx context.Context, a TranslateArgs) (Result, error) {
+	return s.translateChain(ctx, a)
+}
+
+// translate runs one chain entry within a.Timeout.
+func (s *Service) translate(ctx context.Context, a TranslateArgs) (Result, error) {
 	if a.Unit == nil {
-		return "", errors.New("unit is required")
+		return Result{}, errors.New("unit is required")
 	}
-	prov, err := s.d.Providers.Get(ctx, a.ProviderID)
+	prov, err := s.provider(ctx, a.ProviderID, a.Options)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
-	placeholders := extractPlaceholders(a.Unit.SourceText)
-	tags := extractValveTags(a.Unit.SourceText)
-	masked, unmask := maskTokens(a.Unit.SourceText, placeholders, tags)
-
-	data := ports.PromptData{
-		SrcLang:      a.SourceLang,
-		TgtLang:      a.TargetLang,
-		Key:          a.Unit.Key,
-		Text:         masked,
-		FilePath:     "",
-		Project:      "",
-		Context:      a.Unit.Context,
-		Placeholders: placeholders,
-		Tags:         tags,
+	if d := attemptTimeout(a.Timeout, prov); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
 	}
+	pu, err := s.prepare(ctx, &a)
+	if err != nil {
+		return Result{}, err
+	}
+	data, masked, unmask := pu.data, pu.masked, pu.unmask
+	placeholders, tags, protected := pu.placeholders, pu.tags, pu.protected
+	terms, examples := pu.terms, pu.examples
 
-	system := a.SystemOverride
-	user := a.UserOverride
-	if system == "" {
-		system, err = s.d.Prompt.Render(ctx, "provider", &prov.ID, "translate_single", "system", data)
-		if err != nil {
-			return "", err
-		}
+	adapter, promptless, err := s.adapter(prov)
+	if err != nil {
+		return Result{}, err
 	}
-	if user == "" {
-		user, err = s.d.Prompt.Render(ctx, "provider", &prov.ID, "translate_single", "user", data)
-		if err != nil {
-			return "", err
-		}
+	// machine translation engines get the masked text only
+	system, user, err := s.singlePrompts(ctx, prov, a, data, promptless)
+	if err != nil {
+		return Result{}, err
+	}
+	tokens := estimateRequestTokens(system.Text, user.Text)
+	if promptless {
+		tokens = estimateRequestTokens("", masked)
 	}
 	segment := ports.Segment{Key: a.Unit.Key, Text: masked, Context: a.Unit.Context, Placeholders: placeholders, Tags: tags}
 
-	// Cache lookup (masked variant to protect placeholders)
+	// Cache lookup: both key and stored translation are masked, so entries are
+	// independent of the concrete placeholder names in the source.
+	key := s.cacheKey(ctx, prov, masked, a, data, promptless)
 	if !a.BypassCache {
-		if ce, _ := s.d.Cache.Get(ctx, masked, a.SourceLang, a.TargetLang, prov.Type, a.Model); ce != nil {
-			out := unmask(ce.Translation)
-			return out, nil
+		if out, ok := s.cached(ctx, key, pu); ok {
+			return Result{Text: out, TermIssues: glossary.Check(terms, out, a.TargetLang), Examples: examples, System: system, User: user, ProviderID: prov.ID, Model: key.Model}, nil
 		}
 	}
 
-	// Build provider and execute translation
-	if s.d.BuildProvider == nil {
-		return "", fmt.Errorf("TranslateOne: provider builder missing")
-	}
-	adapter, err := s.d.BuildProvider(prov)
-	if err != nil {
-		return "", err
-	}
 	var res ports.TranslateResult
-	var trErr error
-	for attempt := 1; attempt <= 3; attempt++ {
-		res, trErr = adapter.Translate(ctx, segment, ports.TranslateParams{
+	err = s.call(ctx, prov, tokens, func() error {
+		var err error
+		res, err = adapter.Translate(ctx, segment, ports.TranslateParams{
 			SourceLang:   a.SourceLang,
 			TargetLang:   a.TargetLang,
 			Model:        a.Model,
 			Temperature:  0.0,
-			SystemPrompt: system,
-			UserPrompt:   user,
+			SystemPrompt: system.Text,
+			UserPrompt:   user.Text,
 		})
-		if trErr == nil {
-			break
-		}
-		// Retry only on parse/formatting errors that models often flake on
-		if !isRetryableTranslateError(trErr) || attempt == 3 {
-			return "", trErr
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	maskedOut := strings.TrimSpace(res.Translation)
+	translated := unmask(maskedOut)
+	if err := validateTokens(translated, placeholders, tags, protected); err != nil {
+		return Result{}, err
+	}
+	s.storeCache(ctx, key, maskedOut, a.ProjectID)
+	return Result{Text: translated, TermIssues: glossary.Check(terms, translated, a.TargetLang), Examples: examples, System: system, User: user, ProviderID: prov.ID, Model: key.Model, Usage: res.Usage}, nil
+}
+
+// singlePrompts renders the translate_single prompts of a unit; promptless providers get none.
+func (s *Service) singlePrompts(ctx context.Context, prov *domain.Provider, a TranslateArgs, data ports.PromptData, promptless bool) (system, user ports.RenderedPrompt, err error) {
+	if promptless {
+		return system, user, nil
+	}
+	scope := templateScope(prov.ID, a.ProjectID)
+	system, err = s.d.Prompt.Render(ctx, scope, "translate_single", "system", a.SystemOverride, data)
+	if err != nil {
+		return system, user, err
+	}
+	user, err = s.d.Prompt.Render(ctx, scope, "translate_single", "user", a.UserOverride, data)
+	return system, user, err
+}
+
+// provider loads a provider with overrides applied to its options.
+func (s *Service) provider(ctx context.Context, id int64, overrides json.RawMessage) (*domain.Provider, error) {
+	prov, err := s.d.Providers.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return WithOptions(prov, overrides)
+}
+
+// adapter builds the provider client and reports whether it translates without prompts.
+func (s *Service) adapter(prov *domain.Provider) (ports.Provider, bool, error) {
+	if s.d.BuildProvider == nil {
+		return nil, false, errors.New("provider builder missing")
+	}
+	p, err := s.d.BuildProvider(prov)
+	if err != nil {
+		return nil, false, err
+	}
+	pl, ok := p.(ports.PromptlessProvider)
+	return p, ok && pl.Promptless(), nil
+}
+
+// cached returns the unmasked cached translation for key when it is fresh and still valid.
+func (s *Service) cached(ctx context.Context, key domain.CacheKey, pu *preparedUnit) (string, bool) {
+	ce, _ := s.d.Cache.Get(ctx, key)
+	if ce == nil || s.cacheExpired(ctx, ce) {
+		return "", false
+	}
+	out := pu.unmask(ce.Translation)
+	if err := validateTokens(out, pu.placeholders, pu.tags, pu.protected); err != nil {
+		return "", false
+	}
+	_ = s.d.Cache.Touch(ctx, ce.ID)
+	return out, true
+}
+
+func (s *Service) storeCache(ctx context.Context, key domain.CacheKey, maskedOut string, projectID int64) {
+	var pid *int64
+	if projectID != 0 {
+		pid = &projectID
+	}
+	_ = s.d.Cache.Put(ctx, &domain.CacheEntry{
+		SourceText:      key.SourceText,
+		SrcLang:         key.SrcLang,
+		TgtLang:         key.TgtLang,
+		Provider:        key.Provider,
+		Model:           key.Model,
+		TemplateVersion: key.TemplateVersion,
+		ContextHash:     key.ContextHash,
+		Translation:     maskedOut,
+		ProjectID:       pid,
+	})
+}
+
+// preparedUnit is a unit ready to be put into a prompt: masked text, the tokens that must
+// survive translation and the template data.
+type preparedUnit struct {
+	data         ports.PromptData
+	masked       string
+	unmask       func(string) string
+	placeholders []string
+	tags         []string
+	protected    []string
+	terms        []*domain.GlossaryTerm
+	examples     []domain.TMMatch
+}
+
+// prepare resolves the unit's context (filling a.SourceLang and a.ProjectID when empty), masks its text and
+// builds the template data.
+func (s *Service) prepare(ctx context.Context, a *TranslateArgs) (*preparedUnit, error) {
+	pc := a.Context
+	if pc == nil {
+		resolved, err := s.res.Resolve(ctx, a.Unit)
+		if err != nil {
+			return nil, err
 		}
-		// small backoff
-		time.Sleep(time.Duration(200*attempt) * time.Millisecond)
+		pc = &resolved
+	}
+	if a.SourceLang == "" {
+		a.SourceLang = pc.SourceLang
 	}
-	translated := unmask(strings.TrimSpace(res.Translation))
+	if a.ProjectID == 0 {
+		a.ProjectID = pc.ProjectID
+	}
+	pu := &preparedUnit{
+		placeholders: extractPlaceholders(a.Unit.SourceText),
+		tags:         extractValveTags(a.Unit.SourceText),
+		protected:    extractProtected(a.Unit.SourceText, pc.Protected),
+		terms:        glossary.Match(pc.Glossary, a.Unit.SourceText),
+		examples:     s.examples(ctx, pc, a.Unit, a.TargetLang),
+	}
+	pu.masked, pu.unmask = maskTokens(a.Unit.SourceText, pu.placeholders, pu.tags, pu.protected)
+	style := ResolveStyle(pc.StyleGuides, a.TargetLang)
+	pu.data = ports.PromptData{
+		SrcLang:      a.SourceLang,
+		TgtLang:      a.TargetLang,
+		Key:          a.Unit.Key,
+		Text:         pu.masked,
+		FilePath:     pc.FilePath,
+		Project:      pc.Project,
+		Context:      a.Unit.Context,
+		Placeholders: pu.placeholders,
+		Tags:         pu.tags,
+		Neighbors:    pc.Neighbors,
+		Glossary:     glossary.PromptEntries(pu.terms, a.TargetLang),
+		Examples:     promptExamples(pu.examples),
+		Formality:    style.Formality,
+		Tone:         style.Tone,
+		Audience:     style.Audience,
+		Punctuation:  style.Punctuation,
+		StyleNotes:   style.Notes,
+	}
+	return pu, nil
+}
+
+// PromptData returns the template data a unit would be translated with, e.g. for previews.
+func (s *Service) PromptData(ctx context.Context, u *domain.Unit, targetLang string) (ports.PromptData, error) {
+	if u == nil {
+		return ports.PromptData{}, errors.New("unit is required")
+	}
+	a := TranslateArgs{Unit: u, TargetLang: targetLang}
+	pu, err := s.prepare(ctx, &a)
+	if err != nil {
+		return ports.PromptData{}, err
+	}
+	return pu.data, nil
+}
+
+// validateTokens ensures every placeholder, tag and protected term of the source survived translation.
+func validateTokens(translated string, placeholders, tags, protected []string) error {
 	for _, ph := range placeholders {
 		if !strings.Contains(translated, ph) {
-			return "", fmt.Errorf("placeholder missing in translation: %s", ph)
+			return fmt.Errorf("%w: placeholder missing: %s", ErrInvalidOutput, ph)
 		}
 	}
 	for _, tg := range tags {
 		if !strings.Contains(translated, tg) {
-			return "", fmt.Errorf("tag missing in translation: %s", tg)
+			return fmt.Errorf("%w: tag missing: %s", ErrInvalidOutput, tg)
 		}
 	}
-	// Save cache
-	_ = s.d.Cache.Put(ctx, &domain.CacheEntry{
-		SourceText:  masked,
-		SrcLang:     a.SourceLang,
-		TgtLang:     a.TargetLang,
-		Provider:    prov.Type,
-		Model:       a.Model,
-		Translation: translated,
-	})
-	return translated, nil
+	for _, pt := range protected {
+		if !strings.Contains(translated, pt) {
+			return fmt.Errorf("%w: protected term missing: %s", ErrInvalidOutput, pt)
+		}
+	}
+	return nil
+}
+
+// cacheKey builds a normalized cache key for a masked source text. Prompts and context do not
+// matter to promptless providers.
+func (s *Service) cacheKey(ctx context.Context, prov *domain.Provider, masked string, a TranslateArgs, data ports.PromptData, promptless bool) domain.CacheKey {
+	model := strings.TrimSpace(a.Model)
+	if model == "" {
+		model = strings.TrimSpace(prov.Model)
+	}
+	if promptless {
+		return domain.CacheKey{
+			SourceText:      masked,
+			SrcLang:         normalizeLang(a.SourceLang),
+			TgtLang:         normalizeLang(a.TargetLang),
+			Provider:        strings.ToLower(strings.TrimSpace(prov.Type)),
+			Model:           model,
+			TemplateVersion: "native",
+		}
+	}
+	return domain.CacheKey{
+		SourceText:      masked,
+		SrcLang:         normalizeLang(a.SourceLang),
+		TgtLang:         normalizeLang(a.TargetLang),
+		Provider:        strings.ToLower(strings.TrimSpace(prov.Type)),
+		Model:           model,
+		TemplateVersion: s.templateVersion(ctx, prov, a),
+		ContextHash:     contextHash(data),
+	}
+}
+
+// contextHash digests the prompt inputs other than the source text that change its
+// translation: project, style guide and the glossary terms found in the text. The unit's key,
+// comment, neighbors and few-shot examples are left out so that the same string is reused
+// across keys and files, and editing a unit does not invalidate the entries around it.
+func contextHash(d ports.PromptData) string {
+	h := sha256.New()
+	for _, v := range []string{d.Project, d.Formality, d.Tone, d.Audience, d.Punctuation, d.StyleNotes} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	for _, g := range d.Glossary {
+		fmt.Fprintf(h, "%s=%q!%q dnt=%t\x00", g.Term, g.Translations, g.Forbidden, g.DoNotTranslate)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// templateVersion returns a short hash of the prompt template bodies in effect, so that
+// editing a prompt does not serve translations produced by the previous one.
+func (s *Service) templateVersion(ctx context.Context, prov *domain.Provider, a TranslateArgs) string {
+	bodies := []string{a.SystemOverride, a.UserOverride}
+	for i, role := range []string{"system", "user"} {
+		if bodies[i] != "" || s.d.Templates == nil {
+			continue
+		}
+		if t, _ := s.d.Templates.GetEffective(ctx, templateScope(prov.ID, a.ProjectID), "translate_single", role); t != nil {
+			bodies[i] = t.Body
+		} else {
+			bodies[i] = "builtin"
+		}
+	}
+	sum := sha256.Sum256([]byte(bodies[0] + "\x00" + bodies[1]))
+	return hex.EncodeToString(sum[:6])
+}
+
+// templateScope selects provider templates first, then project ones.
+func templateScope(providerID, projectID int64) ports.TemplateScope {
+	scope := ports.TemplateScope{ProviderID: &providerID}
+	if projectID != 0 {
+		scope.ProjectID = &projectID
+	}
+	return scope
+}
+
+// cacheExpired reports whether an entry is older than the configured TTL.
+func (s *Service) cacheExpired(ctx context.Context, e *domain.CacheEntry) bool {
+	if s.d.Settings == nil {
+		return false
+	}
+	v, err := s.d.Settings.Get(ctx, CacheTTLSettingKey)
+	if err != nil {
+		return false
+	}
+	hours, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || hours <= 0 {
+		return false
+	}
+	return time.Since(e.CreatedAt) > time.Duration(hours)*time.Hour
+}
+
+func normalizeLang(l string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(l)), "_", "-")
 }
 
 var placeholderRE = regexp.MustCompile(`\{[^}]+\}`)
@@ -174,10 +474,16 @@ func extractValveTags(s string) []string {
 	return out
 }
 
-func maskTokens(s string, placeholders, tags []string) (string, func(string) string) {
+func maskTokens(s string, placeholders, tags, protected []string) (string, func(string) string) {
 	masked := s
 	repls := []struct{ from, to string }{}
-	// Replace placeholders first
+	// Protected terms first: they may contain placeholders or tags (e.g. URLs)
+	for i, pt := range protected {
+		token := fmt.Sprintf("__PT_%d__", i)
+		masked = strings.ReplaceAll(masked, pt, token)
+		repls = append(repls, struct{ from, to string }{from: token, to: pt})
+	}
+	// Then placeholders
 	for i, ph := range placeholders {
 		token := fmt.Sprintf("__PH_%d__", i)
 		masked = strings.ReplaceAll(masked, ph, token)
@@ -199,24 +505,3 @@ func maskTokens(s string, placeholders, tags []string) (string, func(string) str
 	}
 	return masked, unmask
 }
-
-// isRetryableTranslateError returns true for transient output/format issues that
-// are likely to succeed on retry (e.g., invalid/missing JSON in model response).
-func isRetryableTranslateError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := strings.ToLower(err.Error())
-	switch {
-	case strings.Contains(msg, "failed to parse translation json"):
-		return true
-	case strings.Contains(msg, "no choices returned"):
-		return true
-	case strings.Contains(msg, "unexpected end of"):
-		return true
-	case strings.Contains(msg, "invalid character"):
-		return true
-	default:
-		return false
-	}
-}
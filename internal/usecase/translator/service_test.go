@@ -0,0 +1,288 @@
+package translator
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbsqlite "locail/internal/adapters/db/sqlite"
+	"locail/internal/adapters/prompt"
+	"locail/internal/domain"
+	"locail/internal/ports"
+)
+
+// fakeProvider answers with the masked segment text run through translate and records the
+// segments and prompts it was sent.
+type fakeProvider struct {
+	translate func(string) string
+
+	mu      sync.Mutex
+	calls   int
+	segs    []ports.Segment
+	prompts []string
+}
+
+func (f *fakeProvider) Translate(_ context.Context, seg ports.Segment, p ports.TranslateParams) (ports.TranslateResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.segs = append(f.segs, seg)
+	f.prompts = append(f.prompts, p.UserPrompt)
+	return ports.TranslateResult{Translation: f.translate(seg.Text), Usage: ports.Usage{InputTokens: 10, OutputTokens: 5}}, nil
+}
+
+func (f *fakeProvider) TranslateBatch(_ context.Context, segs []ports.Segment, _ ports.TranslateParams) (ports.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	out := map[string]string{}
+	for _, s := range segs {
+		f.segs = append(f.segs, s)
+		out[s.Key] = f.translate(s.Text)
+	}
+	return ports.BatchResult{Translations: out}, nil
+}
+
+func (f *fakeProvider) ListModels(context.Context) ([]ports.ModelInfo, error) { return nil, nil }
+func (f *fakeProvider) Test(context.Context) error                            { return nil }
+
+func (f *fakeProvider) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// testEnv is a translator over a fresh database with one project, one file and a provider
+// served by fake.
+type testEnv struct {
+	db       *sql.DB
+	svc      *Service
+	fake     *fakeProvider
+	project  *domain.Project
+	file     *domain.File
+	provider *domain.Provider
+	units    *dbsqlite.UnitRepo
+	deps     Deps
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+	db, err := dbsqlite.Init(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	env := &testEnv{
+		db:    db,
+		fake:  &fakeProvider{translate: func(s string) string { return strings.ReplaceAll(s, "Hi", "Hallo") }},
+		units: dbsqlite.NewUnitRepo(db),
+	}
+	projects := dbsqlite.NewProjectRepo(db)
+	files := dbsqlite.NewFileRepo(db)
+	providers := dbsqlite.NewProviderRepo(db)
+	templates := dbsqlite.NewTemplateRepo(db)
+	if err := prompt.SeedDefaults(ctx, templates); err != nil {
+		t.Fatal(err)
+	}
+	env.project = &domain.Project{Name: "Game", SourceLang: "en"}
+	if err := projects.Create(ctx, env.project); err != nil {
+		t.Fatal(err)
+	}
+	env.file = &domain.File{ProjectID: env.project.ID, Path: "ui.json", Format: "json", Locale: "en"}
+	if err := files.Create(ctx, env.file); err != nil {
+		t.Fatal(err)
+	}
+	env.provider = &domain.Provider{Type: "ollama", Name: "fake", Model: "m"}
+	if err := providers.Create(ctx, env.provider); err != nil {
+		t.Fatal(err)
+	}
+	env.deps = Deps{
+		Projects:      projects,
+		Files:         files,
+		Units:         env.units,
+		Providers:     providers,
+		Templates:     templates,
+		Cache:         dbsqlite.NewCacheRepo(db),
+		Translations:  dbsqlite.NewTranslationRepo(db),
+		Prompt:        prompt.New(templates),
+		Settings:      dbsqlite.NewSettingsRepo(db),
+		Glossary:      dbsqlite.NewGlossaryRepo(db),
+		Protected:     dbsqlite.NewProtectedTermRepo(db),
+		Styles:        dbsqlite.NewStyleGuideRepo(db),
+		BuildProvider: func(*domain.Provider) (ports.Provider, error) { return env.fake, nil },
+	}
+	env.svc = New(env.deps)
+	return env
+}
+
+// addUnits stores key/source pairs in the env's file and returns them in order.
+func (env *testEnv) addUnits(t *testing.T, kv ...string) []*domain.Unit {
+	t.Helper()
+	ctx := context.Background()
+	var us []*domain.Unit
+	for i := 0; i+1 < len(kv); i += 2 {
+		us = append(us, &domain.Unit{FileID: env.file.ID, Key: kv[i], SourceText: kv[i+1]})
+	}
+	if err := env.units.UpsertBatch(ctx, us); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := env.units.ListByFile(ctx, env.file.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byKey := map[string]*domain.Unit{}
+	for _, u := range stored {
+		byKey[u.Key] = u
+	}
+	for i, u := range us {
+		us[i] = byKey[u.Key]
+	}
+	return us
+}
+
+func (env *testEnv) translate(t *testing.T, u *domain.Unit, locale string) Result {
+	t.Helper()
+	res, err := env.svc.Translate(context.Background(), TranslateArgs{ProviderID: env.provider.ID, Unit: u, TargetLang: locale})
+	if err != nil {
+		t.Fatalf("translate %s: %v", u.Key, err)
+	}
+	return res
+}
+
+func TestTranslateServesMaskedCache(t *testing.T) {
+	env := newTestEnv(t)
+	us := env.addUnits(t, "greet.name", "Hi {name}", "greet.user", "Hi {user}", "menu.title", "Menu")
+
+	tests := []struct {
+		unit  *domain.Unit
+		want  string
+		calls int
+	}{
+		{us[0], "Hallo {name}", 1},
+		// same text with another placeholder name: a cache hit, unmasked with its own name
+		{us[1], "Hallo {user}", 1},
+		{us[2], "Menu", 2},
+	}
+	for _, tt := range tests {
+		res := env.translate(t, tt.unit, "de")
+		if res.Text != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.unit.Key, res.Text, tt.want)
+		}
+		if n := env.fake.count(); n != tt.calls {
+			t.Errorf("%s: provider called %d times, want %d", tt.unit.Key, n, tt.calls)
+		}
+	}
+	if seg := env.fake.segs[0]; seg.Text != "Hi __PH_0__" {
+		t.Errorf("provider got %q, want the masked text", seg.Text)
+	}
+
+	var stored string
+	if err := env.db.QueryRow(`SELECT translation FROM cache WHERE source_text = 'Hi __PH_0__'`).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != "Hallo __PH_0__" {
+		t.Errorf("cache holds %q, want the masked translation", stored)
+	}
+}
+
+func TestTranslateBypassCache(t *testing.T) {
+	env := newTestEnv(t)
+	us := env.addUnits(t, "a", "Hi {name}")
+	env.translate(t, us[0], "de")
+	res, err := env.svc.Translate(context.Background(), TranslateArgs{ProviderID: env.provider.ID, Unit: us[0], TargetLang: "de", BypassCache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "Hallo {name}" || env.fake.count() != 2 {
+		t.Fatalf("got %q after %d calls, want a second provider call", res.Text, env.fake.count())
+	}
+}
+
+func TestContextHash(t *testing.T) {
+	base := ports.PromptData{
+		Project:  "Game",
+		Key:      "menu.save",
+		FilePath: "ui.json",
+		Context:  "button",
+		Tone:     "playful",
+		Glossary: []ports.GlossaryEntry{{Term: "Save", Translations: []string{"Speichern"}}},
+		Neighbors: []ports.NeighborUnit{
+			{Key: "menu.load", Text: "Load"},
+		},
+		Examples: []ports.Example{{Source: "Save all", Translation: "Alle speichern"}},
+	}
+	tests := []struct {
+		name   string
+		change func(*ports.PromptData)
+		same   bool
+	}{
+		{"other key", func(d *ports.PromptData) { d.Key = "dialog.save" }, true},
+		{"other file", func(d *ports.PromptData) { d.FilePath = "hud.json" }, true},
+		{"other comment", func(d *ports.PromptData) { d.Context = "" }, true},
+		{"edited neighbor", func(d *ports.PromptData) { d.Neighbors = []ports.NeighborUnit{{Key: "menu.load", Text: "Load game"}} }, true},
+		{"other examples", func(d *ports.PromptData) { d.Examples = nil }, true},
+		{"other project", func(d *ports.PromptData) { d.Project = "Shop" }, false},
+		{"other tone", func(d *ports.PromptData) { d.Tone = "formal" }, false},
+		{"other glossary translation", func(d *ports.PromptData) {
+			d.Glossary = []ports.GlossaryEntry{{Term: "Save", Translations: []string{"Sichern"}}}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := base
+			tt.change(&d)
+			if got := contextHash(d) == contextHash(base); got != tt.same {
+				t.Fatalf("same hash = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestPromptDataReusesFileContext(t *testing.T) {
+	env := newTestEnv(t)
+	us := env.addUnits(t, "a", "One", "b", "Two")
+	ctx := context.Background()
+	if _, err := env.svc.PromptData(ctx, us[0], "de"); err != nil {
+		t.Fatal(err)
+	}
+	// a unit added after the first call is not seen while the file's context is fresh
+	env.addUnits(t, "c", "Three")
+	data, err := env.svc.PromptData(ctx, us[1], "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Neighbors) != 1 {
+		t.Fatalf("neighbors %+v, want the memoized file", data.Neighbors)
+	}
+	env.svc.res.ttl = time.Nanosecond
+	if data, _ = env.svc.PromptData(ctx, us[1], "de"); len(data.Neighbors) != 2 {
+		t.Fatalf("neighbors %+v, want a reload after the ttl", data.Neighbors)
+	}
+}
+
+// promptlessProvider is a fake machine translation engine.
+type promptlessProvider struct{ *fakeProvider }
+
+func (promptlessProvider) Promptless() bool { return true }
+
+func TestTranslatePromptlessSkipsPrompts(t *testing.T) {
+	env := newTestEnv(t)
+	env.svc.d.BuildProvider = func(*domain.Provider) (ports.Provider, error) { return promptlessProvider{env.fake}, nil }
+	us := env.addUnits(t, "a", "Hi {name}")
+	// an override that does not parse is never rendered
+	res, err := env.svc.Translate(context.Background(), TranslateArgs{ProviderID: env.provider.ID, Unit: us[0], TargetLang: "de", UserOverride: "{{ .Broken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "Hallo {name}" || res.System.Text != "" || res.User.Text != "" {
+		t.Fatalf("got %+v", res)
+	}
+	if p := env.fake.prompts[0]; p != "" || env.fake.segs[0].Text != "Hi __PH_0__" {
+		t.Fatalf("provider got prompt %q for %q", p, env.fake.segs[0].Text)
+	}
+}
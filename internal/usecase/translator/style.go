@@ -0,0 +1,48 @@
+package translator
+
+import (
+	"locail/internal/domain"
+	"strings"
+)
+
+// ResolveStyle merges a project's style guides for a target locale: the project-wide guide,
+// then the base language ("de"), then the exact locale ("de-at"). Non-empty fields of more
+// specific guides win; notes are concatenated.
+func ResolveStyle(guides []*domain.StyleGuide, locale string) domain.StyleGuide {
+	locale = normalizeLang(locale)
+	base, _, _ := strings.Cut(locale, "-")
+	levels := []string{""}
+	if base != "" {
+		levels = append(levels, base)
+	}
+	if locale != base {
+		levels = append(levels, locale)
+	}
+	var out domain.StyleGuide
+	for _, want := range levels {
+		for _, g := range guides {
+			if normalizeLang(g.Locale) != want {
+				continue
+			}
+			out.ProjectID = g.ProjectID
+			out.Locale = want
+			override(&out.Formality, g.Formality)
+			override(&out.Tone, g.Tone)
+			override(&out.Audience, g.Audience)
+			override(&out.Punctuation, g.Punctuation)
+			if n := strings.TrimSpace(g.Notes); n != "" {
+				if out.Notes != "" {
+					out.Notes += "\n"
+				}
+				out.Notes += n
+			}
+		}
+	}
+	return out
+}
+
+func override(dst *string, v string) {
+	if v = strings.TrimSpace(v); v != "" {
+		*dst = v
+	}
+}
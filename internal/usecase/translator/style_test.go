@@ -0,0 +1,59 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"locail/internal/domain"
+)
+
+func TestResolveStyle(t *testing.T) {
+	guides := []*domain.StyleGuide{
+		{Locale: "de_AT", Tone: "relaxed", Notes: "Austrian terms"},
+		{Locale: "", Formality: "formal", Tone: "neutral", Audience: "gamers", Notes: "Keep it short"},
+		{Locale: "de", Formality: "informal (du)", Punctuation: "„ “ quotes", Notes: " "},
+		{Locale: "fr", Formality: "formal (vous)"},
+	}
+	tests := []struct {
+		locale string
+		want   domain.StyleGuide
+	}{
+		{"de-AT", domain.StyleGuide{Locale: "de-at", Formality: "informal (du)", Tone: "relaxed", Audience: "gamers", Punctuation: "„ “ quotes", Notes: "Keep it short\nAustrian terms"}},
+		{"de", domain.StyleGuide{Locale: "de", Formality: "informal (du)", Tone: "neutral", Audience: "gamers", Punctuation: "„ “ quotes", Notes: "Keep it short"}},
+		{"de-CH", domain.StyleGuide{Locale: "de", Formality: "informal (du)", Tone: "neutral", Audience: "gamers", Punctuation: "„ “ quotes", Notes: "Keep it short"}},
+		{"fr_CA", domain.StyleGuide{Locale: "fr", Formality: "formal (vous)", Tone: "neutral", Audience: "gamers", Notes: "Keep it short"}},
+		{"ja", domain.StyleGuide{Locale: "", Formality: "formal", Tone: "neutral", Audience: "gamers", Notes: "Keep it short"}},
+	}
+	for _, tt := range tests {
+		if got := ResolveStyle(guides, tt.locale); got != tt.want {
+			t.Errorf("ResolveStyle(%s) = %+v, want %+v", tt.locale, got, tt.want)
+		}
+	}
+	if got := ResolveStyle(nil, "de"); got != (domain.StyleGuide{}) {
+		t.Errorf("no guides: %+v", got)
+	}
+}
+
+func TestTranslateFollowsStyleGuide(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	for _, g := range []*domain.StyleGuide{
+		{ProjectID: env.project.ID, Tone: "neutral", Notes: "Keep it short"},
+		{ProjectID: env.project.ID, Locale: "de", Tone: "playful", Formality: "informal (du)"},
+	} {
+		if err := env.deps.Styles.Upsert(ctx, g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	us := env.addUnits(t, "a", "Hi")
+	res := env.translate(t, us[0], "de")
+	for _, want := range []string{"Formality: informal (du).", "Tone: playful.", "Style notes: Keep it short"} {
+		if !strings.Contains(res.System.Text, want) {
+			t.Errorf("system prompt without %q:\n%s", want, res.System.Text)
+		}
+	}
+	if fr := env.translate(t, us[0], "fr"); !strings.Contains(fr.System.Text, "Tone: neutral.") || strings.Contains(fr.System.Text, "Formality") {
+		t.Errorf("fr system prompt:\n%s", fr.System.Text)
+	}
+}
@@ -0,0 +1,174 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"strings"
+	"sync"
+	"time"
+)
+
+// priceTTL is how long listed model prices are trusted before the list is fetched again.
+const priceTTL = 24 * time.Hour
+
+type Deps struct {
+	Providers     ports.ProviderRepository
+	Jobs          ports.JobRepository
+	Usage         ports.UsageRepository
+	BuildProvider func(*domain.Provider) (ports.Provider, error)
+}
+
+// Service prices token usage and records it per job item.
+type Service struct {
+	d  Deps
+	mu sync.Mutex
+	// fetched is when a provider's model list was last requested, so that providers that
+	// fail or publish no prices are not asked on every item.
+	fetched map[int64]time.Time
+}
+
+func New(d Deps) *Service { return &Service{d: d, fetched: map[int64]time.Time{}} }
+
+// Price is what a model charges in USD per million tokens.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Cost returns the cost of u in USD.
+func (p Price) Cost(u ports.Usage) float64 {
+	return (float64(u.InputTokens)*p.Input + float64(u.OutputTokens)*p.Output) / 1e6
+}
+
+// Price returns the price of a model of a provider: the provider's input_price and
+// output_price options, else the prices its model list publishes. ok is false when neither
+// is known.
+func (s *Service) Price(ctx context.Context, providerID int64, model string) (Price, bool) {
+	prov, err := s.d.Providers.Get(ctx, providerID)
+	if err != nil || prov == nil {
+		return Price{}, false
+	}
+	if p, ok := optionPrice(prov); ok {
+		return p, true
+	}
+	if model == "" {
+		model = prov.Model
+	}
+	models, _ := s.d.Providers.ListModelCache(ctx, providerID)
+	if stale(models) && s.shouldFetch(providerID) {
+		if fresh, err := s.RefreshPrices(ctx, prov); err == nil {
+			models = fresh
+		}
+	}
+	for _, m := range models {
+		if strings.EqualFold(m.Name, model) && (m.InputPrice > 0 || m.OutputPrice > 0) {
+			return Price{Input: m.InputPrice, Output: m.OutputPrice}, true
+		}
+	}
+	return Price{}, false
+}
+
+// RefreshPrices fetches the model list of a provider and caches it with its prices.
+func (s *Service) RefreshPrices(ctx context.Context, prov *domain.Provider) ([]*domain.ProviderModel, error) {
+	if s.d.BuildProvider == nil {
+		return nil, errors.New("provider builder missing")
+	}
+	p, err := s.d.BuildProvider(prov)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := p.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]*domain.ProviderModel, 0, len(infos))
+	for _, mi := range infos {
+		models = append(models, &domain.ProviderModel{
+			ProviderID:    prov.ID,
+			Name:          mi.Name,
+			ContextTokens: mi.ContextTokens,
+			InputPrice:    mi.InputPrice,
+			OutputPrice:   mi.OutputPrice,
+		})
+	}
+	if err := s.d.Providers.SaveModelCache(ctx, prov.ID, models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// throughputJobs is how many recent jobs of a model predict its speed.
+const throughputJobs = 20
+
+// ItemDuration returns the average wall-clock time per item of recent jobs of a provider and
+// model; ok is false without history.
+func (s *Service) ItemDuration(ctx context.Context, providerID int64, model string) (time.Duration, bool) {
+	items, seconds, err := s.d.Usage.Throughput(ctx, providerID, model, throughputJobs)
+	if err != nil || items == 0 || seconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(seconds / float64(items) * float64(time.Second)), true
+}
+
+// Record stores the usage of a job item translated by a provider and model and returns its
+// cost. Items without tokens, such as cache hits, cost nothing; others have no cost when the
+// price is unknown.
+func (s *Service) Record(ctx context.Context, itemID, providerID int64, model string, u ports.Usage) (*float64, error) {
+	var cost *float64
+	if u.InputTokens == 0 && u.OutputTokens == 0 {
+		zero := 0.0
+		cost = &zero
+	} else if p, ok := s.Price(ctx, providerID, model); ok {
+		c := p.Cost(u)
+		cost = &c
+	}
+	return cost, s.d.Jobs.SetItemUsage(ctx, itemID, providerID, model, u.InputTokens, u.OutputTokens, cost)
+}
+
+// Report sums recorded usage per the groups of q.
+func (s *Service) Report(ctx context.Context, q domain.UsageQuery) ([]*domain.UsageRow, error) {
+	return s.d.Usage.Report(ctx, q)
+}
+
+func (s *Service) shouldFetch(providerID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.fetched[providerID]; ok && time.Since(t) < priceTTL {
+		return false
+	}
+	s.fetched[providerID] = time.Now()
+	return true
+}
+
+// stale reports whether a cached model list is missing or older than priceTTL.
+func stale(models []*domain.ProviderModel) bool {
+	if len(models) == 0 {
+		return true
+	}
+	return time.Since(models[0].UpdatedAt) > priceTTL
+}
+
+// optionPrice returns the price set in a provider's options, if any.
+func optionPrice(prov *domain.Provider) (Price, bool) {
+	var o struct {
+		InputPrice  *float64 `json:"input_price"`
+		OutputPrice *float64 `json:"output_price"`
+	}
+	if strings.TrimSpace(prov.OptionsRaw) != "" {
+		_ = json.Unmarshal([]byte(prov.OptionsRaw), &o)
+	}
+	if o.InputPrice == nil && o.OutputPrice == nil {
+		return Price{}, false
+	}
+	var p Price
+	if o.InputPrice != nil {
+		p.Input = *o.InputPrice
+	}
+	if o.OutputPrice != nil {
+		p.Output = *o.OutputPrice
+	}
+	return p, true
+}
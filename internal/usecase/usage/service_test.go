@@ -0,0 +1,47 @@
+package usage
+
+import (
+	"locail/internal/domain"
+	"locail/internal/ports"
+	"math"
+	"testing"
+)
+
+func TestPriceCost(t *testing.T) {
+	tests := []struct {
+		price Price
+		usage ports.Usage
+		want  float64
+	}{
+		{Price{Input: 2.5, Output: 10}, ports.Usage{InputTokens: 1_000_000, OutputTokens: 500_000}, 7.5},
+		{Price{Input: 0.15, Output: 0.6}, ports.Usage{InputTokens: 1200, OutputTokens: 300}, 0.00036},
+		{Price{Input: 3}, ports.Usage{OutputTokens: 1000}, 0},
+		{Price{}, ports.Usage{InputTokens: 1000, OutputTokens: 1000}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.price.Cost(tt.usage); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%+v.Cost(%+v) = %v, want %v", tt.price, tt.usage, got, tt.want)
+		}
+	}
+}
+
+func TestOptionPrice(t *testing.T) {
+	tests := []struct {
+		options string
+		want    Price
+		ok      bool
+	}{
+		{"", Price{}, false},
+		{`{"temperature":0.2}`, Price{}, false},
+		{`{"input_price":1.5,"output_price":6}`, Price{Input: 1.5, Output: 6}, true},
+		{`{"output_price":2}`, Price{Output: 2}, true},
+		{`{"input_price":0}`, Price{}, true}, // explicitly free
+		{`not json`, Price{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := optionPrice(&domain.Provider{OptionsRaw: tt.options})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("optionPrice(%s) = %+v, %v, want %+v, %v", tt.options, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	dbsqlite "locail/internal/adapters/db/sqlite"
@@ -8,19 +9,25 @@ import (
 	expjson "locail/internal/adapters/exporter/paraglidejson"
 	exportreg "locail/internal/adapters/exporter/registry"
 	expvdf "locail/internal/adapters/exporter/valvevdf"
+	"locail/internal/adapters/langdetect"
 	llmfactory "locail/internal/adapters/llm/factory"
+	llmregistry "locail/internal/adapters/llm/registry"
 	csvparser "locail/internal/adapters/parser/csv"
 	paraglidejson "locail/internal/adapters/parser/paraglidejson"
 	parreg "locail/internal/adapters/parser/registry"
 	valvevdf "locail/internal/adapters/parser/valvevdf"
 	promptRenderer "locail/internal/adapters/prompt"
+	"locail/internal/adapters/secrets"
 	apiapp "locail/internal/api/app"
 	"locail/internal/domain"
 	"locail/internal/ports"
 	exporterusecase "locail/internal/usecase/exporter"
+	glossaryusecase "locail/internal/usecase/glossary"
 	"locail/internal/usecase/importer"
 	jobsusecase "locail/internal/usecase/jobs"
+	tmusecase "locail/internal/usecase/tm"
 	translatorusecase "locail/internal/usecase/translator"
+	usageusecase "locail/internal/usecase/usage"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -42,11 +49,31 @@ func main() {
 	projectRepo := dbsqlite.NewProjectRepo(db)
 	fileRepo := dbsqlite.NewFileRepo(db)
 	unitRepo := dbsqlite.NewUnitRepo(db)
-	providerRepo := dbsqlite.NewProviderRepo(db)
 	templatesRepo := dbsqlite.NewTemplateRepo(db)
 	cacheRepo := dbsqlite.NewCacheRepo(db)
 	translationRepo := dbsqlite.NewTranslationRepo(db)
 	jobRepo := dbsqlite.NewJobRepo(db)
+	settingsRepo := dbsqlite.NewSettingsRepo(db)
+	tmRepo := dbsqlite.NewTMRepo(db)
+	glossaryRepo := dbsqlite.NewGlossaryRepo(db)
+	protectedRepo := dbsqlite.NewProtectedTermRepo(db)
+	styleRepo := dbsqlite.NewStyleGuideRepo(db)
+	usageRepo := dbsqlite.NewUsageRepo(db)
+	// API keys are sealed in the OS keyring or the passphrase vault; existing plaintext keys
+	// are migrated now in keyring mode, or on the first unlock otherwise
+	secretStore := secrets.NewStore(settingsRepo)
+	providerRepo := secrets.NewProviderRepo(dbsqlite.NewProviderRepo(db), secretStore)
+	if dberr == nil {
+		if _, err := providerRepo.SealPlaintext(context.Background()); err != nil {
+			println("Secrets Error:", err.Error())
+		}
+		if err := apiapp.LoadNetworkDefaults(context.Background(), settingsRepo); err != nil {
+			println("Network Settings Error:", err.Error())
+		}
+		if err := promptRenderer.SeedDefaults(context.Background(), templatesRepo); err != nil {
+			println("Templates Error:", err.Error())
+		}
+	}
 	fileAPI := apiapp.NewFileAPI(fileRepo)
 	unitAPI := apiapp.NewUnitAPI(unitRepo)
 
@@ -58,25 +85,52 @@ func main() {
 	parserRegistry.Register(csvparser.New())
 	importSvc := importer.New(fileRepo, unitRepo, parserRegistry)
 
+	// Translation memory
+	tmSvc := tmusecase.New(tmusecase.Deps{Memory: tmRepo, Units: unitRepo, Files: fileRepo, Projects: projectRepo})
+
 	// Prompt renderer and translator service
 	pr := promptRenderer.New(templatesRepo)
+	langDetector := langdetect.New()
+	buildProvider := func(p *domain.Provider) (ports.Provider, error) {
+		prov, ok := llmfactory.FromProvider(p)
+		if !ok {
+			return nil, fmt.Errorf("unsupported provider: %s", p.Type)
+		}
+		return prov, nil
+	}
+	validateOptions := func(providerType, options string) error {
+		d, ok := llmregistry.Lookup(providerType)
+		if !ok {
+			return fmt.Errorf("unsupported provider type %q", providerType)
+		}
+		return llmregistry.ValidateOptions(d, options)
+	}
 	transSvc := translatorusecase.New(translatorusecase.Deps{
-		Providers:    providerRepo,
-		Templates:    templatesRepo,
-		Cache:        cacheRepo,
-		Translations: translationRepo,
-		Prompt:       pr,
-		BuildProvider: func(p *domain.Provider) (ports.Provider, error) {
-			prov, ok := llmfactory.FromProvider(p)
-			if !ok {
-				return nil, fmt.Errorf("unsupported provider: %s", p.Type)
-			}
-			return prov, nil
-		},
+		Projects:      projectRepo,
+		Files:         fileRepo,
+		Units:         unitRepo,
+		Providers:     providerRepo,
+		Templates:     templatesRepo,
+		Cache:         cacheRepo,
+		Translations:  translationRepo,
+		Prompt:        pr,
+		Settings:      settingsRepo,
+		Detector:      langDetector,
+		Glossary:      glossaryRepo,
+		Protected:     protectedRepo,
+		Styles:        styleRepo,
+		Memory:        tmSvc,
+		BuildProvider: buildProvider,
 	})
 
+	// Token usage and cost accounting
+	usageSvc := usageusecase.New(usageusecase.Deps{Providers: providerRepo, Jobs: jobRepo, Usage: usageRepo, BuildProvider: buildProvider})
+
+	// Glossary
+	glossarySvc := glossaryusecase.New(glossaryusecase.Deps{Glossary: glossaryRepo, Projects: projectRepo, Units: unitRepo, Files: fileRepo})
+
 	// Job runner
-	runner := jobsusecase.NewRunner(jobsusecase.Deps{Jobs: jobRepo, Files: fileRepo, Units: unitRepo, Providers: providerRepo, Translations: translationRepo, Prompt: pr, Cache: cacheRepo}, transSvc)
+	runner := jobsusecase.NewRunner(jobsusecase.Deps{Jobs: jobRepo, Projects: projectRepo, Files: fileRepo, Units: unitRepo, Providers: providerRepo, Translations: translationRepo, Prompt: pr, Cache: cacheRepo, Detector: langDetector, Memory: tmSvc, Usage: usageSvc, ValidateOptions: validateOptions}, transSvc)
 	app.SetRunner(runner)
 
 	// Exporters and service
@@ -87,12 +141,21 @@ func main() {
 	expSvc := exporterusecase.New(fileRepo, unitRepo, translationRepo, expReg)
 
 	// API bindings
-	projectAPI := apiapp.NewProjectAPI(projectRepo)
+	projectAPI := apiapp.NewProjectAPIWithSettings(projectRepo, settingsRepo)
 	importAPI := apiapp.NewImportAPI(importSvc)
 	providerAPI := apiapp.NewProviderAPI(providerRepo)
 	jobsAPI := apiapp.NewJobsAPI(runner, jobRepo)
 	exportAPI := apiapp.NewExportAPI(expSvc)
-	translationsAPI := apiapp.NewTranslationsAPIWithUnits(translationRepo, unitRepo)
+	translationsAPI := apiapp.NewTranslationsAPIWithMemory(translationRepo, unitRepo, tmSvc)
+	cacheAPI := apiapp.NewCacheAPI(cacheRepo, settingsRepo)
+	tmAPI := apiapp.NewTMAPI(tmSvc, tmRepo)
+	glossaryAPI := apiapp.NewGlossaryAPI(glossarySvc, glossaryRepo)
+	protectedAPI := apiapp.NewProtectedTermsAPI(protectedRepo)
+	stylesAPI := apiapp.NewStyleGuidesAPI(styleRepo)
+	templatesAPI := apiapp.NewTemplatesAPI(templatesRepo, unitRepo, transSvc)
+	usageAPI := apiapp.NewUsageAPI(usageSvc)
+	secretsAPI := apiapp.NewSecretsAPI(secretStore, providerRepo)
+	networkAPI := apiapp.NewNetworkAPI(settingsRepo)
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -116,6 +179,15 @@ func main() {
 			jobsAPI,
 			exportAPI,
 			translationsAPI,
+			cacheAPI,
+			tmAPI,
+			glossaryAPI,
+			protectedAPI,
+			stylesAPI,
+			templatesAPI,
+			usageAPI,
+			secretsAPI,
+			networkAPI,
 		},
 	})
 